@@ -0,0 +1,490 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/word-killer/word-killer/data"
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/dict"
+	"github.com/word-killer/word-killer/pkg/game"
+	"github.com/word-killer/word-killer/pkg/history"
+	"github.com/word-killer/word-killer/pkg/paths"
+	"github.com/word-killer/word-killer/pkg/records"
+	"github.com/word-killer/word-killer/pkg/replay"
+)
+
+// options 启动游戏的命令行参数
+type options struct {
+	configPath string
+	mode       string // 为空时显示欢迎界面
+	words      int    // 覆盖经典/极速模式的单词数（0 表示使用配置）
+	duration   int    // 覆盖倒计时类模式的时长，单位秒（0 表示使用配置）
+	seed       int64
+	seedSet    bool // 是否显式指定了 --seed
+	dataDir    string
+	beatmap    string // 覆盖节奏舞蹈曲谱（空表示使用配置）
+}
+
+// dataPath 返回数据目录下的文件路径
+func (o options) dataPath(name string) string {
+	return filepath.Join(o.dataDir, name)
+}
+
+// apply 把命令行覆盖项写入配置
+func (o options) apply(cfg *config.Config) {
+	if o.words > 0 {
+		cfg.WordCount = o.words
+		cfg.SpeedRunWordCount = o.words
+	}
+	if o.duration > 0 {
+		cfg.CountdownDuration = o.duration
+		cfg.RhythmDanceDuration = o.duration
+	}
+	if o.beatmap != "" {
+		cfg.RhythmDanceBeatmapPath = o.beatmap
+	}
+}
+
+// run 解析命令行并执行，返回进程退出码
+func run(args []string, stdout, stderr io.Writer) int {
+	var err error
+	if len(args) > 0 {
+		switch args[0] {
+		case "stats":
+			err = runStats(args[1:], stdout)
+		case "records":
+			err = runRecords(args[1:], stdout)
+		case "validate-config":
+			err = runValidateConfig(args[1:], stdout)
+		case "replay":
+			err = runReplay(args[1:], stderr)
+		case "help":
+			printUsage(stdout)
+			return 0
+		default:
+			err = runPlay(args, stderr)
+		}
+	} else {
+		err = runPlay(args, stderr)
+	}
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+}
+
+// errUsage 参数错误（flag 包已经输出了错误信息）
+var errUsage = errors.New("invalid arguments")
+
+// newFlagSet 创建子命令的参数集，解析错误由调用方统一处理
+func newFlagSet(name string, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	return fs
+}
+
+// parseFlags 解析参数，把非 help 的错误转换为 errUsage
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.Output(), "unexpected argument: %s\n", fs.Arg(0))
+		return errUsage
+	}
+	return nil
+}
+
+// parsePlayFlags 解析启动游戏的参数
+func parsePlayFlags(args []string, output io.Writer) (options, error) {
+	var o options
+	fs := newFlagSet("word-killer", output)
+	fs.Usage = func() { printUsage(output) }
+	fs.StringVar(&o.configPath, "config", "", "config file path (default: $WORD_KILLER_CONFIG, then $XDG_CONFIG_HOME/word-killer/config.json)")
+	fs.StringVar(&o.mode, "mode", "", "start directly in a mode: "+strings.Join(game.ModeNames(), ", "))
+	fs.IntVar(&o.words, "words", 0, "word count for classic, speed run, practice, review and romanization modes")
+	fs.IntVar(&o.duration, "duration", 0, "duration in seconds for countdown, underwater and rhythm dance modes")
+	fs.Int64Var(&o.seed, "seed", 0, "random seed for the first round")
+	fs.StringVar(&o.dataDir, "data-dir", "", "directory for history, records, replays and dictionary packs (default: $XDG_DATA_HOME/word-killer)")
+	fs.StringVar(&o.beatmap, "beatmap", "", "beatmap file for rhythm dance mode (e.g. data/beatmap-demo.json)")
+	if err := parseFlags(fs, args); err != nil {
+		return o, err
+	}
+	o.dataDir = dataDirOrDefault(o.dataDir)
+
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			o.seedSet = true
+		}
+	})
+	if o.mode != "" {
+		if _, err := game.ParseMode(o.mode); err != nil {
+			return o, err
+		}
+	}
+	if o.words < 0 || o.duration < 0 {
+		return o, fmt.Errorf("--words and --duration must be positive")
+	}
+	return o, nil
+}
+
+// runPlay 启动游戏
+func runPlay(args []string, stderr io.Writer) error {
+	o, err := parsePlayFlags(args, stderr)
+	if err != nil {
+		return err
+	}
+	return play(o)
+}
+
+// runStats 输出历史记录统计：各模式汇总和每周进步
+func runStats(args []string, w io.Writer) error {
+	fs := newFlagSet("stats", w)
+	dataDir := fs.String("data-dir", "", "directory for history, records and replays")
+	mode := fs.String("mode", "", "only include one mode")
+	since := fs.String("since", "", "only include games finished on or after this date (YYYY-MM-DD)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	q := history.Query{Mode: *mode}
+	if *since != "" {
+		t, err := time.ParseInLocation("2006-01-02", *since, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --since date: %w", err)
+		}
+		q.Since = t
+	}
+
+	recs, err := history.Open(filepath.Join(dataDirOrDefault(*dataDir), historyFile)).Query(q)
+	if err != nil {
+		return err
+	}
+	if len(recs) == 0 {
+		fmt.Fprintln(w, "No games recorded yet.")
+		return nil
+	}
+
+	fmt.Fprintf(w, "Games: %d\n\n", len(recs))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Mode\tGames\tWords\tAvg L/s\tBest L/s\tAvg Acc\t")
+	for _, name := range game.ModeNames() {
+		modeRecs := history.Filter(recs, history.Query{Mode: name})
+		if len(modeRecs) == 0 {
+			continue
+		}
+		var words int
+		var lps, best, acc float64
+		for _, rec := range modeRecs {
+			words += rec.Stats.WordsCompleted
+			lps += rec.Stats.LettersPerSecond
+			acc += rec.Stats.AccuracyPercent
+			if rec.Stats.LettersPerSecond > best {
+				best = rec.Stats.LettersPerSecond
+			}
+		}
+		n := float64(len(modeRecs))
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%.2f\t%.1f%%\t\n", name, len(modeRecs), words, lps/n, best, acc/n)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "\nWeekly progress:")
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Week\tGames\tWords\tAvg L/s\tBest L/s\tAvg Acc\t")
+	for _, p := range history.WeeklyProgress(recs) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%.2f\t%.1f%%\t\n", p.Start.Format("2006-01-02"),
+			p.Games, p.TotalWords, p.AvgLettersPerSecond, p.BestLettersPerSecond, p.AvgAccuracy)
+	}
+	return tw.Flush()
+}
+
+// runRecords 输出个人最佳排行榜
+func runRecords(args []string, w io.Writer) error {
+	fs := newFlagSet("records", w)
+	dataDir := fs.String("data-dir", "", "directory for history, records and replays")
+	mode := fs.String("mode", "", "only show boards of one mode")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *mode != "" {
+		if _, err := game.ParseMode(*mode); err != nil {
+			return err
+		}
+	}
+
+	store, err := records.Load(filepath.Join(dataDirOrDefault(*dataDir), recordsFile))
+	if err != nil {
+		return err
+	}
+
+	shown := 0
+	for _, key := range store.Keys() {
+		if *mode != "" && key != *mode && !strings.HasPrefix(key, *mode+"/") {
+			continue
+		}
+		board, _ := store.Board(key)
+		if shown > 0 {
+			fmt.Fprintln(w)
+		}
+		shown++
+
+		fmt.Fprintf(w, "%s  [%s]\n", board.Label, key)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for i, e := range board.Entries {
+			fmt.Fprintf(tw, "  #%d\t%s %s\t%s\tacc %.1f%%\n", i+1, formatValue(e.Value, board.Unit),
+				board.Unit, e.Achieved.Local().Format("2006-01-02 15:04"), e.Accuracy)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if shown == 0 {
+		fmt.Fprintln(w, "No records yet.")
+	}
+	return nil
+}
+
+// formatValue 按单位格式化成绩
+func formatValue(v float64, unit string) string {
+	switch {
+	case unit == "s":
+		return fmt.Sprintf("%.3f", v)
+	case v == float64(int64(v)):
+		return fmt.Sprintf("%d", int64(v))
+	default:
+		return fmt.Sprintf("%.2f", v)
+	}
+}
+
+// runReplay 播放保存的回放文件
+// FILE 可以是路径，也可以是数据目录 replays/ 中的文件名
+func runReplay(args []string, w io.Writer) error {
+	fs := newFlagSet("replay", w)
+	dataDir := fs.String("data-dir", "", "directory for history, records and replays")
+	// 文件名可以写在参数前面或后面
+	var rest []string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		rest, args = args[:1], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	rest = append(rest, fs.Args()...)
+	if len(rest) != 1 {
+		fmt.Fprintln(w, "usage: word-killer replay [--data-dir DIR] FILE")
+		return errUsage
+	}
+	file := rest[0]
+
+	r, err := replay.Load(replayPath(file, dataDirOrDefault(*dataDir)))
+	if err != nil {
+		return err
+	}
+	m, err := newReplayModel(r, dataDirOrDefault(*dataDir))
+	if err != nil {
+		return fmt.Errorf("failed to start replay: %w", err)
+	}
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("failed to run: %w", err)
+	}
+	return nil
+}
+
+// replayPath 回放文件的路径：不存在的单独文件名在数据目录的 replays/ 中查找
+func replayPath(file, dataDir string) string {
+	if _, err := os.Stat(file); err == nil || strings.ContainsRune(file, os.PathSeparator) {
+		return file
+	}
+	return filepath.Join(dataDir, replayDir, file)
+}
+
+// newReplayModel 只播放一个回放的界面（replay 子命令）
+func newReplayModel(r *replay.Replay, dataDir string) (model, error) {
+	cfg := r.Config
+	m := initialModel(&cfg, game.New(), nil, nil, dataDir)
+	m, err := m.playReplay(r)
+	if err != nil {
+		return m, err
+	}
+	if player, err := newAudioPlayer(&cfg); err == nil {
+		m.audio = player
+	}
+	m.replayOnly = true
+	m.ready = true
+	return m, nil
+}
+
+// runValidateConfig 检查配置文件和它引用的词库
+func runValidateConfig(args []string, w io.Writer) error {
+	fs := newFlagSet("validate-config", w)
+	flagPath := fs.String("config", "", "config file path (default: search like the game does)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var problems []string
+	cfg, path, err := loadConfig(*flagPath)
+	var invalid *config.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		for _, fe := range invalid.Errors {
+			problems = append(problems, fe.Error())
+		}
+		cfg = &config.Config{} // 配置无效时不再检查词库
+	case err != nil:
+		return err
+	}
+
+	for _, d := range []struct {
+		name, path string
+		words      bool // 单词词库（纯文本或结构化格式）
+	}{
+		{"short_dict_path", cfg.ShortDictPath, true},
+		{"medium_dict_path", cfg.MediumDictPath, true},
+		{"long_dict_path", cfg.LongDictPath, true},
+		{"dictionary_path", cfg.DictionaryPath, true},
+		{"pack", cfg.Pack, true},
+		{"sentence_dict_path", cfg.SentenceDictPath, false},
+		{"romanization_dict_path", cfg.RomanizationDictPath, false},
+	} {
+		if d.path == "" {
+			continue
+		}
+		if !data.Exists(d.path) {
+			problems = append(problems, fmt.Sprintf("%s: %s not found", d.name, d.path))
+			continue
+		}
+		if d.words {
+			if err := checkDictionary(d.path); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", d.name, err))
+			}
+		}
+	}
+	if cfg.PacksDir != "" {
+		_, skipped, err := dict.Discover(cfg.PacksDir)
+		if err != nil {
+			skipped = append(skipped, err)
+		}
+		for _, err := range skipped {
+			problems = append(problems, fmt.Sprintf("packs_dir: %v", err))
+		}
+	}
+	if _, err := loadBeatmap(cfg.RhythmDanceBeatmapPath); err != nil {
+		problems = append(problems, fmt.Sprintf("rhythm_dance_beatmap_path: %v", err))
+	}
+
+	if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Fprintf(w, "  - %s\n", p)
+		}
+		return fmt.Errorf("%s: %d problem(s) found", path, len(problems))
+	}
+	fmt.Fprintf(w, "%s: OK\n", path)
+	return nil
+}
+
+// checkDictionary 检查单词词库的格式（结构化词库的表头、排名等）
+func checkDictionary(path string) error {
+	f, err := data.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := dict.Read(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// loadConfig 按搜索顺序加载配置，返回配置和它的来源（用于提示）
+// 显式指定的配置文件不存在时报错，否则回退到内置默认配置
+func loadConfig(flagPath string) (*config.Config, string, error) {
+	path, src := paths.FindConfig(flagPath)
+	if src == paths.SourceDefaults {
+		// 旧版本的配置在启动目录下：第一次运行新版本时迁移到 XDG 配置目录
+		migrated, err := paths.MigrateLegacyConfig()
+		if err != nil {
+			return nil, "", err
+		}
+		if migrated == "" {
+			return config.DefaultConfig(), "built-in defaults", nil
+		}
+		path = migrated
+	}
+	if _, err := os.Stat(path); err != nil && src.IsExplicit() {
+		return nil, path, fmt.Errorf("config file not found: %w", err)
+	}
+
+	cfg, err := config.Load(path)
+	return cfg, path, err
+}
+
+// settingsPath 设置界面保存配置的位置：已有配置文件时覆盖它，否则写到 XDG 配置目录
+func settingsPath(flagPath string) string {
+	path, src := paths.FindConfig(flagPath)
+	if src == paths.SourceDefaults {
+		return paths.DefaultConfigPath()
+	}
+	return path
+}
+
+// dataDirOrDefault 未指定数据目录时使用 XDG 数据目录
+func dataDirOrDefault(dir string) string {
+	if dir == "" {
+		return paths.DataDir()
+	}
+	return dir
+}
+
+// printUsage 输出命令行帮助
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, `Usage:
+  word-killer [flags]                 start the game
+  word-killer stats [flags]           show history statistics and weekly progress
+  word-killer records [flags]         show personal best leaderboards
+  word-killer validate-config [flags] check a config file and its dictionaries
+  word-killer replay [flags] FILE     watch a saved replay (a path, or a file name in the replays directory)
+
+Game flags:
+  --config PATH     config file (default: $WORD_KILLER_CONFIG, then
+                    $XDG_CONFIG_HOME/word-killer/config.json)
+  --mode NAME       start directly in a mode: %s
+  --words N         word count for classic, speed run, practice, review and romanization modes
+  --duration SEC    duration for countdown, underwater and rhythm dance modes
+  --seed N          random seed for the first round
+  --data-dir DIR    directory for history, records, replays and dictionary packs
+                    (default: $XDG_DATA_HOME/word-killer)
+  --beatmap PATH    beatmap file for rhythm dance mode (e.g. data/beatmap-demo.json)
+
+Run "word-killer <command> -h" for command flags.
+`, strings.Join(game.ModeNames(), ", "))
+}
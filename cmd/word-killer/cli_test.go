@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/game"
+	"github.com/word-killer/word-killer/pkg/history"
+	"github.com/word-killer/word-killer/pkg/records"
+	"github.com/word-killer/word-killer/pkg/replay"
+	"github.com/word-killer/word-killer/pkg/stats"
+)
+
+func TestParsePlayFlags(t *testing.T) {
+	var out bytes.Buffer
+	o, err := parsePlayFlags([]string{"--mode", "speedrun", "--words", "40", "--seed", "0", "--data-dir", "saves"}, &out)
+	if err != nil {
+		t.Fatalf("parsePlayFlags: %v", err)
+	}
+	if o.mode != "speedrun" || !o.seedSet || o.seed != 0 || o.dataPath(recordsFile) != filepath.Join("saves", recordsFile) {
+		t.Errorf("unexpected options: %+v", o)
+	}
+
+	cfg := config.DefaultConfig()
+	o.apply(cfg)
+	if cfg.SpeedRunWordCount != 40 || cfg.WordCount != 40 {
+		t.Errorf("--words not applied: %+v", cfg)
+	}
+
+	if _, err := parsePlayFlags([]string{"--mode", "zen"}, &out); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+	if code := run([]string{"--bogus"}, &out, &out); code != 2 {
+		t.Errorf("unknown flag exit code = %d, want 2", code)
+	}
+}
+
+func TestRecordsAndStatsCommands(t *testing.T) {
+	dir := t.TempDir()
+	rec := history.Record{
+		Mode:    "speedrun",
+		EndedAt: time.Now(),
+		Config:  *config.DefaultConfig(),
+		Stats:   stats.Snapshot{ElapsedSeconds: 31.5, WordsCompleted: 25, LettersPerSecond: 4, AccuracyPercent: 98},
+	}
+	if err := history.Open(filepath.Join(dir, historyFile)).Append(rec); err != nil {
+		t.Fatal(err)
+	}
+	store, _ := records.Load(filepath.Join(dir, recordsFile))
+	store.Submit(rec)
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if code := run([]string{"records", "--data-dir", dir, "--mode", "speedrun"}, &out, &out); code != 0 {
+		t.Fatalf("records exit code %d: %s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "31.500 s") {
+		t.Errorf("records output missing best time:\n%s", out.String())
+	}
+
+	out.Reset()
+	if code := run([]string{"stats", "--data-dir", dir}, &out, &out); code != 0 {
+		t.Fatalf("stats exit code %d: %s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "Games: 1") || !strings.Contains(out.String(), "speedrun") {
+		t.Errorf("stats output:\n%s", out.String())
+	}
+}
+
+func TestValidateConfigCommand(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.ShortDictPath = filepath.Join(dir, "missing.txt")
+	cfg.MediumDictPath = ""
+	cfg.LongDictPath = ""
+	cfg.ShortRatio, cfg.MediumRatio, cfg.LongRatio = 1, 0, 0
+	cfg.SentenceDictPath = ""
+	path := filepath.Join(dir, "config.json")
+	if err := config.Save(cfg, path); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if code := run([]string{"validate-config", "--config", path}, &out, &out); code != 1 {
+		t.Fatalf("exit code = %d, want 1", code)
+	}
+	if !strings.Contains(out.String(), "short_dict_path") {
+		t.Errorf("missing dictionary not reported:\n%s", out.String())
+	}
+
+	os.WriteFile(cfg.ShortDictPath, []byte("apple\n"), 0o644)
+	out.Reset()
+	if code := run([]string{"validate-config", "--config", path}, &out, &out); code != 0 {
+		t.Errorf("exit code = %d, want 0:\n%s", code, out.String())
+	}
+
+	// 结构化词库的格式错误
+	cfg.DictionaryPath = filepath.Join(dir, "words.tsv")
+	os.WriteFile(cfg.DictionaryPath, []byte("#word-killer-dict 1\nword\trank\napple\tfirst\n"), 0o644)
+	if err := config.Save(cfg, path); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if code := run([]string{"validate-config", "--config", path}, &out, &out); code != 1 || !strings.Contains(out.String(), "invalid rank") {
+		t.Errorf("bad rank not reported (exit code %d):\n%s", code, out.String())
+	}
+}
+
+func TestReplayCommandPlaysSavedFile(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SpeedRunWordCount = 3
+	clock := game.NewManualClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	g := game.NewWithClock(clock, 7)
+	if err := loadGame(g, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := startMode(g, cfg, game.ModeSpeedRun); err != nil {
+		t.Fatal(err)
+	}
+	for g.Status == game.StatusRunning {
+		for _, ch := range g.GetActiveWords()[0] {
+			clock.Advance(100 * time.Millisecond)
+			g.AddChar(ch)
+		}
+		g.TryEliminate()
+	}
+
+	dataDir := t.TempDir()
+	r := replay.FromGame(g, *cfg)
+	name := replay.FileName(r)
+	if err := r.Save(filepath.Join(dataDir, replayDir, name)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 单独的文件名在数据目录的 replays/ 中查找
+	path := replayPath(name, dataDir)
+	if path != filepath.Join(dataDir, replayDir, name) {
+		t.Errorf("replayPath = %q", path)
+	}
+	loaded, err := replay.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := newReplayModel(loaded, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 播放结束后停在最后一帧，按键退出
+	m.player.RunToEnd()
+	updated, _ := m.Update(tickMsg(time.Now()))
+	m = updated.(model)
+	if m.player == nil || m.game.Status != game.StatusFinished {
+		t.Fatalf("replay should stay on the last frame (status %v)", m.game.Status)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil || cmd() != tea.Quit() {
+		t.Error("q should quit the replay command")
+	}
+
+	if code := run([]string{"replay", "--data-dir", dataDir}, &bytes.Buffer{}, &bytes.Buffer{}); code == 0 {
+		t.Error("replay without a file should fail")
+	}
+}
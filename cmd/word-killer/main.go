@@ -1,14 +1,23 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"time"
+	"unicode"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/word-killer/word-killer/data"
+	"github.com/word-killer/word-killer/pkg/audio"
+	"github.com/word-killer/word-killer/pkg/beatmap"
 	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/dict"
 	"github.com/word-killer/word-killer/pkg/game"
+	"github.com/word-killer/word-killer/pkg/history"
+	"github.com/word-killer/word-killer/pkg/records"
+	"github.com/word-killer/word-killer/pkg/replay"
+	"github.com/word-killer/word-killer/pkg/review"
 	"github.com/word-killer/word-killer/pkg/ui"
 )
 
@@ -18,29 +27,59 @@ type tickMsg time.Time
 // model is the Bubble Tea model
 type model struct {
 	game             *game.Game
-	cfg              *config.Config
+	cfg              *config.Config // 运行时配置（含命令行覆盖项）
+	fileCfg          *config.Config // 配置文件中的配置（不含命令行覆盖项），设置界面以它为草稿并保存
+	cli              options        // 命令行参数（保存设置后重新应用覆盖项）
 	ready            bool
-	showModeSelect   bool // true when showing mode selection screen
-	showAbout        bool // true when showing about page
-	selectedMode     int  // 0=经典, 1=句子, 2=倒计时, 3=极速, 4=节奏大师, 5=水下倒计时, 6=节奏舞蹈
+	showModeSelect   bool        // true when showing mode selection screen
+	showAbout        bool        // true when showing about page
+	selectedMode     int         // 0=经典, 1=句子, 2=倒计时, 3=极速, 4=节奏大师, 5=水下倒计时, 6=节奏舞蹈, 7=自适应练习, 8=间隔复习, 9=罗马字
+	modeNotice       string      // 模式选择界面的提示（如没有到期的复习单词）
+	packs            []dict.Pack // 词库包目录中的词库包（打开模式选择界面时扫描）
 	width            int
 	height           int
 	animFrame        int                       // animation frame counter for pause menu
 	welcomeAnimState *ui.WelcomeAnimationState // welcome screen animation state
-	// 极速模式专用
-	speedRunBestTime float64 // 最佳时间（秒），从文件加载
-	tickCount        int     // tick 计数器，用于控制游戏逻辑更新频率
+	tickCount        int                       // tick 计数器，用于控制游戏逻辑更新频率
+	// 历史记录和个人最佳
+	history        *history.Store
+	records        *records.Store
+	resultRecorded bool          // 当前结束的对局是否已写入历史
+	lastRecord     ui.RecordInfo // 当前结束对局的排行榜名次
+	keys           *ui.KeyReport // 结算后的按键分析界面（nil 表示未打开）
+	review         *review.Store // 间隔复习队列（nil 表示不可用）
+	dataDir        string        // 历史、排行榜和回放的保存目录
+	// 回放
+	lastReplay  *replay.Replay // 当前结束对局的回放
+	player      *replay.Player // 正在播放的回放（nil 表示未在回放）
+	liveGame    *game.Game     // 回放期间暂存的真实游戏
+	replayOnly  bool           // 由 replay 子命令启动：回放结束后停在最后一帧，按键退出程序
+	replayStart time.Time      // 回放开始的时间
+	ghost       *replay.Ghost  // 极速模式的幽灵对手（最佳成绩的回放）
+	// 设置
+	settings   *settingsState // 设置界面状态（nil 表示未打开）
+	configPath string         // 设置保存到的配置文件
+	// 声音提示
+	audio audio.Player
+	cues  *audio.Tracker
 }
 
-func initialModel(cfg *config.Config, g *game.Game) model {
+func initialModel(cfg *config.Config, g *game.Game, store *history.Store, best *records.Store, dataDir string) model {
+	fileCfg := *cfg
 	return model{
 		game:             g,
 		cfg:              cfg,
+		fileCfg:          &fileCfg,
+		history:          store,
+		records:          best,
+		dataDir:          dataDir,
 		ready:            false,
 		showModeSelect:   false,
 		showAbout:        false,
 		selectedMode:     0,
 		welcomeAnimState: &ui.WelcomeAnimationState{},
+		audio:            audio.Null{},
+		cues:             audio.NewTracker(),
 	}
 }
 
@@ -57,11 +96,18 @@ func tickCmd() tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		return m.handleKey(msg)
+		updated, cmd := m.handleKey(msg)
+		m = updated.(model)
+		m.playCues()
+		return m.recordFinishedGame(), cmd
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		// 终端太小时看不到游戏画面，自动暂停
+		if m.viewport().TooSmall() && m.ready && m.player == nil && m.game.Status == game.StatusRunning {
+			m.game.Pause()
+		}
 		return m, nil
 
 	case tickMsg:
@@ -69,25 +115,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.tickCount++
 		m.animFrame++
 
+		// 回放中：按时间推进回放，不运行真实游戏逻辑
+		if m.player != nil {
+			m.player.AdvanceTo(time.Since(m.replayStart))
+			m.playCues()
+			if m.player.Done() && !m.replayOnly {
+				m = m.stopReplay()
+			}
+			return m, tickCmd()
+		}
+
 		// Update game logic only every 3 ticks (~100ms, 10 updates/second)
 		// This keeps animation speeds the same while rendering at 30 FPS
 		if m.tickCount%3 == 0 {
 			// Update welcome animation if on welcome screen
-			if !m.ready && !m.showModeSelect && !m.showAbout {
-				ui.UpdateWelcomeAnimation(m.welcomeAnimState)
+			if !m.ready && !m.showModeSelect && !m.showAbout && m.settings == nil {
+				ui.UpdateWelcomeAnimation(m.viewport(), m.welcomeAnimState)
 			}
 
-			// Update underwater animations if in underwater mode
-			if m.ready && m.game.Mode == game.ModeUnderwaterCountdown && m.game.Status == game.StatusRunning {
-				m.game.UpdateFishPositions()
-				m.game.UpdateBackgroundAnimation()
-				m.game.UpdateCountdown()
-			}
-
-			// Update rhythm dance animations if in rhythm dance mode
-			if m.ready && m.game.Mode == game.ModeRhythmDance && m.game.Status == game.StatusRunning {
-				m.game.UpdateRhythmPointer()
-				m.game.UpdateDanceAnimation()
+			// Update underwater / rhythm dance animations
+			if m.ready {
+				m.game.Tick()
 			}
 		}
 
@@ -96,14 +144,43 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.game.CheckTimeouts()
 		}
 
+		m.playCues()
+
 		// Always return tick command to keep animation running at 30 FPS
-		return m, tickCmd()
+		return m.recordFinishedGame(), tickCmd()
 	}
 
 	return m, nil
 }
 
 func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Terminal too small: only quitting is possible
+	if m.viewport().TooSmall() {
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	// Replay: any of these keys stops playback
+	if m.player != nil {
+		switch msg.String() {
+		case "esc", "enter", "q":
+			if m.replayOnly {
+				return m, tea.Quit
+			}
+			return m.stopReplay(), nil
+		case "ctrl+c":
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	// Settings screen
+	if !m.ready && m.settings != nil {
+		return m.handleSettingsKey(msg)
+	}
+
 	// About screen
 	if !m.ready && m.showAbout {
 		switch msg.String() {
@@ -119,20 +196,22 @@ func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if !m.ready && !m.showModeSelect && !m.showAbout {
 		switch msg.String() {
 		case "up", "k":
-			// Move selection up (3 options now)
-			m.welcomeAnimState.SelectedOption = (m.welcomeAnimState.SelectedOption - 1 + 3) % 3
+			// Move selection up (4 options: Start, Settings, About, Quit)
+			m.welcomeAnimState.SelectedOption = (m.welcomeAnimState.SelectedOption - 1 + 4) % 4
 			return m, nil
 		case "down", "j":
-			// Move selection down (3 options now)
-			m.welcomeAnimState.SelectedOption = (m.welcomeAnimState.SelectedOption + 1) % 3
+			// Move selection down
+			m.welcomeAnimState.SelectedOption = (m.welcomeAnimState.SelectedOption + 1) % 4
 			return m, nil
 		case "enter":
 			// Confirm selection
 			if m.welcomeAnimState.SelectedOption == 0 {
 				// Start selected - show mode selection
-				m.showModeSelect = true
-				m.selectedMode = 0
+				m = m.openModeSelect()
 			} else if m.welcomeAnimState.SelectedOption == 1 {
+				// Settings selected
+				m.settings = newSettings(m.fileCfg)
+			} else if m.welcomeAnimState.SelectedOption == 2 {
 				// About selected
 				m.showAbout = true
 			} else {
@@ -150,42 +229,37 @@ func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if !m.ready && m.showModeSelect {
 		switch msg.String() {
 		case "up", "k":
-			// Move selection up（现在有7个模式）
-			m.selectedMode = (m.selectedMode - 1 + 7) % 7
+			// Move selection up（现在有10个模式）
+			m.selectedMode = (m.selectedMode - 1 + 10) % 10
+			m.modeNotice = ""
 			return m, nil
 		case "down", "j":
 			// Move selection down
-			m.selectedMode = (m.selectedMode + 1) % 7
+			m.selectedMode = (m.selectedMode + 1) % 10
+			m.modeNotice = ""
 			return m, nil
+		case "left", "h":
+			// 切换词库包
+			return m.cyclePack(-1), nil
+		case "right", "l":
+			return m.cyclePack(1), nil
 		case "enter":
 			// Start game with selected mode
-			var err error
-			switch m.selectedMode {
-			case 0:
-				// 经典模式
-				err = m.game.Start(m.cfg.WordCount)
-			case 1:
-				// 句子模式
-				err = m.game.StartSentenceMode()
-			case 2:
-				// 倒计时模式 - 从配置读取时长
-				err = m.game.StartCountdownMode(time.Duration(m.cfg.CountdownDuration) * time.Second)
-			case 3:
-				// 极速模式 - 从配置读取单词数
-				err = m.game.StartSpeedRunMode(m.cfg.SpeedRunWordCount)
-				// 加载最佳时间记录
-				m.speedRunBestTime = loadSpeedRunBestTime()
-			case 4:
-				// 节奏大师模式
-				err = m.game.StartRhythmMasterMode()
-			case 5:
-				// 水下倒计时模式
-				err = m.game.StartUnderwaterCountdown(m.cfg.CountdownDuration)
-			case 6:
-				// 节奏舞蹈模式
-				err = m.game.StartRhythmDanceMode(m.cfg.RhythmDanceDuration, m.cfg.RhythmDanceInitialSpeed, m.cfg.RhythmDanceSpeedIncrement)
+			// 模式列表顺序与 game.GameMode 保持一致
+			mode := game.GameMode(m.selectedMode)
+			if mode == game.ModeReview {
+				// 没有到期单词时留在模式选择界面
+				if m.modeNotice = m.reviewNotice(); m.modeNotice != "" {
+					return m, nil
+				}
+			}
+			if mode == game.ModeRomanization && !m.game.HasRomanDictionary() {
+				// 词库加载失败时留在模式选择界面
+				m.modeNotice = "No romanization dictionary - check romanization_dict_path"
+				return m, nil
 			}
-			if err != nil {
+			var err error
+			if m, err = m.start(mode); err != nil {
 				return m, tea.Quit
 			}
 			m.ready = true
@@ -193,6 +267,7 @@ func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "esc":
 			// Go back to welcome screen
 			m.showModeSelect = false
+			m.modeNotice = ""
 			return m, nil
 		case "ctrl+c":
 			return m, tea.Quit
@@ -216,27 +291,9 @@ func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "backspace":
 			m.game.Backspace()
 		default:
-			// Handle input based on game mode
-			runes := []rune(msg.String())
-			if len(runes) == 1 {
-				r := runes[0]
-				// 节奏舞蹈模式：空格键触发判定
-				if m.game.Mode == game.ModeRhythmDance && r == ' ' {
-					m.game.TryRhythmJudgment()
-				} else if m.game.Mode == game.ModeSentence {
-					// Sentence mode: accept all printable characters
-					if r >= 32 && r <= 126 {
-						m.game.AddChar(r)
-					}
-				} else {
-					// Classic mode: only letters
-					if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
-						if r >= 'A' && r <= 'Z' {
-							r = r + 32
-						}
-						m.game.AddChar(r)
-					}
-				}
+			// 输入法可能一次提交多个字符，逐个处理
+			for _, r := range typedRunes(msg) {
+				m.typeRune(r)
 			}
 		}
 
@@ -261,27 +318,10 @@ func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.game.Resume()
 			} else if idx == 1 {
 				// Restart - same mode
-				switch m.game.Mode {
-				case game.ModeSentence:
-					m.game.StartSentenceMode()
-				case game.ModeCountdown:
-					m.game.StartCountdownMode(time.Duration(m.cfg.CountdownDuration) * time.Second)
-				case game.ModeSpeedRun:
-					m.game.StartSpeedRunMode(m.cfg.SpeedRunWordCount)
-				case game.ModeRhythmMaster:
-					m.game.StartRhythmMasterMode()
-				case game.ModeUnderwaterCountdown:
-					m.game.StartUnderwaterCountdown(m.cfg.CountdownDuration)
-				case game.ModeRhythmDance:
-					m.game.StartRhythmDanceMode(m.cfg.RhythmDanceDuration, m.cfg.RhythmDanceInitialSpeed, m.cfg.RhythmDanceSpeedIncrement)
-				default:
-					m.game.Start(m.cfg.WordCount)
-				}
+				m, _ = m.start(m.game.Mode)
 			} else if idx == 2 {
 				// Select Mode - go back to mode selection
-				m.ready = false
-				m.showModeSelect = true
-				m.selectedMode = 0
+				m = m.openModeSelect()
 			} else if idx == 3 {
 				// Main Menu - go back to welcome
 				m.ready = false
@@ -294,7 +334,20 @@ func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	} else if m.game.Status == game.StatusFinished {
-		// Results page - 3 options: Restart, Select Mode, Main Menu
+		// Key analysis screen opened from the results page
+		if m.keys != nil {
+			switch msg.String() {
+			case "tab":
+				m.keys = m.keyReport(!m.keys.AllTime)
+			case "esc", "enter", "h", "q":
+				m.keys = nil
+			case "ctrl+c":
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
+		// Results page - 4 options: Restart, Watch Replay, Select Mode, Main Menu
 		switch msg.String() {
 		case "up", "k":
 			m.game.MoveResultsMenu(-1)
@@ -304,34 +357,22 @@ func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			idx := m.game.ResultsMenuIndex
 			if idx == 0 {
 				// Restart - same mode
-				switch m.game.Mode {
-				case game.ModeSentence:
-					m.game.StartSentenceMode()
-				case game.ModeCountdown:
-					m.game.StartCountdownMode(time.Duration(m.cfg.CountdownDuration) * time.Second)
-				case game.ModeSpeedRun:
-					m.game.StartSpeedRunMode(m.cfg.SpeedRunWordCount)
-				case game.ModeRhythmMaster:
-					m.game.StartRhythmMasterMode()
-				case game.ModeUnderwaterCountdown:
-					m.game.StartUnderwaterCountdown(m.cfg.CountdownDuration)
-				case game.ModeRhythmDance:
-					m.game.StartRhythmDanceMode(m.cfg.RhythmDanceDuration, m.cfg.RhythmDanceInitialSpeed, m.cfg.RhythmDanceSpeedIncrement)
-				default:
-					m.game.Start(m.cfg.WordCount)
-				}
+				m, _ = m.start(m.game.Mode)
 			} else if idx == 1 {
-				// Select Mode - go back to mode selection
-				m.ready = false
-				m.showModeSelect = true
-				m.selectedMode = 0
+				// Watch Replay
+				return m.startReplay(), nil
 			} else if idx == 2 {
+				// Select Mode - go back to mode selection
+				m = m.openModeSelect()
+			} else if idx == 3 {
 				// Main Menu - go back to welcome
 				m.ready = false
 				m.showModeSelect = false
 				m.showAbout = false
 				m.welcomeAnimState.SelectedOption = 0
 			}
+		case "h":
+			m.keys = m.keyReport(false)
 		case "esc", "ctrl+c":
 			return m, tea.Quit
 		}
@@ -341,20 +382,46 @@ func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// viewport 当前终端大小（尚未收到 WindowSizeMsg 时为零值，按默认布局渲染）
+func (m model) viewport() ui.Viewport {
+	return ui.Viewport{Width: m.width, Height: m.height}
+}
+
 func (m model) View() string {
+	vp := m.viewport()
+	if vp.TooSmall() {
+		return ui.RenderTooSmall(vp)
+	}
+
+	// 回放中：在画面顶部显示回放进度
+	if m.player != nil {
+		r := m.player.Replay()
+		banner := ui.RenderReplayBanner(vp, r.Mode, time.Since(m.replayStart), r.Duration())
+		return banner + "\n" + m.screen(vp.Shrink(lipgloss.Height(banner)))
+	}
+	return m.screen(vp)
+}
+
+// screen 渲染当前界面
+func (m model) screen(vp ui.Viewport) string {
+	// Settings screen
+	if !m.ready && m.settings != nil {
+		return m.renderSettings(vp)
+	}
+
 	// About screen
 	if !m.ready && m.showAbout {
-		return ui.RenderAbout()
+		return ui.RenderAbout(vp)
 	}
 
 	// Welcome screen
 	if !m.ready && !m.showModeSelect {
-		return ui.RenderWelcome(m.welcomeAnimState, m.animFrame)
+		return ui.RenderWelcome(vp, m.welcomeAnimState, m.animFrame)
 	}
 
 	// Mode selection screen
 	if !m.ready && m.showModeSelect {
-		return ui.RenderModeSelection(m.selectedMode, m.animFrame)
+		return ui.RenderModeSelection(vp, m.selectedMode, m.animFrame, m.modeNotice, m.packPicker())
 	}
 
 	if m.game.Status == game.StatusRunning {
@@ -362,21 +429,11 @@ func (m model) View() string {
 		switch m.game.Mode {
 		case game.ModeUnderwaterCountdown:
 			// Underwater countdown mode rendering
-			return ui.RenderUnderwaterGame(m.game)
+			return ui.RenderUnderwaterGame(vp, m.game)
 		case game.ModeSentence:
 			// Sentence mode rendering
-			stats := ui.GameStats{
-				TotalKeystrokes:  m.game.Stats.TotalKeystrokes,
-				ValidKeystrokes:  m.game.Stats.ValidKeystrokes,
-				CorrectChars:     m.game.Stats.CorrectChars,
-				WordsCompleted:   m.game.Stats.WordsCompleted,
-				TotalLetters:     m.game.Stats.TotalLetters,
-				ElapsedSeconds:   m.game.Stats.GetElapsedSeconds(),
-				LettersPerSecond: m.game.Stats.GetLettersPerSecond(),
-				WordsPerSecond:   m.game.Stats.GetWordsPerSecond(),
-				AccuracyPercent:  m.game.Stats.GetAccuracyPercent(),
-			}
-			return ui.RenderSentenceGame(m.game.TargetSentence, m.game.InputBuffer, stats)
+			stats := gameStats(m.game)
+			return ui.RenderSentenceGame(vp, m.game.TargetSentence, m.game.InputBuffer, stats)
 
 		case game.ModeCountdown:
 			// 倒计时模式渲染
@@ -390,52 +447,45 @@ func (m model) View() string {
 				}
 			}
 			highlighted := m.game.GetMatchedIndices()
-			stats := ui.GameStats{
-				TotalKeystrokes:  m.game.Stats.TotalKeystrokes,
-				ValidKeystrokes:  m.game.Stats.ValidKeystrokes,
-				CorrectChars:     m.game.Stats.CorrectChars,
-				WordsCompleted:   m.game.Stats.WordsCompleted,
-				TotalLetters:     m.game.Stats.TotalLetters,
-				ElapsedSeconds:   m.game.Stats.GetElapsedSeconds(),
-				LettersPerSecond: m.game.Stats.GetLettersPerSecond(),
-				WordsPerSecond:   m.game.Stats.GetWordsPerSecond(),
-				AccuracyPercent:  m.game.Stats.GetAccuracyPercent(),
-			}
-			elapsed := time.Since(m.game.CountdownStartTime)
-			remaining := m.game.CountdownDuration - elapsed
-			remainingSec := remaining.Seconds()
-			if remainingSec < 0 {
-				remainingSec = 0
-			}
-			return ui.RenderCountdownGame(wordInfos, highlighted, m.game.InputBuffer, stats,
+			stats := gameStats(m.game)
+			remainingSec := m.game.GetCountdownRemaining().Seconds()
+			return ui.RenderCountdownGame(vp, wordInfos, highlighted, m.game.InputBuffer, stats,
 				remainingSec, m.game.CountdownDuration.Seconds())
 
 		case game.ModeSpeedRun:
 			// 极速模式渲染
 			allWords := m.game.GetAllWords()
 			wordInfos := make([]ui.WordInfo, len(allWords))
+			ghost, ghostDone := m.ghostProgress()
 			for i, w := range allWords {
 				wordInfos[i] = ui.WordInfo{
 					Text:        w.Text,
 					Completed:   w.Completed,
 					CompletedAt: w.CompletedAt,
+					Ghosted:     ghostDone[w.Text],
 				}
 			}
 			highlighted := m.game.GetMatchedIndices()
-			stats := ui.GameStats{
-				TotalKeystrokes:  m.game.Stats.TotalKeystrokes,
-				ValidKeystrokes:  m.game.Stats.ValidKeystrokes,
-				CorrectChars:     m.game.Stats.CorrectChars,
-				WordsCompleted:   m.game.Stats.WordsCompleted,
-				TotalLetters:     m.game.Stats.TotalLetters,
-				ElapsedSeconds:   m.game.Stats.GetElapsedSeconds(),
-				LettersPerSecond: m.game.Stats.GetLettersPerSecond(),
-				WordsPerSecond:   m.game.Stats.GetWordsPerSecond(),
-				AccuracyPercent:  m.game.Stats.GetAccuracyPercent(),
+			stats := gameStats(m.game)
+			currentTime := m.game.PlayTime().Seconds()
+			return ui.RenderSpeedRunGame(vp, wordInfos, highlighted, m.game.InputBuffer, stats,
+				currentTime, m.bestValue(game.ModeSpeedRun), ghost)
+
+		case game.ModePractice:
+			// 自适应练习模式渲染
+			allWords := m.game.GetAllWords()
+			wordInfos := make([]ui.WordInfo, len(allWords))
+			for i, w := range allWords {
+				wordInfos[i] = ui.WordInfo{
+					Text:        w.Text,
+					Completed:   w.Completed,
+					CompletedAt: w.CompletedAt,
+				}
 			}
-			currentTime := time.Since(m.game.SpeedRunStartTime).Seconds()
-			return ui.RenderSpeedRunGame(wordInfos, highlighted, m.game.InputBuffer, stats,
-				currentTime, m.speedRunBestTime)
+			highlighted := m.game.GetMatchedIndices()
+			stats := gameStats(m.game)
+			return ui.RenderPracticeGame(vp, wordInfos, highlighted, m.game.InputBuffer, stats,
+				m.game.PracticeTarget, m.game.PracticeFocus.String())
 
 		case game.ModeRhythmMaster:
 			// 节奏大师模式渲染
@@ -449,24 +499,9 @@ func (m model) View() string {
 				}
 			}
 			highlighted := m.game.GetMatchedIndices()
-			stats := ui.GameStats{
-				TotalKeystrokes:  m.game.Stats.TotalKeystrokes,
-				ValidKeystrokes:  m.game.Stats.ValidKeystrokes,
-				CorrectChars:     m.game.Stats.CorrectChars,
-				WordsCompleted:   m.game.Stats.WordsCompleted,
-				TotalLetters:     m.game.Stats.TotalLetters,
-				ElapsedSeconds:   m.game.Stats.GetElapsedSeconds(),
-				LettersPerSecond: m.game.Stats.GetLettersPerSecond(),
-				WordsPerSecond:   m.game.Stats.GetWordsPerSecond(),
-				AccuracyPercent:  m.game.Stats.GetAccuracyPercent(),
-			}
-			wordElapsed := time.Since(m.game.CurrentWordStartTime)
-			wordRemaining := m.game.WordTimeLimit - wordElapsed
-			wordRemainingSec := wordRemaining.Seconds()
-			if wordRemainingSec < 0 {
-				wordRemainingSec = 0
-			}
-			return ui.RenderRhythmMasterGame(wordInfos, highlighted, m.game.InputBuffer, stats,
+			stats := gameStats(m.game)
+			wordRemainingSec := m.game.GetWordRemaining().Seconds()
+			return ui.RenderRhythmMasterGame(vp, wordInfos, highlighted, m.game.InputBuffer, stats,
 				wordRemainingSec, m.game.WordTimeLimit.Seconds(),
 				m.game.ConsecutiveSuccesses, m.game.DifficultyLevel)
 
@@ -484,10 +519,14 @@ func (m model) View() string {
 			rhythmBar := ui.RhythmBarInfo{
 				PointerPosition: state.PointerPosition,
 				GoldenRatio:     state.GoldenRatio,
+				PerfectZone:     state.ZoneWidth(state.Rules.Windows.Perfect),
+				NiceZone:        state.ZoneWidth(state.Rules.Windows.Nice),
+				OKZone:          state.ZoneWidth(state.Rules.Windows.OK),
 			}
 
 			// 构建统计信息
 			stats := ui.RhythmDanceStats{
+				Song:            state.Song(),
 				RemainingTime:   m.game.GetRhythmRemainingTime(),
 				CompletedWords:  state.CompletedWords,
 				TotalScore:      state.TotalScore,
@@ -503,6 +542,7 @@ func (m model) View() string {
 			}
 
 			return ui.RenderRhythmDanceGame(
+				vp,
 				danceFrame,
 				state.WordQueue,
 				state.CurrentWordIndex,
@@ -512,6 +552,23 @@ func (m model) View() string {
 				judgmentEffect,
 			)
 
+		case game.ModeRomanization:
+			// 罗马字模式渲染：单词上方显示中日文
+			allWords := m.game.GetAllWords()
+			wordInfos := make([]ui.WordInfo, len(allWords))
+			for i, w := range allWords {
+				wordInfos[i] = ui.WordInfo{
+					Text:        w.Text,
+					Completed:   w.Completed,
+					CompletedAt: w.CompletedAt,
+					Target:      w.Target,
+					Syllables:   w.Syllables,
+				}
+			}
+			highlighted := m.game.GetMatchedIndices()
+			stats := gameStats(m.game)
+			return ui.RenderRomanizationGame(vp, wordInfos, highlighted, m.game.InputBuffer, stats, len(m.game.GetActiveWords()))
+
 		default:
 			// Classic mode rendering (existing code)
 			// Get all words (including completed ones)
@@ -528,57 +585,20 @@ func (m model) View() string {
 			highlighted := m.game.GetMatchedIndices()
 			activeWords := m.game.GetActiveWords()
 
-			stats := ui.GameStats{
-				TotalKeystrokes:  m.game.Stats.TotalKeystrokes,
-				ValidKeystrokes:  m.game.Stats.ValidKeystrokes,
-				CorrectChars:     m.game.Stats.CorrectChars,
-				WordsCompleted:   m.game.Stats.WordsCompleted,
-				TotalLetters:     m.game.Stats.TotalLetters,
-				ElapsedSeconds:   m.game.Stats.GetElapsedSeconds(),
-				LettersPerSecond: m.game.Stats.GetLettersPerSecond(),
-				WordsPerSecond:   m.game.Stats.GetWordsPerSecond(),
-				AccuracyPercent:  m.game.Stats.GetAccuracyPercent(),
-			}
+			stats := gameStats(m.game)
 
-			return ui.RenderGame(wordInfos, highlighted, m.game.InputBuffer, stats, len(activeWords))
+			return ui.RenderGame(vp, wordInfos, highlighted, m.game.InputBuffer, stats, len(activeWords))
 		}
 	} else if m.game.Status == game.StatusPaused {
 		// Pass stats and animation frame to pause menu
 		activeWords := m.game.GetActiveWords()
-		stats := ui.GameStats{
-			TotalKeystrokes:  m.game.Stats.TotalKeystrokes,
-			ValidKeystrokes:  m.game.Stats.ValidKeystrokes,
-			CorrectChars:     m.game.Stats.CorrectChars,
-			WordsCompleted:   m.game.Stats.WordsCompleted,
-			TotalLetters:     m.game.Stats.TotalLetters,
-			ElapsedSeconds:   m.game.Stats.GetElapsedSeconds(),
-			LettersPerSecond: m.game.Stats.GetLettersPerSecond(),
-			WordsPerSecond:   m.game.Stats.GetWordsPerSecond(),
-			AccuracyPercent:  m.game.Stats.GetAccuracyPercent(),
-		}
-		return ui.RenderPauseMenu(m.game.PauseMenuIndex, stats, len(activeWords), m.animFrame)
+		stats := gameStats(m.game)
+		return ui.RenderPauseMenu(vp, m.game.PauseMenuIndex, stats, len(activeWords), m.animFrame)
 	} else if m.game.Status == game.StatusFinished {
-		stats := ui.GameStats{
-			TotalKeystrokes:  m.game.Stats.TotalKeystrokes,
-			ValidKeystrokes:  m.game.Stats.ValidKeystrokes,
-			CorrectChars:     m.game.Stats.CorrectChars,
-			WordsCompleted:   m.game.Stats.WordsCompleted,
-			TotalLetters:     m.game.Stats.TotalLetters,
-			ElapsedSeconds:   m.game.Stats.GetElapsedSeconds(),
-			LettersPerSecond: m.game.Stats.GetLettersPerSecond(),
-			WordsPerSecond:   m.game.Stats.GetWordsPerSecond(),
-			AccuracyPercent:  m.game.Stats.GetAccuracyPercent(),
-		}
-
-		// 如果是极速模式且未中止，检查是否创造新记录
-		if m.game.Mode == game.ModeSpeedRun && !m.game.Aborted {
-			completionTime := m.game.Stats.GetElapsedSeconds()
-			if m.speedRunBestTime == 0 || completionTime < m.speedRunBestTime {
-				// 新记录！
-				saveSpeedRunBestTime(completionTime)
-				m.speedRunBestTime = completionTime
-			}
+		if m.keys != nil {
+			return ui.RenderKeyAnalysis(vp, *m.keys)
 		}
+		stats := gameStats(m.game)
 
 		// 如果是节奏舞蹈模式，渲染专用结果界面
 		if m.game.Mode == game.ModeRhythmDance && m.game.RhythmDanceState != nil {
@@ -593,36 +613,208 @@ func (m model) View() string {
 				NiceCount:      state.NiceCount,
 				OKCount:        state.OKCount,
 				MissCount:      state.MissCount,
+				NetWPM:         stats.NetWPM,
+				RawWPM:         stats.RawWPM,
+				Consistency:    stats.Consistency,
 			}
-			return ui.RenderRhythmDanceResults(rhythmStats, m.game.ResultsMenuIndex, m.animFrame)
+			return ui.RenderRhythmDanceResults(vp, rhythmStats, m.game.ResultsMenuIndex, m.animFrame, m.lastRecord)
 		}
 
-		return ui.RenderResults(stats, m.game.Aborted, m.game.ResultsMenuIndex, m.animFrame)
+		return ui.RenderResults(vp, stats, m.game.Aborted, m.game.ResultsMenuIndex, m.animFrame, m.lastRecord)
 	}
 
 	return ""
 }
 
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// play 按命令行参数启动游戏界面
+func play(o options) error {
 	// Load configuration
-	cfg, err := config.Load("config.json")
+	cfg, _, err := loadConfig(o.configPath)
 	if err != nil {
-		fmt.Printf("Failed to load config: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to load config: %w", err)
 	}
+	fileCfg := *cfg // 命令行覆盖项只用于本次运行，不写入配置文件
+	o.apply(cfg)
 
 	// Create game instance
 	g := game.New()
+	if o.seedSet {
+		g = game.NewWithClock(game.SystemClock(), o.seed)
+	}
+	if err := loadGame(g, cfg); err != nil {
+		return err
+	}
+	if err := g.LoadSentences(cfg.SentenceDictPath); err != nil {
+		fmt.Printf("Warning: Failed to load sentences: %v\n", err)
+		// Continue anyway - classic mode will still work
+	}
+	if cfg.RomanizationDictPath != "" {
+		if err := g.LoadRomanDictionary(cfg.RomanizationDictPath); err != nil {
+			fmt.Printf("Warning: Failed to load romanization dictionary: %v\n", err)
+		}
+	}
 
-	// Normalize difficulty ratios
-	shortRatio, mediumRatio, longRatio, err := cfg.NormalizeRatios()
+	// Load personal best records
+	best, err := records.Load(o.dataPath(recordsFile))
 	if err != nil {
-		fmt.Printf("Invalid difficulty ratios: %v\n", err)
-		os.Exit(1)
+		fmt.Printf("Warning: Failed to load records: %v\n", err)
+		best = nil // 没有排行榜也可以继续游戏
+	} else {
+		// 旧版本把记录保存在启动目录下
+		migrateLegacySpeedRunRecord(best, cfg, o.dataPath(legacySpeedRunFile))
+		migrateLegacySpeedRunRecord(best, cfg, legacySpeedRunFile)
+	}
+
+	m := initialModel(cfg, g, history.Open(o.dataPath(historyFile)), best, o.dataDir)
+	m.fileCfg = &fileCfg
+	m.cli = o
+	m.configPath = settingsPath(o.configPath)
+	if m.review, err = review.Load(o.dataPath(reviewFile)); err != nil {
+		fmt.Printf("Warning: Failed to load review queue: %v\n", err)
+		m.review = nil // 没有复习队列也可以继续游戏
+	}
+	if m.audio, err = newAudioPlayer(cfg); err != nil {
+		return err
+	}
+
+	// --mode 直接进入游戏，跳过欢迎和模式选择界面
+	if o.mode != "" {
+		mode, err := game.ParseMode(o.mode)
+		if err != nil {
+			return err
+		}
+		if o.seedSet {
+			// 显式指定种子时不加载幽灵对手（幽灵会改用它自己的种子）
+			m.prepare(mode)
+			err = startMode(g, cfg, mode)
+		} else {
+			m, err = m.start(mode)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to start %s: %w", o.mode, err)
+		}
+		m.ready = true
+	}
+
+	// Create Bubble Tea program
+	p := tea.NewProgram(
+		m,
+		tea.WithAltScreen(),       // use alternate screen buffer
+		tea.WithMouseCellMotion(), // enable mouse support (optional)
+	)
+
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("failed to run: %w", err)
+	}
+	return nil
+}
+
+// gameStats 从游戏状态构建界面显示的统计信息
+func gameStats(g *game.Game) ui.GameStats {
+	stats := ui.GameStats{
+		TotalKeystrokes:  g.Stats.TotalKeystrokes,
+		ValidKeystrokes:  g.Stats.ValidKeystrokes,
+		CorrectChars:     g.Stats.CorrectChars,
+		WordsCompleted:   g.Stats.WordsCompleted,
+		TotalLetters:     g.Stats.TotalLetters,
+		ElapsedSeconds:   g.Stats.GetElapsedSeconds(),
+		LettersPerSecond: g.Stats.GetLettersPerSecond(),
+		WordsPerSecond:   g.Stats.GetWordsPerSecond(),
+		AccuracyPercent:  g.Stats.GetAccuracyPercent(),
+
+		NetWPM:            g.Stats.GetNetWPM(),
+		RawWPM:            g.Stats.GetRawWPM(),
+		CorrectedErrors:   g.Stats.CorrectedErrors,
+		UncorrectedErrors: g.Stats.GetUncorrectedErrors(),
+		Consistency:       g.Stats.GetConsistency(),
+	}
+	if s := g.WordScore; s != nil {
+		stats.Score = &ui.ScoreInfo{
+			Score:      s.Score,
+			Streak:     s.Streak,
+			MaxStreak:  s.MaxStreak,
+			Multiplier: s.Multiplier(),
+			Penalties:  s.Penalties,
+		}
+	}
+	return stats
+}
+
+// typedRunes 按键输入的字符（Alt 组合键和其他特殊键不算输入）
+func typedRunes(msg tea.KeyMsg) []rune {
+	switch {
+	case msg.Alt:
+		return nil
+	case msg.Type == tea.KeySpace:
+		return []rune{' '}
+	case msg.Type == tea.KeyRunes:
+		return msg.Runes
+	}
+	return nil
+}
+
+// typeRune 按游戏模式处理一个输入字符
+func (m model) typeRune(r rune) {
+	switch {
+	case m.game.Mode == game.ModeRhythmDance && r == ' ':
+		// 节奏舞蹈模式：空格键触发判定
+		m.game.TryRhythmJudgment()
+	case m.game.Mode == game.ModeSentence:
+		// 句子模式：接受所有可打印字符（含标点和空格）
+		if unicode.IsPrint(r) {
+			m.game.AddChar(r)
+		}
+	case unicode.IsLetter(r):
+		// 其他模式：只接受字母（词库已转为小写）
+		m.game.AddChar(unicode.ToLower(r))
+	}
+}
+
+// loadGame 按配置加载词库和模式参数（不含句子库）
+func loadGame(g *game.Game, cfg *config.Config) error {
+	// 词库不在磁盘上时使用内置词库
+	g.SetFileOpener(data.Open)
+
+	// 目标难度和计算单词难度用的键盘布局
+	layout, err := game.ParseKeyboardLayout(cfg.KeyboardLayout)
+	if err != nil {
+		return err
 	}
+	g.SetDifficulty(float64(cfg.DifficultyTarget), layout)
 
-	// Load word dictionaries
-	if err := g.LoadWordDictionaries(
+	// Normalize difficulty ratios
+	// 设置了目标难度时不按长度配比，三个词库都要加载（合并后按难度选词）
+	shortRatio, mediumRatio, longRatio := 1.0/3, 1.0/3, 1.0/3
+	if cfg.DifficultyTarget == 0 {
+		if shortRatio, mediumRatio, longRatio, err = cfg.NormalizeRatios(); err != nil {
+			return fmt.Errorf("invalid difficulty ratios: %w", err)
+		}
+	}
+
+	// 词库字母表
+	var alphabets [3]game.Alphabet
+	for i, name := range []string{cfg.ShortDictAlphabet, cfg.MediumDictAlphabet, cfg.LongDictAlphabet} {
+		if alphabets[i], err = game.ParseAlphabet(name); err != nil {
+			return err
+		}
+	}
+	g.SetDictAlphabets(alphabets[0], alphabets[1], alphabets[2])
+	g.SetDictFilter(dict.Filter{Tags: cfg.DictionaryTags, MaxRank: cfg.DictionaryMaxRank})
+
+	// 选择了词库包或设置了单个词库时从中按长度选出三个难度
+	if cfg.Pack != "" {
+		if err := g.LoadDictionary(cfg.Pack, shortRatio, mediumRatio, longRatio); err != nil {
+			return fmt.Errorf("failed to load pack %s: %w", dict.PackName(cfg.Pack), err)
+		}
+	} else if cfg.DictionaryPath != "" {
+		if err := g.LoadDictionary(cfg.DictionaryPath, shortRatio, mediumRatio, longRatio); err != nil {
+			return err
+		}
+	} else if err := g.LoadWordDictionaries(
 		cfg.ShortDictPath,
 		cfg.MediumDictPath,
 		cfg.LongDictPath,
@@ -630,14 +822,7 @@ func main() {
 		mediumRatio,
 		longRatio,
 	); err != nil {
-		fmt.Printf("Failed to load word dictionaries: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Load sentences for sentence mode
-	if err := g.LoadSentences(cfg.SentenceDictPath); err != nil {
-		fmt.Printf("Warning: Failed to load sentences: %v\n", err)
-		// Continue anyway - classic mode will still work
+		return fmt.Errorf("failed to load word dictionaries: %w", err)
 	}
 
 	// 设置节奏大师模式的配置参数
@@ -646,58 +831,118 @@ func main() {
 	g.RhythmDifficultyStep = cfg.RhythmDifficultyStep
 	g.RhythmWordsPerLevel = cfg.RhythmWordsPerLevel
 
-	// Create Bubble Tea program
-	p := tea.NewProgram(
-		initialModel(cfg, g),
-		tea.WithAltScreen(),       // use alternate screen buffer
-		tea.WithMouseCellMotion(), // enable mouse support (optional)
-	)
+	// 经典和倒计时模式混入待复习单词的比例
+	g.ReviewMix = float64(cfg.ReviewMix) / 100
 
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Failed to run: %v\n", err)
-		os.Exit(1)
+	// 节奏舞蹈计分规则
+	rules, err := scoringRules(cfg)
+	if err != nil {
+		return err
 	}
-}
+	g.RhythmScoring = rules
 
-// speedRunRecord 存储极速模式的最佳时间
-type speedRunRecord struct {
-	BestTime float64 `json:"best_time"` // 单位：秒
-}
+	// 节奏舞蹈曲谱
+	bm, err := loadBeatmap(cfg.RhythmDanceBeatmapPath)
+	if err != nil {
+		return err
+	}
+	g.Beatmap = bm
 
-// loadSpeedRunBestTime 从文件加载最佳时间
-func loadSpeedRunBestTime() float64 {
-	const recordFile = "speedrun_record.json"
+	return nil
+}
 
-	file, err := os.Open(recordFile)
+// scoringRules 把配置中的计分规则转换为游戏使用的形式
+func scoringRules(cfg *config.Config) (game.ScoringRules, error) {
+	p, err := cfg.ScoringRules()
 	if err != nil {
-		return 0 // 还没有记录文件
+		return game.ScoringRules{}, fmt.Errorf("invalid rhythm dance scoring: %w", err)
 	}
-	defer file.Close()
-
-	var record speedRunRecord
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&record); err != nil {
-		return 0
+	rules := game.ScoringRules{
+		Windows: game.RhythmWindows{
+			Perfect: time.Duration(p.PerfectWindowMS) * time.Millisecond,
+			Nice:    time.Duration(p.NiceWindowMS) * time.Millisecond,
+			OK:      time.Duration(p.OKWindowMS) * time.Millisecond,
+		},
+		Points: map[game.Judgment]int{
+			game.JudgmentPerfect: p.PerfectPoints,
+			game.JudgmentNice:    p.NicePoints,
+			game.JudgmentOK:      p.OKPoints,
+			game.JudgmentMiss:    p.MissPoints,
+		},
+		MissLimit:     p.MissLimit,
+		OKBreaksCombo: p.OKBreaksCombo,
 	}
-
-	return record.BestTime
+	for _, m := range p.ComboMultipliers {
+		rules.ComboMultipliers = append(rules.ComboMultipliers, game.ComboMultiplier{Combo: m.Combo, Multiplier: m.Multiplier})
+	}
+	return rules, nil
 }
 
-// saveSpeedRunBestTime 保存新的最佳时间到文件
-func saveSpeedRunBestTime(newTime float64) error {
-	const recordFile = "speedrun_record.json"
+// newAudioPlayer 按配置创建声音提示后端（终端响铃写到 stderr，不干扰界面输出）
+func newAudioPlayer(cfg *config.Config) (audio.Player, error) {
+	p, err := audio.New(audio.Options{
+		Backend:   cfg.AudioBackend,
+		Command:   cfg.AudioCommand,
+		SoundsDir: cfg.AudioSoundsDir,
+		Output:    os.Stderr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up audio cues: %w", err)
+	}
+	return p, nil
+}
 
-	record := speedRunRecord{
-		BestTime: newTime,
+// playCues 播放游戏状态变化产生的声音提示（回放时跟随回放中的游戏）
+func (m model) playCues() {
+	if !m.ready {
+		return
+	}
+	for _, c := range m.cues.Update(m.game) {
+		m.audio.Play(c)
 	}
+}
 
-	file, err := os.Create(recordFile)
+// loadBeatmap 加载节奏舞蹈曲谱（磁盘上没有时回退到内置曲谱），路径为空时返回 nil
+func loadBeatmap(path string) (*beatmap.Beatmap, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := data.Open(path)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to open beatmap: %w", err)
+	}
+	defer f.Close()
+	bm, err := beatmap.Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
-	defer file.Close()
+	return bm, nil
+}
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(&record)
+// startMode 按配置启动指定模式
+func startMode(g *game.Game, cfg *config.Config, mode game.GameMode) error {
+	switch mode {
+	case game.ModeSentence:
+		return g.StartSentenceMode()
+	case game.ModeCountdown:
+		// 倒计时模式 - 从配置读取时长
+		return g.StartCountdownMode(time.Duration(cfg.CountdownDuration) * time.Second)
+	case game.ModeSpeedRun:
+		// 极速模式 - 从配置读取单词数
+		return g.StartSpeedRunMode(cfg.SpeedRunWordCount)
+	case game.ModeRhythmMaster:
+		return g.StartRhythmMasterMode()
+	case game.ModeUnderwaterCountdown:
+		return g.StartUnderwaterCountdown(cfg.CountdownDuration)
+	case game.ModeRhythmDance:
+		return g.StartRhythmDanceMode(cfg.RhythmDanceDuration, cfg.RhythmDanceInitialSpeed, cfg.RhythmDanceSpeedIncrement)
+	case game.ModePractice:
+		return g.StartPracticeMode(cfg.WordCount)
+	case game.ModeReview:
+		return g.StartReviewMode(cfg.WordCount)
+	case game.ModeRomanization:
+		return g.StartRomanizationMode(cfg.WordCount)
+	default:
+		return g.Start(cfg.WordCount)
+	}
 }
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/game"
+)
+
+// 游戏的默认计分规则在没有配置时使用（如测试和旧回放），必须与配置的默认规则一致
+func TestDefaultScoringRulesMatchConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if cfg.RhythmDanceScoring != config.DefaultScoringPreset {
+		t.Fatalf("default scoring = %q, want %q", cfg.RhythmDanceScoring, config.DefaultScoringPreset)
+	}
+	rules, err := scoringRules(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rules, game.DefaultScoringRules) {
+		t.Errorf("config %q preset = %+v\ngame.DefaultScoringRules = %+v", cfg.RhythmDanceScoring, rules, game.DefaultScoringRules)
+	}
+	if rules.Windows != game.DefaultRhythmWindows {
+		t.Errorf("config windows = %+v, game.DefaultRhythmWindows = %+v", rules.Windows, game.DefaultRhythmWindows)
+	}
+}
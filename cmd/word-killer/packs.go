@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/dict"
+	"github.com/word-killer/word-killer/pkg/ui"
+)
+
+// packsDir 词库包目录：配置的 packs_dir，未设置时为数据目录下的 packs
+func packsDir(cfg *config.Config, dataDir string) string {
+	if cfg.PacksDir != "" {
+		return cfg.PacksDir
+	}
+	return filepath.Join(dataDir, "packs")
+}
+
+// openModeSelect 打开模式选择界面，并重新扫描词库包目录（新放入的词库包不需要重启）
+func (m model) openModeSelect() model {
+	m.ready = false
+	m.showModeSelect = true
+	m.selectedMode = 0
+	m.modeNotice = ""
+
+	packs, skipped, err := dict.Discover(packsDir(m.cfg, m.dataDir))
+	m.packs = packs
+	switch {
+	case err != nil:
+		m.modeNotice = err.Error()
+	case len(skipped) > 0:
+		m.modeNotice = fmt.Sprintf("Skipped %d invalid pack(s) - run word-killer validate-config", len(skipped))
+	}
+	return m
+}
+
+// packIndex 当前词库包在列表中的位置，-1 表示使用配置的词库（或不在词库包目录中的词库包）
+func (m model) packIndex() int {
+	for i, p := range m.packs {
+		if p.Path == m.cfg.Pack {
+			return i
+		}
+	}
+	return -1
+}
+
+// cyclePack 切换到上一个或下一个词库包并立即加载（不需要重启）
+// 顺序为：配置的词库、词库包 1、词库包 2……；加载失败时保持原来的词库并显示原因
+func (m model) cyclePack(delta int) model {
+	if m.packPicker() == nil {
+		return m
+	}
+	n := len(m.packs) + 1
+	next := (m.packIndex()+1+delta+n)%n - 1
+
+	cfg := *m.cfg
+	cfg.Pack = ""
+	if next >= 0 {
+		cfg.Pack = m.packs[next].Path
+	}
+	if err := loadGame(m.game, &cfg); err != nil {
+		m.modeNotice = err.Error()
+		// 恢复原来的词库
+		if err := loadGame(m.game, m.cfg); err != nil {
+			m.modeNotice = err.Error()
+		}
+		return m
+	}
+	*m.cfg = cfg
+	m.fileCfg.Pack = cfg.Pack // 在设置界面保存时一起保存
+	m.modeNotice = ""
+	return m
+}
+
+// packPicker 模式选择界面的词库包选择器（没有词库包时不显示）
+func (m model) packPicker() *ui.PackPicker {
+	if len(m.packs) == 0 && m.cfg.Pack == "" {
+		return nil
+	}
+	picker := &ui.PackPicker{Name: "Configured dictionaries"}
+	if i := m.packIndex(); i >= 0 {
+		p := m.packs[i]
+		picker.Name = p.Name
+		picker.Words = p.Words
+		picker.Language = p.Language
+	} else if m.cfg.Pack != "" {
+		picker.Name = dict.PackName(m.cfg.Pack)
+	}
+	return picker
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/game"
+)
+
+func TestPackPickerSwapsDictionaries(t *testing.T) {
+	dataDir := t.TempDir()
+	dir := filepath.Join(dataDir, "packs")
+	os.MkdirAll(dir, 0o755)
+	os.WriteFile(filepath.Join(dir, "go.tsv"), []byte("#word-killer-dict 1\n#name: Go keywords\nword\nfunc\nreturn\ninterface\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "broken.tsv"), []byte("#word-killer-dict 1\nrank\n"), 0o644)
+
+	cfg := config.DefaultConfig()
+	g := game.New()
+	if err := loadGame(g, cfg); err != nil {
+		t.Fatalf("loadGame: %v", err)
+	}
+	m := initialModel(cfg, g, nil, nil, dataDir)
+	m = m.openModeSelect()
+	if len(m.packs) != 1 || m.packs[0].Name != "Go keywords" {
+		t.Fatalf("packs = %+v", m.packs)
+	}
+	if !strings.Contains(m.modeNotice, "Skipped 1 invalid pack") {
+		t.Errorf("notice = %q", m.modeNotice)
+	}
+
+	// 切换到词库包：不需要重启，单词立即来自词库包
+	key := func(k tea.KeyType) {
+		updated, _ := m.handleKey(tea.KeyMsg{Type: k})
+		m = updated.(model)
+	}
+	key(tea.KeyRight)
+	if cfg.Pack != filepath.Join(dir, "go.tsv") || m.packPicker().Name != "Go keywords" {
+		t.Fatalf("pack = %q, picker = %+v (notice %q)", cfg.Pack, m.packPicker(), m.modeNotice)
+	}
+	if err := g.Start(3); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range g.Words {
+		if !strings.Contains("func return interface", w.Text) {
+			t.Errorf("word %q is not from the pack", w.Text)
+		}
+	}
+
+	// 再切换一次回到配置的词库
+	key(tea.KeyRight)
+	if cfg.Pack != "" || m.packPicker().Name != "Configured dictionaries" {
+		t.Errorf("pack = %q, want configured dictionaries", cfg.Pack)
+	}
+}
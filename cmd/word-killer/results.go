@@ -0,0 +1,402 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/game"
+	"github.com/word-killer/word-killer/pkg/history"
+	"github.com/word-killer/word-killer/pkg/records"
+	"github.com/word-killer/word-killer/pkg/replay"
+	"github.com/word-killer/word-killer/pkg/review"
+	"github.com/word-killer/word-killer/pkg/stats"
+	"github.com/word-killer/word-killer/pkg/ui"
+)
+
+const (
+	historyFile = "history.jsonl" // 历史记录文件
+	recordsFile = "records.json"  // 个人最佳排行榜文件
+	reviewFile  = "review.json"   // 间隔复习队列文件
+	replayDir   = "replays"       // 回放文件目录
+	keepReplays = 100             // 保留最近的回放数（排行榜引用的回放另外保留）
+
+	// legacySpeedRunFile 旧版本只保存极速模式最佳时间的文件
+	legacySpeedRunFile = "speedrun_record.json"
+
+	// practiceHistory 自适应练习参考的最近对局数：只看近期数据，练好的旧弱项不再影响选词
+	practiceHistory = 20
+)
+
+// recordFinishedGame 对局结束时写入一次历史记录并提交排行榜
+func (m model) recordFinishedGame() model {
+	// 回放中的游戏不记录
+	if m.player != nil {
+		return m
+	}
+	if m.game.Status != game.StatusFinished {
+		m.resultRecorded = false
+		m.lastRecord = ui.RecordInfo{}
+		m.lastReplay = nil
+		m.keys = nil
+		return m
+	}
+	if m.resultRecorded {
+		return m
+	}
+	m.resultRecorded = true
+
+	rec := newHistoryRecord(m.game, m.cfg)
+
+	// 写入失败不影响游戏流程
+	m.lastReplay = replay.FromGame(m.game, *m.cfg)
+	path := filepath.Join(m.dataDir, replayDir, replay.FileName(m.lastReplay))
+	if m.lastReplay.Save(path) == nil {
+		rec.Replay = path
+	}
+	if m.history != nil {
+		_ = m.history.Append(rec)
+	}
+	m.updateReview()
+	if m.records != nil {
+		if res, ok := m.records.Submit(rec); ok {
+			m.lastRecord = ui.RecordInfo{
+				Rank:        res.Rank,
+				IsNewRecord: res.IsNewRecord,
+				Value:       res.Value,
+				Unit:        res.Category.Unit,
+				Label:       res.Category.Label,
+			}
+			if res.Rank > 0 {
+				_ = m.records.Save()
+			}
+		}
+	}
+	m.pruneReplays()
+
+	return m
+}
+
+// pruneReplays 删除超出保留数量的旧回放，排行榜成绩引用的回放（幽灵赛跑需要）不会删除
+func (m model) pruneReplays() {
+	var protected map[string]bool
+	if m.records != nil {
+		protected = m.records.Replays()
+	}
+	_ = replay.Prune(filepath.Join(m.dataDir, replayDir), keepReplays, protected)
+}
+
+// keyReport 构建按键分析界面的数据：allTime 时汇总历史记录中所有带按键统计的对局
+// 读不到历史记录时只显示刚结束的一局
+func (m model) keyReport(allTime bool) *ui.KeyReport {
+	current := &ui.KeyReport{Keys: m.game.Stats.Keys}
+	if !allTime {
+		return current
+	}
+	keys, games, err := m.keyProfile(0)
+	if err != nil || games == 0 {
+		return current
+	}
+	return &ui.KeyReport{Keys: keys, AllTime: true, Games: games}
+}
+
+// keyProfile 汇总历史记录中最近 limit 局（0 表示全部）带按键统计的对局，返回汇总结果和局数
+func (m model) keyProfile(limit int) (stats.KeyStats, int, error) {
+	if m.history == nil {
+		return stats.KeyStats{}, 0, nil
+	}
+	all, err := m.history.Load()
+	if err != nil {
+		return stats.KeyStats{}, 0, err
+	}
+	var recs []history.Record
+	for _, rec := range all {
+		if rec.Keys != nil {
+			recs = append(recs, rec)
+		}
+	}
+	if limit > 0 && len(recs) > limit {
+		recs = recs[len(recs)-limit:]
+	}
+	return history.KeyProfile(recs), len(recs), nil
+}
+
+// practiceProfile 自适应练习开局时参考的按键统计（读不到历史记录时为空，按普通方式选词）
+func (m model) practiceProfile() stats.KeyStats {
+	keys, _, _ := m.keyProfile(practiceHistory)
+	return keys
+}
+
+// updateReview 按本局每个单词的输入过程更新复习队列：退格多、卡壳的单词加入队列，队列中的单词按 SM-2 重新排程
+func (m model) updateReview() {
+	// 罗马字模式输入的是读音而不是词库单词，不加入复习队列
+	if m.review == nil || m.game.Mode == game.ModeRomanization {
+		return
+	}
+	changed := false
+	for _, a := range m.game.Attempts {
+		if m.review.Record(a.Word, review.Quality(a.Corrections, a.Pause), m.game.Stats.EndTime) {
+			changed = true
+		}
+	}
+	if changed {
+		_ = m.review.Save()
+	}
+}
+
+// dueWords 已到期的待复习单词（没有复习队列时为空）
+func (m model) dueWords() []string {
+	if m.review == nil {
+		return nil
+	}
+	return m.review.Due(time.Now())
+}
+
+// reviewNotice 复习模式无法开始时在模式选择界面显示的提示（可以开始时为空）
+func (m model) reviewNotice() string {
+	if len(m.dueWords()) > 0 {
+		return ""
+	}
+	if m.review != nil {
+		if next, ok := m.review.NextDue(); ok {
+			return "No words due for review - next one " + next.Format("Jan 2 15:04")
+		}
+	}
+	return "No words to review yet - fumbled words are added as you play"
+}
+
+// prepare 设置开局前由外部提供的数据：自适应练习的历史按键统计、复习和混入复习单词用的到期单词
+func (m model) prepare(mode game.GameMode) {
+	m.game.ReviewWords = nil
+	switch mode {
+	case game.ModePractice:
+		m.game.PracticeProfile = m.practiceProfile()
+	case game.ModeReview:
+		m.game.ReviewWords = m.dueWords()
+	case game.ModeClassic, game.ModeCountdown:
+		if m.cfg.ReviewMix > 0 {
+			m.game.ReviewWords = m.dueWords()
+		}
+	}
+}
+
+// start 启动指定模式；极速模式会加载最佳成绩的回放作为幽灵对手
+func (m model) start(mode game.GameMode) (model, error) {
+	m.ghost = nil
+	m.prepare(mode)
+	if mode == game.ModeSpeedRun {
+		if ghost := m.loadGhost(); ghost != nil {
+			// 使用幽灵对局的种子，得到相同的单词序列
+			m.game.UseSeed(ghost.Seed())
+			m.ghost = ghost
+		}
+	}
+
+	if err := startMode(m.game, m.cfg, mode); err != nil {
+		m.ghost = nil
+		return m, err
+	}
+
+	if m.ghost != nil {
+		words := make([]string, 0, len(m.game.Words))
+		for _, w := range m.game.Words {
+			words = append(words, w.Text)
+		}
+		if !m.ghost.SameWords(words) {
+			m.ghost = nil // 词库已改变，单词不同无法比较
+		}
+	}
+	return m, nil
+}
+
+// loadGhost 加载当前极速模式变体最佳成绩的回放
+func (m model) loadGhost() *replay.Ghost {
+	if m.records == nil {
+		return nil
+	}
+	best, ok := m.records.Best(records.CategoryFor(game.ModeSpeedRun.String(), *m.cfg).Key)
+	if !ok || best.Replay == "" {
+		return nil
+	}
+	r, err := replay.Load(best.Replay)
+	if err != nil || r.Mode != game.ModeSpeedRun.String() {
+		return nil
+	}
+	return replay.NewGhost(r)
+}
+
+// ghostProgress 获取幽灵对手在当前游戏时刻的进度
+func (m model) ghostProgress() (ui.GhostInfo, map[string]bool) {
+	if m.ghost == nil || m.player != nil {
+		return ui.GhostInfo{}, nil
+	}
+
+	now := m.game.PlayTime()
+	finish := m.ghost.FinishTime()
+	return ui.GhostInfo{
+		Active:     true,
+		Words:      m.ghost.WordsAt(now),
+		Finished:   finish > 0 && now >= finish,
+		FinishTime: finish.Seconds(),
+	}, m.ghost.CompletedAt(now)
+}
+
+// startReplay 开始播放刚结束的对局
+func (m model) startReplay() model {
+	if m.lastReplay == nil {
+		return m
+	}
+	if played, err := m.playReplay(m.lastReplay); err == nil {
+		return played
+	}
+	return m
+}
+
+// playReplay 开始播放一个回放，暂存当前的真实游戏
+func (m model) playReplay(r *replay.Replay) (model, error) {
+	start := time.Now()
+	player, err := replay.NewPlayer(r, start, func(g *game.Game) error {
+		return setupReplay(g, r)
+	})
+	if err != nil {
+		return m, err
+	}
+
+	m.liveGame = m.game
+	m.game = player.Game
+	m.player = player
+	m.replayStart = start
+	return m, nil
+}
+
+// stopReplay 结束回放，回到原来的结果页面
+func (m model) stopReplay() model {
+	if m.player == nil {
+		return m
+	}
+	m.game = m.liveGame
+	m.liveGame = nil
+	m.player = nil
+	return m
+}
+
+// setupReplay 按回放记录的配置加载词库并启动对应模式
+func setupReplay(g *game.Game, r *replay.Replay) error {
+	mode, err := game.ParseMode(r.Mode)
+	if err != nil {
+		return err
+	}
+	cfg := r.Config
+	if err := loadGame(g, &cfg); err != nil {
+		return err
+	}
+	if r.PracticeProfile != nil {
+		g.PracticeProfile = *r.PracticeProfile
+	}
+	g.ReviewWords = r.ReviewWords
+	if mode == game.ModeSentence {
+		if err := g.LoadSentences(cfg.SentenceDictPath); err != nil {
+			return err
+		}
+	}
+	if mode == game.ModeRomanization {
+		if err := g.LoadRomanDictionary(cfg.RomanizationDictPath); err != nil {
+			return err
+		}
+	}
+	return startMode(g, &cfg, mode)
+}
+
+// bestValue 获取当前配置下指定模式的最佳成绩（没有记录时返回0）
+func (m model) bestValue(mode game.GameMode) float64 {
+	if m.records == nil {
+		return 0
+	}
+	best, ok := m.records.Best(records.CategoryFor(mode.String(), *m.cfg).Key)
+	if !ok {
+		return 0
+	}
+	return best.Value
+}
+
+// newHistoryRecord 根据当前游戏状态构建历史记录
+func newHistoryRecord(g *game.Game, cfg *config.Config) history.Record {
+	rec := history.Record{
+		Mode:      g.Mode.String(),
+		StartedAt: g.Stats.StartTime,
+		EndedAt:   g.Stats.EndTime,
+		Aborted:   g.Aborted,
+		Config:    *cfg,
+		Stats:     g.Stats.Snapshot(),
+	}
+	if !g.Stats.Keys.Empty() {
+		keys := g.Stats.Keys.Clone()
+		rec.Keys = &keys
+	}
+
+	if s := g.WordScore; s != nil {
+		rec.WordScore = &history.WordScoreSummary{
+			Score:     s.Score,
+			MaxStreak: s.MaxStreak,
+			Penalties: s.Penalties,
+		}
+	}
+
+	switch g.Mode {
+	case game.ModeRhythmMaster:
+		rec.RhythmMaster = &history.RhythmMasterSummary{
+			DifficultyLevel:      g.DifficultyLevel,
+			ConsecutiveSuccesses: g.ConsecutiveSuccesses,
+			FinalTimeLimit:       g.WordTimeLimit.Seconds(),
+		}
+	case game.ModeRhythmDance:
+		if state := g.RhythmDanceState; state != nil {
+			rec.RhythmDance = &history.RhythmDanceSummary{
+				TotalScore:     state.TotalScore,
+				CompletedWords: state.CompletedWords,
+				MaxCombo:       state.MaxCombo,
+				PerfectCount:   state.PerfectCount,
+				NiceCount:      state.NiceCount,
+				OKCount:        state.OKCount,
+				MissCount:      state.MissCount,
+			}
+		}
+	}
+
+	return rec
+}
+
+// migrateLegacySpeedRunRecord 把旧版 speedrun_record.json 的最佳时间导入排行榜
+// 旧文件不记录单词数，按当前配置的极速模式变体导入；导入后删除旧文件
+func migrateLegacySpeedRunRecord(store *records.Store, cfg *config.Config, path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	var legacy struct {
+		BestTime float64 `json:"best_time"` // 单位：秒
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil || legacy.BestTime <= 0 {
+		return
+	}
+
+	store.Submit(history.Record{
+		Mode:    game.ModeSpeedRun.String(),
+		EndedAt: info.ModTime(),
+		Config:  *cfg,
+		Stats: stats.Snapshot{
+			ElapsedSeconds: legacy.BestTime,
+			WordsCompleted: cfg.SpeedRunWordCount,
+		},
+	})
+	if store.Save() == nil {
+		os.Remove(path)
+	}
+}
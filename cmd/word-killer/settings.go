@@ -0,0 +1,383 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/word-killer/word-killer/data"
+	"github.com/word-killer/word-killer/pkg/audio"
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/game"
+	"github.com/word-killer/word-killer/pkg/ui"
+)
+
+// settingKind 设置项的编辑方式
+type settingKind int
+
+const (
+	settingInt    settingKind = iota // 整数步进
+	settingFloat                     // 小数步进
+	settingRatio                     // 配比滑块（0-100）
+	settingPath                      // 文件路径输入
+	settingText                      // 文本输入（不检查文件）
+	settingChoice                    // 在 options 中循环选择
+)
+
+// setting 设置界面中的一个配置项
+type setting struct {
+	key     string // JSON 键名，与 config.FieldError.Field 对应
+	label   string // 显示名称
+	kind    settingKind
+	step    float64                         // 每次按键的调整量
+	zero    string                          // 整数项为 0 时显示的说明（如 "preset"）
+	options func(c *config.Config) []string // settingChoice 的可选值（可能取决于配置本身）
+	field   func(c *config.Config) any      // 返回字段指针：*int、*float64 或 *string
+}
+
+// fileOnlySettings 只能在配置文件中编辑的键（结构化的值不适合在设置界面里编辑）
+var fileOnlySettings = map[string]bool{
+	"rhythm_dance_scoring_presets": true,
+	"dictionary_tags":              true,
+	"pack":                         true, // 在模式选择界面切换
+}
+
+// settingFields 设置界面列出的全部配置项，顺序与 config.Config 一致
+var settingFields = []setting{
+	{key: "word_count", label: "Classic word count", kind: settingInt, step: 5,
+		field: func(c *config.Config) any { return &c.WordCount }},
+	{key: "short_dict_path", label: "Short dictionary", kind: settingPath,
+		field: func(c *config.Config) any { return &c.ShortDictPath }},
+	{key: "medium_dict_path", label: "Medium dictionary", kind: settingPath,
+		field: func(c *config.Config) any { return &c.MediumDictPath }},
+	{key: "long_dict_path", label: "Long dictionary", kind: settingPath,
+		field: func(c *config.Config) any { return &c.LongDictPath }},
+	{key: "short_dict_alphabet", label: "Short alphabet", kind: settingChoice, options: alphabetOptions,
+		field: func(c *config.Config) any { return &c.ShortDictAlphabet }},
+	{key: "medium_dict_alphabet", label: "Medium alphabet", kind: settingChoice, options: alphabetOptions,
+		field: func(c *config.Config) any { return &c.MediumDictAlphabet }},
+	{key: "long_dict_alphabet", label: "Long alphabet", kind: settingChoice, options: alphabetOptions,
+		field: func(c *config.Config) any { return &c.LongDictAlphabet }},
+	{key: "dictionary_path", label: "Single dictionary", kind: settingPath,
+		field: func(c *config.Config) any { return &c.DictionaryPath }},
+	{key: "dictionary_max_rank", label: "Most frequent words only", kind: settingInt, step: 500, zero: "all",
+		field: func(c *config.Config) any { return &c.DictionaryMaxRank }},
+	{key: "packs_dir", label: "Dictionary packs folder", kind: settingPath,
+		field: func(c *config.Config) any { return &c.PacksDir }},
+	{key: "short_ratio", label: "Short ratio", kind: settingRatio, step: 5,
+		field: func(c *config.Config) any { return &c.ShortRatio }},
+	{key: "medium_ratio", label: "Medium ratio", kind: settingRatio, step: 5,
+		field: func(c *config.Config) any { return &c.MediumRatio }},
+	{key: "long_ratio", label: "Long ratio", kind: settingRatio, step: 5,
+		field: func(c *config.Config) any { return &c.LongRatio }},
+	{key: "difficulty_target", label: "Difficulty target", kind: settingInt, step: 5, zero: "ratios",
+		field: func(c *config.Config) any { return &c.DifficultyTarget }},
+	{key: "keyboard_layout", label: "Keyboard layout", kind: settingChoice, options: keyboardLayoutOptions,
+		field: func(c *config.Config) any { return &c.KeyboardLayout }},
+	{key: "sentence_dict_path", label: "Sentence dictionary", kind: settingPath,
+		field: func(c *config.Config) any { return &c.SentenceDictPath }},
+	{key: "romanization_dict_path", label: "Romanization dictionary", kind: settingPath,
+		field: func(c *config.Config) any { return &c.RomanizationDictPath }},
+	{key: "countdown_duration", label: "Countdown duration (s)", kind: settingInt, step: 5,
+		field: func(c *config.Config) any { return &c.CountdownDuration }},
+	{key: "speedrun_word_count", label: "Speed run word count", kind: settingInt, step: 5,
+		field: func(c *config.Config) any { return &c.SpeedRunWordCount }},
+	{key: "review_mix", label: "Review words mixed in (%)", kind: settingInt, step: 5, zero: "off",
+		field: func(c *config.Config) any { return &c.ReviewMix }},
+	{key: "rhythm_initial_time_limit", label: "Rhythm initial limit (s)", kind: settingFloat, step: 0.1,
+		field: func(c *config.Config) any { return &c.RhythmInitialTimeLimit }},
+	{key: "rhythm_min_time_limit", label: "Rhythm min limit (s)", kind: settingFloat, step: 0.1,
+		field: func(c *config.Config) any { return &c.RhythmMinTimeLimit }},
+	{key: "rhythm_difficulty_step", label: "Rhythm difficulty step (s)", kind: settingFloat, step: 0.05,
+		field: func(c *config.Config) any { return &c.RhythmDifficultyStep }},
+	{key: "rhythm_words_per_level", label: "Rhythm words per level", kind: settingInt, step: 1,
+		field: func(c *config.Config) any { return &c.RhythmWordsPerLevel }},
+	{key: "rhythm_dance_duration", label: "Dance duration (s)", kind: settingInt, step: 5,
+		field: func(c *config.Config) any { return &c.RhythmDanceDuration }},
+	{key: "rhythm_dance_initial_speed", label: "Dance initial speed", kind: settingFloat, step: 0.005,
+		field: func(c *config.Config) any { return &c.RhythmDanceInitialSpeed }},
+	{key: "rhythm_dance_speed_increment", label: "Dance speed increment", kind: settingFloat, step: 0.001,
+		field: func(c *config.Config) any { return &c.RhythmDanceSpeedIncrement }},
+	{key: "rhythm_dance_scoring", label: "Dance scoring", kind: settingChoice,
+		options: func(c *config.Config) []string { return c.ScoringPresetNames() },
+		field:   func(c *config.Config) any { return &c.RhythmDanceScoring }},
+	{key: "rhythm_dance_perfect_window_ms", label: "Perfect window (ms)", kind: settingInt, step: 5, zero: "preset",
+		field: func(c *config.Config) any { return &c.RhythmDancePerfectWindow }},
+	{key: "rhythm_dance_nice_window_ms", label: "Nice window (ms)", kind: settingInt, step: 10, zero: "preset",
+		field: func(c *config.Config) any { return &c.RhythmDanceNiceWindow }},
+	{key: "rhythm_dance_ok_window_ms", label: "OK window (ms)", kind: settingInt, step: 10, zero: "preset",
+		field: func(c *config.Config) any { return &c.RhythmDanceOKWindow }},
+	{key: "rhythm_dance_beatmap_path", label: "Dance beatmap", kind: settingPath,
+		field: func(c *config.Config) any { return &c.RhythmDanceBeatmapPath }},
+	{key: "audio_backend", label: "Audio cues", kind: settingChoice, options: func(*config.Config) []string { return audio.Backends() },
+		field: func(c *config.Config) any { return &c.AudioBackend }},
+	{key: "audio_command", label: "Audio player command", kind: settingText,
+		field: func(c *config.Config) any { return &c.AudioCommand }},
+	{key: "audio_sounds_dir", label: "Audio sounds directory", kind: settingPath,
+		field: func(c *config.Config) any { return &c.AudioSoundsDir }},
+}
+
+// alphabetOptions 词库字母表的可选值
+func alphabetOptions(*config.Config) []string { return game.Alphabets() }
+
+// keyboardLayoutOptions 计算单词难度用的键盘布局
+func keyboardLayoutOptions(*config.Config) []string { return game.KeyboardLayouts() }
+
+// settingsState 设置界面的编辑状态
+// 修改只作用于 draft，确认保存后才应用到游戏并写入配置文件
+type settingsState struct {
+	draft   config.Config
+	cursor  int               // 0..len(settingFields)-1 为配置项，之后依次为 Save、Cancel
+	editing bool              // 是否正在输入文件路径或文本
+	input   string            // 输入缓冲
+	errors  map[string]string // 按 JSON 键名记录的校验错误
+	status  string            // 保存失败等提示信息
+}
+
+// newSettings 以当前配置为草稿打开设置界面
+func newSettings(cfg *config.Config) *settingsState {
+	s := &settingsState{draft: *cfg}
+	s.validate()
+	return s
+}
+
+// saveIndex / cancelIndex 设置列表末尾的两个按钮
+func (s *settingsState) saveIndex() int   { return len(settingFields) }
+func (s *settingsState) cancelIndex() int { return len(settingFields) + 1 }
+
+// move 移动光标（循环）
+func (s *settingsState) move(delta int) {
+	n := len(settingFields) + 2
+	s.cursor = (s.cursor + delta + n) % n
+}
+
+// adjust 调整当前数值项，dir 为 +1 或 -1
+func (s *settingsState) adjust(dir int) {
+	if s.cursor >= len(settingFields) {
+		return
+	}
+	f := settingFields[s.cursor]
+	switch p := f.field(&s.draft).(type) {
+	case *int:
+		*p = max(*p+dir*int(f.step), 0)
+	case *float64:
+		v := math.Round((*p+float64(dir)*f.step)/f.step) * f.step
+		v = math.Round(v*1e6) / 1e6 // 去掉浮点误差，保存的 JSON 更干净
+		if f.kind == settingRatio {
+			v = min(v, 100)
+		}
+		*p = max(v, 0)
+	case *string:
+		if f.kind != settingChoice {
+			return
+		}
+		options := f.options(&s.draft)
+		i := 0
+		for j, o := range options {
+			if o == *p {
+				i = j
+			}
+		}
+		*p = options[(i+dir+len(options))%len(options)]
+	}
+	s.validate()
+}
+
+// startEdit 开始编辑当前路径或文本项
+func (s *settingsState) startEdit() {
+	if s.cursor >= len(settingFields) {
+		return
+	}
+	if kind := settingFields[s.cursor].kind; kind != settingPath && kind != settingText {
+		return
+	}
+	s.editing = true
+	s.input = *settingFields[s.cursor].field(&s.draft).(*string)
+}
+
+// finishEdit 结束路径输入，commit 为 false 时放弃修改
+func (s *settingsState) finishEdit(commit bool) {
+	if commit {
+		*settingFields[s.cursor].field(&s.draft).(*string) = s.input
+		s.validate()
+	}
+	s.editing = false
+	s.input = ""
+}
+
+// validate 校验草稿：配置规则之外还检查词库文件是否存在
+func (s *settingsState) validate() {
+	s.errors = make(map[string]string)
+	var invalid *config.ValidationError
+	if err := s.draft.Validate(); errors.As(err, &invalid) {
+		for _, fe := range invalid.Errors {
+			if _, seen := s.errors[fe.Field]; !seen {
+				s.errors[fe.Field] = fe.Message
+			}
+		}
+	}
+	for _, f := range settingFields {
+		if f.kind != settingPath {
+			continue
+		}
+		path := *f.field(&s.draft).(*string)
+		if _, seen := s.errors[f.key]; !seen && path != "" && !data.Exists(path) {
+			s.errors[f.key] = "file not found"
+		}
+	}
+}
+
+// valid 草稿是否可以保存
+func (s *settingsState) valid() bool {
+	return len(s.errors) == 0
+}
+
+// rows 构建界面显示的设置行
+func (s *settingsState) rows() []ui.SettingRow {
+	total := s.draft.ShortRatio + s.draft.MediumRatio + s.draft.LongRatio
+	rows := make([]ui.SettingRow, len(settingFields))
+	for i, f := range settingFields {
+		row := ui.SettingRow{Label: f.label, Error: s.errors[f.key], Slider: -1}
+		switch p := f.field(&s.draft).(type) {
+		case *int:
+			row.Value = fmt.Sprintf("%d", *p)
+			if *p == 0 && f.zero != "" {
+				row.Value = f.zero
+			}
+		case *float64:
+			row.Value = fmt.Sprintf("%g", *p)
+			if f.kind == settingRatio {
+				row.Slider = *p / 100
+				if total > 0 {
+					row.Value = fmt.Sprintf("%g (%.0f%%)", *p, *p/total*100)
+				}
+			}
+		case *string:
+			row.Value = *p
+			row.Path = f.kind != settingChoice
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// handleSettingsKey 设置界面的按键处理
+func (m model) handleSettingsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.settings
+
+	// 路径输入
+	if s.editing {
+		switch msg.Type {
+		case tea.KeyEnter:
+			s.finishEdit(true)
+		case tea.KeyEsc:
+			s.finishEdit(false)
+		case tea.KeyBackspace:
+			if r := []rune(s.input); len(r) > 0 {
+				s.input = string(r[:len(r)-1])
+			}
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+		case tea.KeyRunes, tea.KeySpace:
+			s.input += string(msg.Runes)
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		s.move(-1)
+	case "down", "j", "tab":
+		s.move(1)
+	case "left", "h", "-":
+		s.adjust(-1)
+	case "right", "l", "+", "=":
+		s.adjust(1)
+	case "enter":
+		switch s.cursor {
+		case s.saveIndex():
+			return m.applySettings(), nil
+		case s.cancelIndex():
+			m.settings = nil
+		default:
+			s.startEdit()
+		}
+	case "esc":
+		// 放弃修改
+		m.settings = nil
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// applySettings 把草稿应用到当前游戏并保存到配置文件
+// 草稿来自配置文件（不含命令行覆盖项），覆盖项只重新应用到运行时配置；失败时留在设置界面并显示原因
+func (m model) applySettings() model {
+	s := m.settings
+	if !s.valid() {
+		s.status = "Fix the highlighted settings before saving"
+		return m
+	}
+
+	saved := s.draft
+	cfg := saved
+	m.cli.apply(&cfg)
+
+	// 任何一步失败都按原来的配置重新加载，游戏不会停留在一半新、一半旧的状态
+	rollback := func(err error) model {
+		s.status = err.Error()
+		if err := loadAll(m.game, m.cfg); err != nil {
+			s.status = err.Error()
+		}
+		return m
+	}
+	if err := loadAll(m.game, &cfg); err != nil {
+		return rollback(err)
+	}
+	player, err := newAudioPlayer(&cfg)
+	if err != nil {
+		return rollback(err)
+	}
+	*m.cfg = cfg
+	*m.fileCfg = saved
+	m.audio = player
+
+	if err := config.Save(&saved, m.configPath); err != nil {
+		s.status = fmt.Sprintf("Applied, but %v", err)
+		return m
+	}
+	m.settings = nil
+	return m
+}
+
+// loadAll 按配置加载词库、模式参数、句子库和罗马字词库
+func loadAll(g *game.Game, cfg *config.Config) error {
+	if err := loadGame(g, cfg); err != nil {
+		return err
+	}
+	if err := g.LoadSentences(cfg.SentenceDictPath); err != nil {
+		return err
+	}
+	if cfg.RomanizationDictPath != "" {
+		if err := g.LoadRomanDictionary(cfg.RomanizationDictPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderSettings 渲染设置界面
+func (m model) renderSettings(vp ui.Viewport) string {
+	s := m.settings
+	return ui.RenderSettings(vp, ui.SettingsView{
+		Rows:     s.rows(),
+		Selected: s.cursor,
+		Editing:  s.editing,
+		Input:    s.input,
+		Valid:    s.valid(),
+		Status:   s.status,
+		SavePath: m.configPath,
+	}, m.animFrame)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/game"
+)
+
+// settingIndex 按 JSON 键名查找设置项
+func settingIndex(t *testing.T, key string) int {
+	t.Helper()
+	for i, f := range settingFields {
+		if f.key == key {
+			return i
+		}
+	}
+	t.Fatalf("no setting for %s", key)
+	return -1
+}
+
+func TestSettingsCoverEveryConfigField(t *testing.T) {
+	keys := make(map[string]bool)
+	for _, f := range settingFields {
+		keys[f.key] = true
+	}
+	typ := reflect.TypeOf(config.Config{})
+	for i := 0; i < typ.NumField(); i++ {
+		key, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if fileOnlySettings[key] {
+			keys[key] = true
+			continue
+		}
+		if !keys[key] {
+			t.Errorf("config field %s is missing from the settings screen", key)
+		}
+	}
+	if len(keys) != typ.NumField() {
+		t.Errorf("%d settings for %d config fields", len(keys), typ.NumField())
+	}
+}
+
+func TestSettingsAdjustAndValidate(t *testing.T) {
+	s := newSettings(config.DefaultConfig())
+
+	s.cursor = settingIndex(t, "rhythm_dance_speed_increment")
+	s.adjust(1)
+	if s.draft.RhythmDanceSpeedIncrement != 0.006 {
+		t.Errorf("speed increment = %v, want 0.006", s.draft.RhythmDanceSpeedIncrement)
+	}
+
+	s.cursor = settingIndex(t, "long_ratio")
+	for range 30 {
+		s.adjust(1)
+	}
+	if s.draft.LongRatio != 100 {
+		t.Errorf("ratio slider should stop at 100, got %v", s.draft.LongRatio)
+	}
+
+	// 选项循环切换；command 后端需要填写命令
+	s.cursor = settingIndex(t, "audio_backend")
+	s.adjust(-1)
+	if s.draft.AudioBackend != "command" || s.errors["audio_command"] == "" {
+		t.Errorf("audio backend = %q, errors %v; want command with a missing-command error", s.draft.AudioBackend, s.errors)
+	}
+	s.adjust(1)
+	if s.draft.AudioBackend != "none" || !s.valid() {
+		t.Errorf("audio backend = %q, errors %v; want none", s.draft.AudioBackend, s.errors)
+	}
+
+	// 计分规则的选项包括配置文件中自定义的规则
+	s.draft.RhythmDanceScoringPresets = map[string]config.ScoringPreset{
+		"zen": {PerfectWindowMS: 100, NiceWindowMS: 200, OKWindowMS: 300},
+	}
+	s.cursor = settingIndex(t, "rhythm_dance_scoring")
+	s.adjust(-1)
+	if s.draft.RhythmDanceScoring != "hardcore" {
+		t.Errorf("scoring = %q, want hardcore before standard", s.draft.RhythmDanceScoring)
+	}
+	s.adjust(1)
+	s.adjust(1)
+	if s.draft.RhythmDanceScoring != "zen" || !s.valid() {
+		t.Errorf("scoring = %q, errors %v; want the custom preset zen", s.draft.RhythmDanceScoring, s.errors)
+	}
+	s.draft.RhythmDanceScoring = config.DefaultScoringPreset
+	s.draft.RhythmDanceScoringPresets = nil
+
+	// 最小时间不能大于初始时间
+	s.cursor = settingIndex(t, "rhythm_min_time_limit")
+	for range 20 {
+		s.adjust(1)
+	}
+	if s.valid() || s.errors["rhythm_min_time_limit"] == "" {
+		t.Errorf("expected live validation error, got %v", s.errors)
+	}
+}
+
+func TestSettingsSaveAppliesAndPersists(t *testing.T) {
+	cfg := config.DefaultConfig()
+	g := game.New()
+	if err := loadGame(g, cfg); err != nil {
+		t.Fatalf("loadGame: %v", err)
+	}
+	m := initialModel(cfg, g, nil, nil, t.TempDir())
+	m.configPath = filepath.Join(t.TempDir(), "word-killer", "config.json")
+	m.settings = newSettings(cfg)
+
+	// 编辑路径：指向不存在的文件时不能保存
+	m.settings.cursor = settingIndex(t, "sentence_dict_path")
+	m.settings.startEdit()
+	m.settings.input = "missing.txt"
+	m.settings.finishEdit(true)
+	m.settings.cursor = m.settings.saveIndex()
+	updated, _ := m.handleSettingsKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.settings == nil || m.settings.status == "" {
+		t.Fatal("invalid settings should not be saved")
+	}
+
+	m.settings.draft.SentenceDictPath = cfg.SentenceDictPath
+	m.settings.draft.RhythmWordsPerLevel = 7
+	m.settings.validate()
+	updated, _ = m.handleSettingsKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.settings != nil {
+		t.Fatalf("save failed: %s", m.settings.status)
+	}
+
+	if cfg.RhythmWordsPerLevel != 7 || g.RhythmWordsPerLevel != 7 {
+		t.Errorf("settings not applied: cfg=%d game=%d", cfg.RhythmWordsPerLevel, g.RhythmWordsPerLevel)
+	}
+	saved, err := config.Load(m.configPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(saved, cfg) {
+		t.Errorf("saved config differs:\ngot  %+v\nwant %+v", saved, cfg)
+	}
+}
+
+func TestSettingsSaveKeepsCommandLineOverridesOut(t *testing.T) {
+	fileCfg := config.DefaultConfig()
+	cfg := *fileCfg
+	o := options{duration: 10, words: 5}
+	o.apply(&cfg)
+
+	g := game.New()
+	if err := loadGame(g, &cfg); err != nil {
+		t.Fatalf("loadGame: %v", err)
+	}
+	m := initialModel(&cfg, g, nil, nil, t.TempDir())
+	m.fileCfg = fileCfg
+	m.cli = o
+	m.configPath = filepath.Join(t.TempDir(), "config.json")
+	m.settings = newSettings(m.fileCfg)
+	m.settings.draft.RhythmWordsPerLevel = 7
+	m.settings.validate()
+	m.settings.cursor = m.settings.saveIndex()
+	updated, _ := m.handleSettingsKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.settings != nil {
+		t.Fatalf("save failed: %s", m.settings.status)
+	}
+
+	saved, err := config.Load(m.configPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	defaults := config.DefaultConfig()
+	if saved.CountdownDuration != defaults.CountdownDuration || saved.RhythmDanceDuration != defaults.RhythmDanceDuration ||
+		saved.WordCount != defaults.WordCount || saved.SpeedRunWordCount != defaults.SpeedRunWordCount {
+		t.Errorf("command line overrides were saved: %+v", saved)
+	}
+	if saved.RhythmWordsPerLevel != 7 {
+		t.Errorf("saved rhythm_words_per_level = %d, want 7", saved.RhythmWordsPerLevel)
+	}
+
+	// 本次运行仍然使用覆盖项
+	if m.cfg.CountdownDuration != 10 || m.cfg.WordCount != 5 || m.cfg.RhythmWordsPerLevel != 7 {
+		t.Errorf("runtime config lost overrides: %+v", *m.cfg)
+	}
+}
+
+func TestSettingsSaveFailureKeepsGameUnchanged(t *testing.T) {
+	cfg := config.DefaultConfig()
+	g := game.New()
+	if err := loadGame(g, cfg); err != nil {
+		t.Fatalf("loadGame: %v", err)
+	}
+	before := g.WordDifficulty("ate")
+
+	// 句子库文件存在（通过校验）但是空的，加载完单词词库之后才失败
+	empty := filepath.Join(t.TempDir(), "empty.txt")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := initialModel(cfg, g, nil, nil, t.TempDir())
+	m.configPath = filepath.Join(t.TempDir(), "config.json")
+	m.settings = newSettings(cfg)
+	m.settings.draft.KeyboardLayout = "dvorak"
+	m.settings.draft.SentenceDictPath = empty
+	m.settings.validate()
+	m.settings.cursor = m.settings.saveIndex()
+	updated, _ := m.handleSettingsKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(model)
+	if m.settings == nil || m.settings.status == "" {
+		t.Fatal("a sentence file that fails to load should keep the settings screen open")
+	}
+
+	// 键盘布局在加载句子库之前已经设置，失败后必须恢复
+	if got := g.WordDifficulty("ate"); got != before {
+		t.Errorf("difficulty of \"ate\" = %v after a failed save, want %v (qwerty)", got, before)
+	}
+	if cfg.KeyboardLayout == "dvorak" {
+		t.Errorf("runtime keyboard layout = %q after a failed save", cfg.KeyboardLayout)
+	}
+	if _, err := os.Stat(m.configPath); err == nil {
+		t.Error("config file written after a failed save")
+	}
+}
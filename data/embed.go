@@ -0,0 +1,62 @@
+// Package data 内置的默认词库、句子库和示例曲谱
+//
+// 词库文件和示例曲谱随程序一起编译进二进制，`go install` 得到的程序不依赖工作目录也能运行。
+package data
+
+import (
+	"embed"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed *.txt *.tsv *.json
+var files embed.FS
+
+// Files 返回内置的词库文件系统
+func Files() fs.FS {
+	return files
+}
+
+// Open 打开词库文件：优先读取磁盘上的文件，不存在时回退到同名的内置词库
+// 只有形如 "data/xxx.txt" 的默认路径才会回退（自定义路径不会被内置文件替代）
+func Open(name string) (io.ReadCloser, error) {
+	f, err := os.Open(name)
+	if err == nil {
+		return f, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	if embedded, ok := embeddedName(name); ok {
+		return files.Open(embedded)
+	}
+	return nil, err
+}
+
+// Exists 检查词库文件是否可以打开（磁盘或内置）
+func Exists(name string) bool {
+	f, err := Open(name)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+
+// embeddedName 把默认路径映射为内置文件名
+func embeddedName(name string) (string, bool) {
+	rel, ok := strings.CutPrefix(filepath.ToSlash(name), "data/")
+	if !ok || rel != path.Base(rel) {
+		return "", false
+	}
+	if _, err := fs.Stat(files, rel); err != nil {
+		return "", false
+	}
+	return rel, true
+}
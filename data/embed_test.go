@@ -0,0 +1,27 @@
+package data
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenFallsBackToEmbedded(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	f, err := Open(filepath.Join("data", "google-10000-short.txt"))
+	if err != nil {
+		t.Fatalf("Open default dictionary: %v", err)
+	}
+	defer f.Close()
+	if b, _ := io.ReadAll(f); len(b) == 0 {
+		t.Error("embedded dictionary is empty")
+	}
+
+	if Exists("custom/google-10000-short.txt") {
+		t.Error("custom paths must not fall back to embedded files")
+	}
+	if Exists("data/missing.txt") {
+		t.Error("unknown file reported as existing")
+	}
+}
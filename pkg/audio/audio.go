@@ -0,0 +1,95 @@
+// Package audio 节奏模式的声音提示
+//
+// 提示（Cue）由 Tracker 根据游戏状态产生：节拍、判定、连击里程碑和时间警告，
+// 再交给可替换的 Player 输出。内置三种后端：不出声的 Null、终端响铃 Bell，
+// 以及调用本地播放器命令播放 WAV 文件的 Command。
+package audio
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// 后端名称（对应配置项 audio_backend）
+const (
+	BackendNone    = "none"
+	BackendBell    = "bell"
+	BackendCommand = "command"
+)
+
+// Backends 所有可用的后端名称
+func Backends() []string {
+	return []string{BackendNone, BackendBell, BackendCommand}
+}
+
+// CueKind 提示类型
+type CueKind int
+
+const (
+	CueBeat        CueKind = iota // 节拍（指针经过黄金点 / 节奏大师新单词开始计时）
+	CueJudgment                   // 判定结果
+	CueCombo                      // 连击里程碑 / 难度升级
+	CueTimeWarning                // 时间快到了
+)
+
+var cueKindNames = map[CueKind]string{
+	CueBeat:        "beat",
+	CueJudgment:    "judgment",
+	CueCombo:       "combo",
+	CueTimeWarning: "warning",
+}
+
+// String 返回提示类型名称，也是默认的声音文件名
+func (k CueKind) String() string {
+	return cueKindNames[k]
+}
+
+// Cue 一次声音提示
+type Cue struct {
+	Kind     CueKind
+	Judgment string // CueJudgment 的判定等级（"Perfect"、"Miss"、"Hit" 等）
+	Combo    int    // CueCombo 达到的连击数或难度等级
+}
+
+// Sound 提示对应的声音名称：判定按等级区分（如 "perfect"），其他按类型
+func (c Cue) Sound() string {
+	if c.Kind == CueJudgment && c.Judgment != "" {
+		return strings.ToLower(c.Judgment)
+	}
+	return c.Kind.String()
+}
+
+// Player 声音输出后端
+// Play 不能阻塞游戏主循环，播放失败时静默忽略（声音只是辅助提示）
+type Player interface {
+	Play(c Cue)
+}
+
+// Null 不输出任何声音
+type Null struct{}
+
+// Play 忽略提示
+func (Null) Play(Cue) {}
+
+// Options 创建后端的参数
+type Options struct {
+	Backend   string    // BackendNone、BackendBell 或 BackendCommand
+	Command   string    // Command 后端的播放器命令，如 "aplay -q"
+	SoundsDir string    // Command 后端的声音文件目录
+	Output    io.Writer // Bell 后端的输出（终端）
+}
+
+// New 按名称创建后端
+func New(o Options) (Player, error) {
+	switch o.Backend {
+	case BackendNone, "":
+		return Null{}, nil
+	case BackendBell:
+		return NewBell(o.Output), nil
+	case BackendCommand:
+		return NewCommand(o.Command, o.SoundsDir)
+	default:
+		return nil, fmt.Errorf("unknown audio backend %q (want one of %s)", o.Backend, strings.Join(Backends(), ", "))
+	}
+}
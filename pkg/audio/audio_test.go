@@ -0,0 +1,160 @@
+package audio
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/word-killer/word-killer/pkg/game"
+)
+
+// newGame 创建使用虚拟时钟和测试词库的游戏
+func newGame(t *testing.T) (*game.Game, *game.ManualClock) {
+	t.Helper()
+	dict := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(dict, []byte("apple\nbrave\ncloud\ndance\neagle\nflame\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	clock := game.NewManualClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	g := game.NewWithClock(clock, 1)
+	if err := g.LoadWordDictionaries(dict, "", "", 1, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	return g, clock
+}
+
+func kinds(cues []Cue) []CueKind {
+	var ks []CueKind
+	for _, c := range cues {
+		ks = append(ks, c.Kind)
+	}
+	return ks
+}
+
+func TestTrackerRhythmDance(t *testing.T) {
+	g, clock := newGame(t)
+	if err := g.StartRhythmDanceMode(15, 0.05, 0); err != nil {
+		t.Fatal(err)
+	}
+	tr := NewTracker()
+	if cues := tr.Update(g); len(cues) != 0 {
+		t.Fatalf("cues at start: %v", cues)
+	}
+
+	// 每秒 0.5 个节奏条，1.236 秒时第一次经过黄金点
+	clock.Advance(1300 * time.Millisecond)
+	if got := kinds(tr.Update(g)); !reflect.DeepEqual(got, []CueKind{CueBeat}) {
+		t.Errorf("after first pass: %v, want a beat", got)
+	}
+	if cues := tr.Update(g); len(cues) != 0 {
+		t.Errorf("beat repeated: %v", cues)
+	}
+
+	// 错误单词判定为 Miss
+	g.AddChar('z')
+	g.TryRhythmJudgment()
+	cues := tr.Update(g)
+	if len(cues) != 1 || cues[0].Sound() != "miss" {
+		t.Errorf("after judging a wrong word: %v, want a miss", cues)
+	}
+
+	// 剩余 10 秒时提示一次
+	clock.Advance(4 * time.Second)
+	var warnings int
+	for _, c := range tr.Update(g) {
+		if c.Kind == CueTimeWarning {
+			warnings++
+		}
+	}
+	tr.Update(g)
+	if warnings != 1 {
+		t.Errorf("%d time warnings, want 1", warnings)
+	}
+}
+
+func TestTrackerRhythmMaster(t *testing.T) {
+	g, clock := newGame(t)
+	g.RhythmInitialTimeLimit = 2
+	g.RhythmMinTimeLimit = 0.5
+	g.RhythmDifficultyStep = 0.5
+	g.RhythmWordsPerLevel = 1
+	if err := g.StartRhythmMasterMode(); err != nil {
+		t.Fatal(err)
+	}
+	tr := NewTracker()
+	if got := kinds(tr.Update(g)); !reflect.DeepEqual(got, []CueKind{CueBeat}) {
+		t.Errorf("first word: %v, want a beat", got)
+	}
+
+	clock.Advance(1500 * time.Millisecond)
+	if got := kinds(tr.Update(g)); !reflect.DeepEqual(got, []CueKind{CueTimeWarning}) {
+		t.Errorf("word almost out of time: %v, want a warning", got)
+	}
+
+	for _, ch := range g.GetActiveWords()[0] {
+		g.AddChar(ch)
+	}
+	g.TryEliminate()
+	want := []CueKind{CueJudgment, CueBeat, CueCombo}
+	if got := kinds(tr.Update(g)); !reflect.DeepEqual(got, want) {
+		t.Errorf("word completed: %v, want %v", got, want)
+	}
+}
+
+func TestCommandSubstitutesSoundFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"beat.wav", "judgment.wav", "perfect.wav"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ran [][]string
+	c, err := NewCommand("paplay --volume={file}", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.run = func(name string, args ...string) error {
+		ran = append(ran, append([]string{name}, args...))
+		return nil
+	}
+	c.Play(Cue{Kind: CueJudgment, Judgment: "Perfect"})
+	c.Play(Cue{Kind: CueJudgment, Judgment: "Nice"}) // 没有 nice.wav，使用 judgment.wav
+	c.Play(Cue{Kind: CueCombo})                      // 没有文件，不出声
+
+	c.args = []string{"aplay", "-q"}
+	c.Play(Cue{Kind: CueBeat})
+
+	want := [][]string{
+		{"paplay", "--volume=" + filepath.Join(dir, "perfect.wav")},
+		{"paplay", "--volume=" + filepath.Join(dir, "judgment.wav")},
+		{"aplay", "-q", filepath.Join(dir, "beat.wav")},
+	}
+	if !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+
+	if _, err := New(Options{Backend: BackendCommand, SoundsDir: dir}); err == nil {
+		t.Error("command backend without a command should fail")
+	}
+}
+
+func TestBellRingsOnBeatsAndJudgments(t *testing.T) {
+	var out bytes.Buffer
+	b := NewBell(&out)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+
+	b.Play(Cue{Kind: CueBeat})
+	b.Play(Cue{Kind: CueJudgment}) // 同一时刻，合并为一次
+	now = now.Add(time.Second)
+	b.Play(Cue{Kind: CueTimeWarning})
+	b.Play(Cue{Kind: CueJudgment})
+
+	if out.String() != "\a\a" {
+		t.Errorf("bell output %q, want two BELs", out.String())
+	}
+}
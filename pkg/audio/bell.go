@@ -0,0 +1,38 @@
+package audio
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// bellInterval 两次响铃的最小间隔，避免同一帧内的多个提示连成一串
+const bellInterval = 80 * time.Millisecond
+
+// Bell 终端响铃后端：在节拍和判定时输出 BEL 字符
+type Bell struct {
+	mu   sync.Mutex
+	out  io.Writer
+	now  func() time.Time
+	last time.Time
+}
+
+// NewBell 创建响铃后端，out 为终端输出
+func NewBell(out io.Writer) *Bell {
+	return &Bell{out: out, now: time.Now}
+}
+
+// Play 节拍和判定时响铃，其他提示不响（终端铃声只有一种，响太多会分不清）
+func (b *Bell) Play(c Cue) {
+	if c.Kind != CueBeat && c.Kind != CueJudgment {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if now.Sub(b.last) < bellInterval {
+		return
+	}
+	b.last = now
+	b.out.Write([]byte("\a"))
+}
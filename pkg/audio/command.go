@@ -0,0 +1,82 @@
+package audio
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// fileToken 命令中代表声音文件的占位符；命令中没有占位符时把文件路径追加在最后
+const fileToken = "{file}"
+
+// Command 调用本地播放器命令播放 WAV 提示音
+// 声音文件按提示命名放在 SoundsDir 中：beat.wav、perfect.wav、nice.wav、ok.wav、
+// miss.wav、hit.wav、combo.wav、warning.wav；判定等级没有单独的文件时使用 judgment.wav，
+// 找不到文件的提示不出声
+type Command struct {
+	args []string
+	dir  string
+	run  func(name string, args ...string) error
+}
+
+// NewCommand 创建命令后端，command 如 "aplay -q" 或 "afplay {file}"
+func NewCommand(command, soundsDir string) (*Command, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("audio command is empty")
+	}
+	if soundsDir == "" {
+		return nil, fmt.Errorf("audio sounds directory is empty")
+	}
+	return &Command{args: args, dir: soundsDir, run: startDetached}, nil
+}
+
+// Play 在后台启动播放器，不等待播放结束
+func (c *Command) Play(cue Cue) {
+	file := c.soundFile(cue)
+	if file == "" {
+		return
+	}
+	args := c.commandLine(file)
+	c.run(args[0], args[1:]...)
+}
+
+// soundFile 查找提示对应的声音文件，没有时返回空
+func (c *Command) soundFile(cue Cue) string {
+	for _, name := range []string{cue.Sound(), cue.Kind.String()} {
+		path := filepath.Join(c.dir, name+".wav")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// commandLine 把声音文件代入命令
+func (c *Command) commandLine(file string) []string {
+	args := make([]string, 0, len(c.args)+1)
+	replaced := false
+	for _, a := range c.args {
+		if strings.Contains(a, fileToken) {
+			a = strings.ReplaceAll(a, fileToken, file)
+			replaced = true
+		}
+		args = append(args, a)
+	}
+	if !replaced {
+		args = append(args, file)
+	}
+	return args
+}
+
+// startDetached 启动进程并在后台回收，不占用终端输入输出
+func startDetached(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,116 @@
+package audio
+
+import (
+	"time"
+
+	"github.com/word-killer/word-killer/pkg/game"
+)
+
+const (
+	// comboMilestone 节奏舞蹈每达到这么多连击提示一次
+	comboMilestone = 10
+
+	// danceWarning 节奏舞蹈剩余时间少于此值时提示（与界面变红的时间一致）
+	danceWarning = 10 * time.Second
+
+	// wordWarningRatio 节奏大师单词剩余时间低于时限的这个比例时提示
+	wordWarningRatio = 0.3
+)
+
+// Tracker 比较游戏状态的变化，产生声音提示
+// 只处理节奏大师和节奏舞蹈模式；每帧调用一次 Update
+type Tracker struct {
+	start  time.Time     // 当前对局的开始时间，变化时说明开始了新的一局
+	events int           // 已处理的事件数
+	beat   int           // 已提示的节拍编号
+	word   time.Duration // 已提示的节奏大师单词开始时间
+	level  int           // 已提示的难度等级
+	warned bool          // 本局（节奏大师为本单词）是否已经提示过时间警告
+}
+
+// NewTracker 创建提示跟踪器
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+// Update 返回自上次调用以来产生的提示
+func (t *Tracker) Update(g *game.Game) []Cue {
+	if g.Mode != game.ModeRhythmMaster && g.Mode != game.ModeRhythmDance {
+		return nil
+	}
+
+	events := g.Events()
+	if !g.Stats.StartTime.Equal(t.start) || len(events) < t.events {
+		// 新的一局：跳过开局时已经存在的状态
+		*t = Tracker{start: g.Stats.StartTime, word: -1}
+	}
+
+	var cues []Cue
+	for _, e := range events[t.events:] {
+		switch {
+		case e.Type == game.EventJudgment:
+			cues = append(cues, Cue{Kind: CueJudgment, Judgment: e.Text})
+		case e.Type == game.EventWordCompleted && g.Mode == game.ModeRhythmMaster:
+			cues = append(cues, Cue{Kind: CueJudgment, Judgment: "Hit"})
+		}
+	}
+	t.events = len(events)
+
+	if g.Status != game.StatusRunning {
+		return cues
+	}
+
+	switch g.Mode {
+	case game.ModeRhythmDance:
+		cues = append(cues, t.updateDance(g)...)
+	case game.ModeRhythmMaster:
+		cues = append(cues, t.updateMaster(g)...)
+	}
+	return cues
+}
+
+// updateDance 节奏舞蹈：节拍、连击里程碑、剩余时间
+func (t *Tracker) updateDance(g *game.Game) []Cue {
+	var cues []Cue
+	state := g.RhythmDanceState
+
+	if beat := g.RhythmBeat(); beat > t.beat {
+		t.beat = beat
+		cues = append(cues, Cue{Kind: CueBeat})
+	}
+
+	milestone := state.CurrentCombo / comboMilestone * comboMilestone
+	if milestone > t.level {
+		cues = append(cues, Cue{Kind: CueCombo, Combo: milestone})
+	}
+	t.level = milestone
+
+	if !t.warned && state.Duration-g.PlayTime() <= danceWarning {
+		t.warned = true
+		cues = append(cues, Cue{Kind: CueTimeWarning})
+	}
+	return cues
+}
+
+// updateMaster 节奏大师：每个单词开始计时算一拍，难度升级，单词快超时
+func (t *Tracker) updateMaster(g *game.Game) []Cue {
+	var cues []Cue
+
+	if g.CurrentWordStart != t.word {
+		t.word = g.CurrentWordStart
+		t.warned = false
+		cues = append(cues, Cue{Kind: CueBeat})
+	}
+
+	if g.DifficultyLevel > t.level {
+		t.level = g.DifficultyLevel
+		cues = append(cues, Cue{Kind: CueCombo, Combo: g.DifficultyLevel})
+	}
+
+	limit := g.WordTimeLimit
+	if !t.warned && limit > 0 && g.GetWordRemaining() <= time.Duration(float64(limit)*wordWarningRatio) {
+		t.warned = true
+		cues = append(cues, Cue{Kind: CueTimeWarning})
+	}
+	return cues
+}
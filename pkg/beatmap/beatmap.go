@@ -0,0 +1,174 @@
+// Package beatmap 节奏舞蹈模式的曲谱文件
+//
+// 曲谱只描述节拍的时间：BPM、第一拍的偏移和按拍分段的变速，不包含也不需要音频。
+// 同一份曲谱在任何机器上都得到相同的节拍时间，可以作为"歌曲"分享。
+package beatmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+)
+
+// maxBPM 允许的最大 BPM（指针每拍扫过一次节奏条，再快就看不清了）
+const maxBPM = 400
+
+// Section 从某一拍开始的变速段
+type Section struct {
+	Beat float64 `json:"beat"`           // 从第几拍开始（第一拍为 0）
+	BPM  float64 `json:"bpm"`            // 本段的速度
+	Name string  `json:"name,omitempty"` // 段落名称（如 "Chorus"），显示在界面上
+}
+
+// Beatmap 一首曲谱
+type Beatmap struct {
+	Title    string    `json:"title"`
+	Artist   string    `json:"artist,omitempty"`
+	BPM      float64   `json:"bpm"`             // 初始速度
+	OffsetMS int       `json:"offset_ms"`       // 第一拍距离开局的时间（毫秒）
+	Beats    int       `json:"beats,omitempty"` // 总拍数，0 表示不限（按配置的时长结束）
+	Sections []Section `json:"sections,omitempty"`
+
+	segments []segment // 按时间排好的变速段，由 Parse 计算
+}
+
+// segment 一段匀速区间的起点
+type segment struct {
+	beat  float64
+	start time.Duration
+	bpm   float64
+	name  string
+}
+
+// Load 读取曲谱文件
+func Load(path string) (*Beatmap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open beatmap: %w", err)
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read 从 r 读取曲谱
+func Read(r io.Reader) (*Beatmap, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read beatmap: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse 解析并校验曲谱
+func Parse(data []byte) (*Beatmap, error) {
+	var b Beatmap
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse beatmap: %w", err)
+	}
+	if err := b.build(); err != nil {
+		return nil, fmt.Errorf("invalid beatmap: %w", err)
+	}
+	return &b, nil
+}
+
+// build 校验字段并计算每个变速段开始的时间
+func (b *Beatmap) build() error {
+	if b.BPM <= 0 || b.BPM > maxBPM {
+		return fmt.Errorf("bpm must be in (0, %d], got %g", maxBPM, b.BPM)
+	}
+	if b.OffsetMS < 0 {
+		return fmt.Errorf("offset_ms must be >= 0, got %d", b.OffsetMS)
+	}
+	if b.Beats < 0 {
+		return fmt.Errorf("beats must be >= 0, got %d", b.Beats)
+	}
+
+	b.segments = []segment{{beat: 0, start: b.Offset(), bpm: b.BPM}}
+	for i, s := range b.Sections {
+		if s.BPM <= 0 || s.BPM > maxBPM {
+			return fmt.Errorf("sections[%d]: bpm must be in (0, %d], got %g", i, maxBPM, s.BPM)
+		}
+		if s.Beat < 0 {
+			return fmt.Errorf("sections[%d]: beat must be >= 0, got %g", i, s.Beat)
+		}
+		if i > 0 && s.Beat <= b.Sections[i-1].Beat {
+			return fmt.Errorf("sections[%d]: beat %g must be after beat %g", i, s.Beat, b.Sections[i-1].Beat)
+		}
+		prev := b.segments[len(b.segments)-1]
+		seg := segment{beat: s.Beat, start: prev.timeOf(s.Beat), bpm: s.BPM, name: s.Name}
+		if s.Beat == 0 {
+			// 从第一拍开始的段落替换初始速度
+			b.segments[0] = seg
+			continue
+		}
+		b.segments = append(b.segments, seg)
+	}
+	return nil
+}
+
+// timeOf 按本段速度计算某一拍的时间
+func (s segment) timeOf(beat float64) time.Duration {
+	return s.start + time.Duration((beat-s.beat)*60/s.bpm*float64(time.Second))
+}
+
+// Offset 第一拍的时间
+func (b *Beatmap) Offset() time.Duration {
+	return time.Duration(b.OffsetMS) * time.Millisecond
+}
+
+// segmentAt 返回时刻 t 所在的变速段（第一拍之前算作第一段）
+func (b *Beatmap) segmentAt(t time.Duration) segment {
+	seg := b.segments[0]
+	for _, s := range b.segments[1:] {
+		if s.start > t {
+			break
+		}
+		seg = s
+	}
+	return seg
+}
+
+// BeatAt 时刻 t 对应的拍数（连续值，整数为正拍；第一拍之前为负数）
+func (b *Beatmap) BeatAt(t time.Duration) float64 {
+	seg := b.segmentAt(t)
+	return seg.beat + (t-seg.start).Minutes()*seg.bpm
+}
+
+// TimeOf 第 beat 拍的时刻
+func (b *Beatmap) TimeOf(beat float64) time.Duration {
+	seg := b.segments[0]
+	for _, s := range b.segments[1:] {
+		if s.beat > beat {
+			break
+		}
+		seg = s
+	}
+	return seg.timeOf(beat)
+}
+
+// NearestBeat 距离时刻 t 最近的正拍及其时刻
+func (b *Beatmap) NearestBeat(t time.Duration) (float64, time.Duration) {
+	beat := math.Round(b.BeatAt(t))
+	return beat, b.TimeOf(beat)
+}
+
+// BPMAt 时刻 t 的速度
+func (b *Beatmap) BPMAt(t time.Duration) float64 {
+	return b.segmentAt(t).bpm
+}
+
+// SectionAt 时刻 t 所在段落的名称（没有命名时为空）
+func (b *Beatmap) SectionAt(t time.Duration) string {
+	return b.segmentAt(t).name
+}
+
+// Duration 整首曲谱的时长（最后一拍的时刻）；Beats 为 0 时返回 0
+func (b *Beatmap) Duration() time.Duration {
+	if b.Beats == 0 {
+		return 0
+	}
+	return b.TimeOf(float64(b.Beats))
+}
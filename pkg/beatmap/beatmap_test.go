@@ -0,0 +1,73 @@
+package beatmap
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const song = `{
+	"title": "Test Song",
+	"bpm": 120,
+	"offset_ms": 1000,
+	"beats": 24,
+	"sections": [
+		{"beat": 8, "bpm": 60, "name": "Bridge"},
+		{"beat": 16, "bpm": 240}
+	]
+}`
+
+func TestBeatTiming(t *testing.T) {
+	b, err := Parse([]byte(song))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 120 BPM 每拍 500ms，第 8 拍之后 60 BPM 每拍 1s，第 16 拍之后 240 BPM 每拍 250ms
+	beats := []struct {
+		beat float64
+		at   time.Duration
+	}{
+		{-1, 500 * time.Millisecond},
+		{0, time.Second},
+		{8, 5 * time.Second},
+		{10, 7 * time.Second},
+		{16, 13 * time.Second},
+		{24, 15 * time.Second},
+	}
+	for _, tt := range beats {
+		if got := b.TimeOf(tt.beat); got != tt.at {
+			t.Errorf("TimeOf(%g) = %v, want %v", tt.beat, got, tt.at)
+		}
+		if got := b.BeatAt(tt.at); got != tt.beat {
+			t.Errorf("BeatAt(%v) = %g, want %g", tt.at, got, tt.beat)
+		}
+	}
+
+	if got := b.BPMAt(6 * time.Second); got != 60 {
+		t.Errorf("BPMAt(6s) = %g, want 60", got)
+	}
+	if got := b.SectionAt(6 * time.Second); got != "Bridge" {
+		t.Errorf("SectionAt(6s) = %q, want Bridge", got)
+	}
+	if beat, at := b.NearestBeat(7400 * time.Millisecond); beat != 10 || at != 7*time.Second {
+		t.Errorf("NearestBeat(7.4s) = %g at %v, want 10 at 7s", beat, at)
+	}
+	if got := b.Duration(); got != 15*time.Second {
+		t.Errorf("Duration() = %v, want 15s", got)
+	}
+}
+
+func TestParseRejectsInvalidBeatmaps(t *testing.T) {
+	tests := map[string]string{
+		"missing bpm":       `{"title": "x"}`,
+		"negative offset":   `{"bpm": 100, "offset_ms": -5}`,
+		"unordered section": `{"bpm": 100, "sections": [{"beat": 8, "bpm": 90}, {"beat": 4, "bpm": 80}]}`,
+		"section bpm":       `{"bpm": 100, "sections": [{"beat": 8, "bpm": 0}]}`,
+	}
+	for name, data := range tests {
+		if _, err := Parse([]byte(data)); err == nil || !strings.Contains(err.Error(), "invalid beatmap") {
+			t.Errorf("%s: expected validation error, got %v", name, err)
+		}
+	}
+}
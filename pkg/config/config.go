@@ -4,6 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 )
 
 // Config game configuration
@@ -16,20 +20,49 @@ type Config struct {
 	MediumDictPath string `json:"medium_dict_path"`
 	LongDictPath   string `json:"long_dict_path"`
 
+	// 词库的字母表：latin（仅 a-z）、latin-extended、cyrillic、greek、any
+	// 含有字母表以外字符的单词在加载时跳过
+	ShortDictAlphabet  string `json:"short_dict_alphabet"`
+	MediumDictAlphabet string `json:"medium_dict_alphabet"`
+	LongDictAlphabet   string `json:"long_dict_alphabet"`
+
+	// 单个词库（设置后代替上面三个难度词库，单词按长度分为短、中、长）
+	DictionaryPath string `json:"dictionary_path"`
+
+	// 从结构化词库中挑选单词：带有其中任一标签的单词、词频排名在前 N 的单词（0 表示不限）
+	DictionaryTags    []string `json:"dictionary_tags"`
+	DictionaryMaxRank int      `json:"dictionary_max_rank"`
+
+	// 词库包目录（空表示数据目录下的 packs），Pack 为当前使用的词库包文件（空表示使用上面的词库）
+	// 词库包在模式选择界面切换
+	PacksDir string `json:"packs_dir"`
+	Pack     string `json:"pack"`
+
 	// Difficulty ratios (will be normalized to percentages)
 	ShortRatio  float64 `json:"short_ratio"`
 	MediumRatio float64 `json:"medium_ratio"`
 	LongRatio   float64 `json:"long_ratio"`
 
+	// 目标难度（0-100，按长度、稀有字母、同指二连键、左右手交替和跨排计算；0 表示按上面的长度配比选词）
+	// 设置后三个词库合并为一个，按单词难度与目标的接近程度选词
+	DifficultyTarget int    `json:"difficulty_target"`
+	KeyboardLayout   string `json:"keyboard_layout"` // 计算难度用的键盘布局：qwerty、dvorak、colemak
+
 	// Sentence mode dictionary path
 	SentenceDictPath string `json:"sentence_dict_path"`
 
+	// Romanization mode dictionary path（每行 "中日文<TAB>拼音/罗马字"）
+	RomanizationDictPath string `json:"romanization_dict_path"`
+
 	// Time-challenge mode settings
 	CountdownDuration int `json:"countdown_duration"` // 倒计时模式时长（秒），默认60
 
 	// Speed Run mode settings
 	SpeedRunWordCount int `json:"speedrun_word_count"` // 极速模式单词数量
 
+	// Spaced-repetition review settings
+	ReviewMix int `json:"review_mix"` // 经典和倒计时模式中混入的待复习单词比例（百分比，0 表示不混入）
+
 	// Rhythm Master mode settings
 	RhythmInitialTimeLimit float64 `json:"rhythm_initial_time_limit"` // 节奏大师初始时间限制（秒）
 	RhythmMinTimeLimit     float64 `json:"rhythm_min_time_limit"`     // 节奏大师最小时间限制（秒）
@@ -37,9 +70,26 @@ type Config struct {
 	RhythmWordsPerLevel    int     `json:"rhythm_words_per_level"`    // 节奏大师每级所需单词数
 
 	// Rhythm Dance mode settings
-	RhythmDanceDuration      int     `json:"rhythm_dance_duration"`        // 节奏舞蹈模式时长（秒）
-	RhythmDanceInitialSpeed  float64 `json:"rhythm_dance_initial_speed"`   // 节奏舞蹈指针初始速度
+	RhythmDanceDuration       int     `json:"rhythm_dance_duration"`        // 节奏舞蹈模式时长（秒）
+	RhythmDanceInitialSpeed   float64 `json:"rhythm_dance_initial_speed"`   // 节奏舞蹈指针初始速度
 	RhythmDanceSpeedIncrement float64 `json:"rhythm_dance_speed_increment"` // 每完成一个单词的速度增量
+
+	// Rhythm Dance scoring rules
+	RhythmDanceScoring        string                   `json:"rhythm_dance_scoring"`                   // 计分规则名称：standard、casual、hardcore 或自定义
+	RhythmDanceScoringPresets map[string]ScoringPreset `json:"rhythm_dance_scoring_presets,omitempty"` // 自定义计分规则
+
+	// Rhythm Dance judgment window overrides (milliseconds either side of the golden point, 0 = use the scoring preset)
+	RhythmDancePerfectWindow int `json:"rhythm_dance_perfect_window_ms"` // Perfect 判定窗口（毫秒）
+	RhythmDanceNiceWindow    int `json:"rhythm_dance_nice_window_ms"`    // Nice 判定窗口（毫秒）
+	RhythmDanceOKWindow      int `json:"rhythm_dance_ok_window_ms"`      // OK 判定窗口（毫秒）
+
+	// Rhythm Dance beatmap (empty: pointer sweeps at rhythm_dance_initial_speed)
+	RhythmDanceBeatmapPath string `json:"rhythm_dance_beatmap_path"` // 节奏舞蹈曲谱文件
+
+	// Audio cues for rhythm modes
+	AudioBackend   string `json:"audio_backend"`    // 声音提示后端：none、bell、command
+	AudioCommand   string `json:"audio_command"`    // command 后端的播放器命令，如 "aplay -q"
+	AudioSoundsDir string `json:"audio_sounds_dir"` // command 后端的 WAV 文件目录
 }
 
 // DefaultConfig returns default configuration
@@ -53,6 +103,14 @@ func DefaultConfig() *Config {
 		MediumRatio:      50,
 		LongRatio:        20,
 		SentenceDictPath: "data/sentences.txt",
+		// 内置词库只有英文单词
+		ShortDictAlphabet:  "latin",
+		MediumDictAlphabet: "latin",
+		LongDictAlphabet:   "latin",
+		// 罗马字模式默认使用汉语拼音词库（日语罗马字词库为 data/romaji.tsv）
+		RomanizationDictPath: "data/pinyin.tsv",
+		// 默认按长度配比选词
+		KeyboardLayout: "qwerty",
 		// Time-challenge mode defaults
 		CountdownDuration: 60, // 默认60秒
 		// Speed Run mode defaults
@@ -63,36 +121,98 @@ func DefaultConfig() *Config {
 		RhythmDifficultyStep:   0.1,  // 每级减少0.1秒
 		RhythmWordsPerLevel:    10,   // 每10个词升级
 		// Rhythm Dance mode defaults
-		RhythmDanceDuration:       60,    // 默认60秒
-		RhythmDanceInitialSpeed:   0.05,  // 初始速度0.05
-		RhythmDanceSpeedIncrement: 0.005, // 每完成一个单词增加0.005
+		RhythmDanceDuration:       60,                   // 默认60秒
+		RhythmDanceInitialSpeed:   0.05,                 // 初始速度0.05
+		RhythmDanceSpeedIncrement: 0.005,                // 每完成一个单词增加0.005
+		RhythmDanceScoring:        DefaultScoringPreset, // 标准计分规则（判定窗口 50/120/230ms）
+		// Audio defaults
+		AudioBackend: "none", // 默认不出声
 	}
 }
 
 // Load loads configuration file
+// 文件中的配置项合并到 DefaultConfig() 之上，缺省的项保留默认值；
+// 以 "_comment" 开头的键作为注释忽略，其他未知键和非法值都会报错
 func Load(path string) (*Config, error) {
 	// Use default config if file doesn't exist
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return DefaultConfig(), nil
 	}
 
-	file, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open config file: %w", err)
 	}
-	defer file.Close()
 
-	var cfg Config
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&cfg); err != nil {
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse 解析 JSON 配置：合并默认值、拒绝未知键并校验
+func Parse(data []byte) (*Config, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
-	return &cfg, nil
+	known := jsonKeys()
+	fields := make(map[string]json.RawMessage, len(raw))
+	var problems []FieldError
+	for key, value := range raw {
+		switch {
+		case strings.HasPrefix(key, "_comment"):
+			// 注释
+		case known[key]:
+			fields[key] = value
+		default:
+			problems = append(problems, FieldError{Field: key, Message: "unknown key"})
+		}
+	}
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Field < problems[j].Field })
+
+	cfg := DefaultConfig()
+	merged, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if err := json.Unmarshal(merged, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		problems = append(problems, err.(*ValidationError).Errors...)
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Errors: problems}
+	}
+	return cfg, nil
+}
+
+// jsonKeys 返回 Config 的所有 JSON 键
+func jsonKeys() map[string]bool {
+	keys := make(map[string]bool)
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
 }
 
 // Save saves configuration to file
+// 目录不存在时自动创建
 func Save(cfg *Config, path string) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create config directory: %w", err)
+		}
+	}
+
 	file, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("failed to create config file: %w", err)
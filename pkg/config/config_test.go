@@ -1,6 +1,10 @@
 package config
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -110,3 +114,139 @@ func abs(x float64) float64 {
 	}
 	return x
 }
+
+func TestLoadMergesDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"_comment_words": "partial config", "word_count": 50}`), 0o644)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := DefaultConfig()
+	want.WordCount = 50
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("partial config not merged onto defaults:\ngot  %+v\nwant %+v", cfg, want)
+	}
+}
+
+func TestParseReportsEveryProblem(t *testing.T) {
+	_, err := Parse([]byte(`{
+		"wrod_count": 10,
+		"rhythm_words_per_level": 0,
+		"rhythm_min_time_limit": 3,
+		"rhythm_dance_initial_speed": 0,
+		"rhythm_dance_nice_window_ms": 300,
+		"medium_dict_alphabet": "klingon",
+		"difficulty_target": 120,
+		"keyboard_layout": "azerty"
+	}`))
+
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	got := make(map[string]bool)
+	for _, fe := range invalid.Errors {
+		got[fe.Field] = true
+	}
+	for _, field := range []string{"wrod_count", "rhythm_words_per_level", "rhythm_min_time_limit", "rhythm_dance_initial_speed", "rhythm_dance_nice_window_ms", "medium_dict_alphabet", "difficulty_target", "keyboard_layout"} {
+		if !got[field] {
+			t.Errorf("missing error for %s in %v", field, invalid.Errors)
+		}
+	}
+}
+
+func TestValidateDictionaryRequirements(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.LongDictPath = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error: long_ratio > 0 without long_dict_path")
+	}
+
+	cfg.LongRatio = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unused dictionary path should be optional: %v", err)
+	}
+
+	// 按目标难度选词时长度配比可以全为 0
+	cfg.ShortRatio, cfg.MediumRatio = 0, 0
+	cfg.DifficultyTarget = 40
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("ratios should be optional with a difficulty target: %v", err)
+	}
+}
+
+func TestExampleConfigIsValid(t *testing.T) {
+	if _, err := Load(filepath.Join("..", "..", "config.example.json")); err != nil {
+		t.Errorf("config.example.json: %v", err)
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "word-killer", "config.json")
+	cfg := DefaultConfig()
+	cfg.CountdownDuration = 90
+	cfg.LongRatio = 0
+
+	if err := Save(cfg, path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, cfg) {
+		t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", loaded, cfg)
+	}
+}
+
+func TestScoringRules(t *testing.T) {
+	cfg := DefaultConfig()
+	rules, err := cfg.ScoringRules()
+	if err != nil || rules.PerfectWindowMS != 50 || rules.MissLimit != 10 || !rules.OKBreaksCombo {
+		t.Errorf("default rules = %+v, %v; want standard", rules, err)
+	}
+
+	// 判定窗口覆盖只替换不为 0 的窗口
+	cfg.RhythmDanceScoring = "hardcore"
+	cfg.RhythmDanceOKWindow = 150
+	rules, err = cfg.ScoringRules()
+	if err != nil || rules.PerfectWindowMS != 25 || rules.OKWindowMS != 150 || rules.MissLimit != 3 {
+		t.Errorf("hardcore with ok override = %+v, %v", rules, err)
+	}
+
+	cfg = DefaultConfig()
+	cfg.RhythmDanceScoring = "zen"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error: unknown scoring preset")
+	}
+}
+
+func TestParseCustomScoringPreset(t *testing.T) {
+	cfg, err := Parse([]byte(`{
+		"rhythm_dance_scoring": "zen",
+		"rhythm_dance_scoring_presets": {
+			"zen": {"perfect_window_ms": 100, "nice_window_ms": 200, "ok_window_ms": 300, "perfect_points": 1, "miss_limit": 0}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if names := cfg.ScoringPresetNames(); !reflect.DeepEqual(names, []string{"casual", "hardcore", "standard", "zen"}) {
+		t.Errorf("ScoringPresetNames = %v", names)
+	}
+	if rules, _ := cfg.ScoringRules(); rules.OKWindowMS != 300 || rules.PerfectPoints != 1 {
+		t.Errorf("zen rules = %+v", rules)
+	}
+
+	_, err = Parse([]byte(`{
+		"rhythm_dance_scoring_presets": {
+			"broken": {"perfect_window_ms": 0, "nice_window_ms": 200, "ok_window_ms": 300, "miss_limit": -1}
+		}
+	}`))
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) || len(invalid.Errors) != 2 || invalid.Errors[0].Field != "rhythm_dance_scoring_presets" {
+		t.Errorf("expected two preset errors, got %v", err)
+	}
+}
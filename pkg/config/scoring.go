@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultScoringPreset 默认的节奏舞蹈计分规则
+const DefaultScoringPreset = "standard"
+
+// ScoringPreset 节奏舞蹈计分规则：判定窗口、得分、连击倍率、Miss 上限
+type ScoringPreset struct {
+	// 判定窗口（毫秒，黄金点单侧）
+	PerfectWindowMS int `json:"perfect_window_ms"`
+	NiceWindowMS    int `json:"nice_window_ms"`
+	OKWindowMS      int `json:"ok_window_ms"`
+
+	// 各判定的得分（Miss 通常为负数）
+	PerfectPoints int `json:"perfect_points"`
+	NicePoints    int `json:"nice_points"`
+	OKPoints      int `json:"ok_points"`
+	MissPoints    int `json:"miss_points"`
+
+	// 连击倍率：连击数达到 Combo 后得分乘以 Multiplier（按 Combo 递增排列）
+	ComboMultipliers []ComboMultiplier `json:"combo_multipliers,omitempty"`
+
+	MissLimit     int  `json:"miss_limit"`      // Miss 达到此次数时游戏结束，0 表示不限
+	OKBreaksCombo bool `json:"ok_breaks_combo"` // OK 是否中断连击
+}
+
+// ComboMultiplier 连击倍率的一档
+type ComboMultiplier struct {
+	Combo      int     `json:"combo"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// builtinScoringPresets 内置的计分规则；standard 与旧版本的固定规则一致
+var builtinScoringPresets = map[string]ScoringPreset{
+	"standard": {
+		PerfectWindowMS: 50, NiceWindowMS: 120, OKWindowMS: 230,
+		PerfectPoints: 5, NicePoints: 3, OKPoints: 1, MissPoints: -1,
+		MissLimit:     10,
+		OKBreaksCombo: true,
+	},
+	"casual": {
+		PerfectWindowMS: 80, NiceWindowMS: 160, OKWindowMS: 300,
+		PerfectPoints: 5, NicePoints: 3, OKPoints: 2, MissPoints: 0,
+		MissLimit:     0,
+		OKBreaksCombo: false,
+	},
+	"hardcore": {
+		PerfectWindowMS: 25, NiceWindowMS: 60, OKWindowMS: 120,
+		PerfectPoints: 5, NicePoints: 3, OKPoints: 1, MissPoints: -3,
+		ComboMultipliers: []ComboMultiplier{{Combo: 10, Multiplier: 1.5}, {Combo: 25, Multiplier: 2}, {Combo: 50, Multiplier: 3}},
+		MissLimit:        3,
+		OKBreaksCombo:    true,
+	},
+}
+
+// ScoringPresetNames 返回所有可用的计分规则名称：内置规则和配置文件中自定义的规则
+func (c *Config) ScoringPresetNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, presets := range []map[string]ScoringPreset{builtinScoringPresets, c.RhythmDanceScoringPresets} {
+		for name := range presets {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ScoringRules 返回当前使用的计分规则
+// 自定义规则优先于同名的内置规则；名称为空（旧版本保存的回放配置）时使用默认规则；
+// rhythm_dance_*_window_ms 不为 0 时覆盖规则中的窗口
+func (c *Config) ScoringRules() (ScoringPreset, error) {
+	name := c.RhythmDanceScoring
+	if name == "" {
+		name = DefaultScoringPreset
+	}
+	p, ok := c.RhythmDanceScoringPresets[name]
+	if !ok {
+		p, ok = builtinScoringPresets[name]
+	}
+	if !ok {
+		return ScoringPreset{}, fmt.Errorf("unknown scoring preset %q", name)
+	}
+	if c.RhythmDancePerfectWindow > 0 {
+		p.PerfectWindowMS = c.RhythmDancePerfectWindow
+	}
+	if c.RhythmDanceNiceWindow > 0 {
+		p.NiceWindowMS = c.RhythmDanceNiceWindow
+	}
+	if c.RhythmDanceOKWindow > 0 {
+		p.OKWindowMS = c.RhythmDanceOKWindow
+	}
+	return p, nil
+}
+
+// problems 检查计分规则本身（不含窗口的大小关系，由 Validate 结合覆盖项检查）
+func (p ScoringPreset) problems() []string {
+	var msgs []string
+	if p.PerfectWindowMS < 1 || p.NiceWindowMS < 1 || p.OKWindowMS < 1 {
+		msgs = append(msgs, fmt.Sprintf("judgment windows must be >= 1 ms, got %d/%d/%d",
+			p.PerfectWindowMS, p.NiceWindowMS, p.OKWindowMS))
+	}
+	if p.MissLimit < 0 {
+		msgs = append(msgs, fmt.Sprintf("miss_limit must be >= 0, got %d", p.MissLimit))
+	}
+	for i, m := range p.ComboMultipliers {
+		if m.Combo < 1 || m.Multiplier <= 0 {
+			msgs = append(msgs, fmt.Sprintf("combo_multipliers[%d] needs combo >= 1 and multiplier > 0", i))
+		}
+		if i > 0 && m.Combo <= p.ComboMultipliers[i-1].Combo {
+			msgs = append(msgs, "combo_multipliers must be sorted by combo")
+		}
+	}
+	return msgs
+}
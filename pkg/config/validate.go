@@ -0,0 +1,187 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError 单个配置项的错误
+type FieldError struct {
+	Field   string // JSON 键名，如 "rhythm_words_per_level"
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError 配置校验错误，包含所有出错的配置项
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return fmt.Sprintf("invalid config (%d problem(s)): %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// validator 收集字段错误
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) addf(field, format string, args ...any) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) minInt(field string, value, min int) {
+	if value < min {
+		v.addf(field, "must be >= %d, got %d", min, value)
+	}
+}
+
+func (v *validator) positive(field string, value float64) {
+	if value <= 0 {
+		v.addf(field, "must be > 0, got %g", value)
+	}
+}
+
+func (v *validator) nonNegative(field string, value float64) {
+	if value < 0 {
+		v.addf(field, "must be >= 0, got %g", value)
+	}
+}
+
+// Validate 检查所有配置项的取值范围和相互关系，一次报告全部问题
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	v.minInt("word_count", c.WordCount, 0)
+
+	// 难度配比与词库
+	v.nonNegative("short_ratio", c.ShortRatio)
+	v.nonNegative("medium_ratio", c.MediumRatio)
+	v.nonNegative("long_ratio", c.LongRatio)
+	if c.ShortRatio >= 0 && c.MediumRatio >= 0 && c.LongRatio >= 0 &&
+		c.ShortRatio+c.MediumRatio+c.LongRatio <= 0 && c.DifficultyTarget == 0 {
+		v.addf("short_ratio", "at least one of short_ratio, medium_ratio, long_ratio must be > 0")
+	}
+	for _, d := range []struct {
+		ratioField, pathField string
+		ratio                 float64
+		path                  string
+	}{
+		{"short_ratio", "short_dict_path", c.ShortRatio, c.ShortDictPath},
+		{"medium_ratio", "medium_dict_path", c.MediumRatio, c.MediumDictPath},
+		{"long_ratio", "long_dict_path", c.LongRatio, c.LongDictPath},
+	} {
+		if d.ratio > 0 && d.path == "" && c.DictionaryPath == "" && c.Pack == "" {
+			v.addf(d.pathField, "required when %s > 0 (or set dictionary_path)", d.ratioField)
+		}
+	}
+
+	v.minInt("dictionary_max_rank", c.DictionaryMaxRank, 0)
+
+	// 词库字母表（名称与 pkg/game 一致）
+	for _, a := range []struct{ field, name string }{
+		{"short_dict_alphabet", c.ShortDictAlphabet},
+		{"medium_dict_alphabet", c.MediumDictAlphabet},
+		{"long_dict_alphabet", c.LongDictAlphabet},
+	} {
+		switch a.name {
+		case "", "latin", "latin-extended", "cyrillic", "greek", "any":
+		default:
+			v.addf(a.field, "must be one of latin, latin-extended, cyrillic, greek, any, got %q", a.name)
+		}
+	}
+
+	// 目标难度（键盘布局名称与 pkg/game 一致）
+	v.minInt("difficulty_target", c.DifficultyTarget, 0)
+	if c.DifficultyTarget > 100 {
+		v.addf("difficulty_target", "must be <= 100, got %d", c.DifficultyTarget)
+	}
+	switch c.KeyboardLayout {
+	case "", "qwerty", "dvorak", "colemak":
+	default:
+		v.addf("keyboard_layout", "must be one of qwerty, dvorak, colemak, got %q", c.KeyboardLayout)
+	}
+
+	// 倒计时 / 极速
+	v.minInt("countdown_duration", c.CountdownDuration, 1)
+	v.minInt("speedrun_word_count", c.SpeedRunWordCount, 1)
+
+	// 间隔复习（百分比）
+	v.minInt("review_mix", c.ReviewMix, 0)
+	if c.ReviewMix > 100 {
+		v.addf("review_mix", "must be <= 100, got %d", c.ReviewMix)
+	}
+
+	// 节奏大师
+	v.positive("rhythm_initial_time_limit", c.RhythmInitialTimeLimit)
+	v.positive("rhythm_min_time_limit", c.RhythmMinTimeLimit)
+	if c.RhythmMinTimeLimit > c.RhythmInitialTimeLimit {
+		v.addf("rhythm_min_time_limit", "must not exceed rhythm_initial_time_limit (%g > %g)",
+			c.RhythmMinTimeLimit, c.RhythmInitialTimeLimit)
+	}
+	v.nonNegative("rhythm_difficulty_step", c.RhythmDifficultyStep)
+	v.minInt("rhythm_words_per_level", c.RhythmWordsPerLevel, 1)
+
+	// 节奏舞蹈（指针位置范围是 0-1，速度是每帧移动距离，判定窗口单位为毫秒）
+	v.minInt("rhythm_dance_duration", c.RhythmDanceDuration, 1)
+	v.positive("rhythm_dance_initial_speed", c.RhythmDanceInitialSpeed)
+	if c.RhythmDanceInitialSpeed >= 1 {
+		v.addf("rhythm_dance_initial_speed", "must be < 1, got %g", c.RhythmDanceInitialSpeed)
+	}
+	v.nonNegative("rhythm_dance_speed_increment", c.RhythmDanceSpeedIncrement)
+
+	// 节奏舞蹈计分规则（判定窗口为 0 时使用规则中的窗口）
+	for name, p := range c.RhythmDanceScoringPresets {
+		for _, msg := range p.problems() {
+			v.addf("rhythm_dance_scoring_presets", "%s: %s", name, msg)
+		}
+	}
+	v.minInt("rhythm_dance_perfect_window_ms", c.RhythmDancePerfectWindow, 0)
+	v.minInt("rhythm_dance_nice_window_ms", c.RhythmDanceNiceWindow, 0)
+	v.minInt("rhythm_dance_ok_window_ms", c.RhythmDanceOKWindow, 0)
+	if rules, err := c.ScoringRules(); err != nil {
+		v.addf("rhythm_dance_scoring", "must be one of %s, got %q", strings.Join(c.ScoringPresetNames(), ", "), c.RhythmDanceScoring)
+	} else {
+		// 窗口的大小关系按覆盖后的值检查，错误报告在被覆盖的键上
+		windowKey := func(key string, override int) string {
+			if override > 0 {
+				return key
+			}
+			return "rhythm_dance_scoring"
+		}
+		if rules.PerfectWindowMS > rules.NiceWindowMS {
+			v.addf(windowKey("rhythm_dance_perfect_window_ms", c.RhythmDancePerfectWindow),
+				"perfect window must not exceed nice window (%d > %d)", rules.PerfectWindowMS, rules.NiceWindowMS)
+		}
+		if rules.NiceWindowMS > rules.OKWindowMS {
+			v.addf(windowKey("rhythm_dance_nice_window_ms", c.RhythmDanceNiceWindow),
+				"nice window must not exceed ok window (%d > %d)", rules.NiceWindowMS, rules.OKWindowMS)
+		}
+	}
+
+	// 声音提示（后端名称与 pkg/audio 一致）
+	switch c.AudioBackend {
+	case "none", "bell":
+	case "command":
+		if strings.TrimSpace(c.AudioCommand) == "" {
+			v.addf("audio_command", "required when audio_backend is \"command\"")
+		}
+		if c.AudioSoundsDir == "" {
+			v.addf("audio_sounds_dir", "required when audio_backend is \"command\"")
+		}
+	default:
+		v.addf("audio_backend", "must be one of none, bell, command, got %q", c.AudioBackend)
+	}
+
+	if len(v.errs) > 0 {
+		return &ValidationError{Errors: v.errs}
+	}
+	return nil
+}
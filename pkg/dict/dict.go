@@ -0,0 +1,239 @@
+// Package dict 词库文件的读取
+//
+// 支持两种格式：
+//   - 纯文本词库：每行一个单词（旧格式，内置词库使用这种格式）；按常用程度排列的词表
+//     （如 google-10000-english）可以直接使用，单词的词频排名就是它在文件中的序号
+//   - 结构化词库：第一行为 "#word-killer-dict 1" 的 TSV 文件，带名称、语言、许可等元数据，
+//     每个单词可以有词频排名、标签（主题、词性）、释义和翻译
+//
+// 结构化词库示例（列之间用制表符分隔，列的顺序由表头决定，只有 word 列是必需的）：
+//
+//	#word-killer-dict 1
+//	#name: Kitchen words
+//	#language: en
+//	#license: CC0
+//	word	rank	tags	definition	translation
+//	apple	412	food,noun	a round fruit	苹果
+//	boil	2301	food,verb	to heat water until it bubbles	煮
+package dict
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Magic 结构化词库第一行的格式标记，后面跟版本号
+const Magic = "#word-killer-dict"
+
+// 结构化词库格式版本
+const CurrentVersion = 1
+
+// Header 结构化词库的元数据（纯文本词库为空）
+type Header struct {
+	Version  int
+	Name     string
+	Language string
+	License  string
+}
+
+// Entry 词库中的一个单词
+type Entry struct {
+	Word        string
+	Rank        int      // 词频排名（1 为最常用，0 表示未知）；纯文本词库为单词在文件中的序号
+	Tags        []string // 主题、词性等标签（小写）
+	Definition  string
+	Translation string
+}
+
+// HasTag 单词是否带有指定标签（不区分大小写）
+func (e Entry) HasTag(tag string) bool {
+	tag = strings.ToLower(tag)
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Dictionary 读取到的词库
+type Dictionary struct {
+	Header     Header
+	Structured bool // 是否为结构化词库
+	Entries    []Entry
+}
+
+// columns 结构化词库支持的列
+var columns = []string{"word", "rank", "tags", "definition", "translation"}
+
+// Read 读取词库，根据第一行自动识别格式
+func Read(r io.Reader) (*Dictionary, error) {
+	scanner := bufio.NewScanner(r)
+	d := &Dictionary{}
+
+	lineNo := 0
+	first := true
+	var header map[string]int // 列名 -> 列下标，读到表头之前为 nil
+	for scanner.Scan() {
+		lineNo++
+		raw := strings.TrimRight(scanner.Text(), "\r")
+		if first {
+			raw = strings.TrimPrefix(raw, "\ufeff") // UTF-8 BOM
+		}
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		if first {
+			first = false
+			if rest, ok := strings.CutPrefix(line, Magic); ok {
+				version, err := strconv.Atoi(strings.TrimSpace(rest))
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid dictionary version %q", lineNo, strings.TrimSpace(rest))
+				}
+				if version > CurrentVersion {
+					return nil, fmt.Errorf("dictionary version %d is newer than supported version %d", version, CurrentVersion)
+				}
+				d.Structured = true
+				d.Header.Version = version
+				continue
+			}
+		}
+
+		if !d.Structured {
+			// 纯文本词库：整行就是一个单词，按文件中的顺序排名
+			d.Entries = append(d.Entries, Entry{Word: line, Rank: len(d.Entries) + 1})
+			continue
+		}
+
+		if comment, ok := strings.CutPrefix(line, "#"); ok {
+			if header == nil {
+				d.Header.set(comment)
+			}
+			continue
+		}
+
+		fields := strings.Split(raw, "\t")
+		if header == nil {
+			var err error
+			if header, err = parseColumns(fields); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			continue
+		}
+
+		e, err := parseEntry(fields, header)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if e.Word != "" {
+			d.Entries = append(d.Entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dictionary: %w", err)
+	}
+
+	if d.Structured && header == nil {
+		return nil, fmt.Errorf("missing column header (want %s)", strings.Join(columns, ", "))
+	}
+	return d, nil
+}
+
+// set 解析一行 "key: value" 元数据（未知的键和普通注释被忽略）
+func (h *Header) set(line string) {
+	key, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return
+	}
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "name":
+		h.Name = value
+	case "language":
+		h.Language = value
+	case "license":
+		h.License = value
+	}
+}
+
+// parseColumns 解析表头行，返回已知列的下标（未知的列被忽略，便于以后增加列）
+func parseColumns(fields []string) (map[string]int, error) {
+	header := make(map[string]int)
+	for i, f := range fields {
+		name := strings.ToLower(strings.TrimSpace(f))
+		for _, c := range columns {
+			if name == c {
+				if _, dup := header[name]; dup {
+					return nil, fmt.Errorf("duplicate column %q", name)
+				}
+				header[name] = i
+			}
+		}
+	}
+	if _, ok := header["word"]; !ok {
+		return nil, fmt.Errorf("column header has no \"word\" column")
+	}
+	return header, nil
+}
+
+// parseEntry 按表头解析一行单词（行尾缺少的列为空）
+func parseEntry(fields []string, header map[string]int) (Entry, error) {
+	get := func(column string) string {
+		i, ok := header[column]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[i])
+	}
+
+	e := Entry{
+		Word:        get("word"),
+		Definition:  get("definition"),
+		Translation: get("translation"),
+	}
+	if rank := get("rank"); rank != "" {
+		n, err := strconv.Atoi(rank)
+		if err != nil || n < 0 {
+			return Entry{}, fmt.Errorf("invalid rank %q for %q", rank, e.Word)
+		}
+		e.Rank = n
+	}
+	for _, tag := range strings.Split(get("tags"), ",") {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			e.Tags = append(e.Tags, tag)
+		}
+	}
+	return e, nil
+}
+
+// Filter 从一个词库中挑选单词的条件
+type Filter struct {
+	Tags    []string // 带有其中任一标签的单词（空表示不限）
+	MaxRank int      // 词频排名在前 MaxRank 的单词（0 表示不限；没有排名的单词不会被选中）
+}
+
+// IsZero 是否没有任何条件
+func (f Filter) IsZero() bool {
+	return len(f.Tags) == 0 && f.MaxRank == 0
+}
+
+// Match 单词是否符合条件
+func (f Filter) Match(e Entry) bool {
+	if f.MaxRank > 0 && (e.Rank == 0 || e.Rank > f.MaxRank) {
+		return false
+	}
+	if len(f.Tags) == 0 {
+		return true
+	}
+	for _, tag := range f.Tags {
+		if e.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package dict
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadStructured(t *testing.T) {
+	src := "\ufeff#word-killer-dict 1\n" +
+		"#name: Kitchen words\n" +
+		"#language: en\n" +
+		"#license: CC0\n" +
+		"# 普通注释被忽略\n" +
+		"word\ttags\trank\ttranslation\textra\n" +
+		"apple\tFood, Noun\t412\t苹果\tx\n" +
+		"\n" +
+		"boil\tfood,verb\n" +
+		"# 表头之后的注释也被忽略\n" +
+		"salt\t\t90\n"
+	d, err := Read(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Structured {
+		t.Error("dictionary should be structured")
+	}
+	wantHeader := Header{Version: 1, Name: "Kitchen words", Language: "en", License: "CC0"}
+	if d.Header != wantHeader {
+		t.Errorf("header = %+v, want %+v", d.Header, wantHeader)
+	}
+	want := []Entry{
+		{Word: "apple", Rank: 412, Tags: []string{"food", "noun"}, Translation: "苹果"},
+		{Word: "boil", Tags: []string{"food", "verb"}},
+		{Word: "salt", Rank: 90},
+	}
+	if !reflect.DeepEqual(d.Entries, want) {
+		t.Errorf("entries = %+v, want %+v", d.Entries, want)
+	}
+}
+
+func TestReadPlain(t *testing.T) {
+	d, err := Read(strings.NewReader("apple\r\n\n  # not a comment\nbanana\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Structured {
+		t.Error("plain dictionary should not be structured")
+	}
+	// 纯文本词库按行的顺序排名（空行不计）
+	want := []Entry{
+		{Word: "apple", Rank: 1},
+		{Word: "# not a comment", Rank: 2},
+		{Word: "banana", Rank: 3},
+	}
+	if !reflect.DeepEqual(d.Entries, want) {
+		t.Errorf("entries = %+v, want %+v", d.Entries, want)
+	}
+	if got := (Filter{MaxRank: 2}).Match(d.Entries[2]); got {
+		t.Error("dictionary_max_rank should keep only the first lines of a plain dictionary")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, tt := range []struct {
+		src, want string
+	}{
+		{"#word-killer-dict one\nword\n", "invalid dictionary version"},
+		{"#word-killer-dict 9\nword\n", "newer than supported"},
+		{"#word-killer-dict 1\n#name: x\n", "missing column header"},
+		{"#word-killer-dict 1\nrank\ttags\n", `no "word" column`},
+		{"#word-killer-dict 1\nword\tword\n", "duplicate column"},
+		{"#word-killer-dict 1\nword\trank\napple\tfirst\n", "line 3: invalid rank"},
+		{"#word-killer-dict 1\nword\trank\napple\t-1\n", "invalid rank"},
+	} {
+		_, err := Read(strings.NewReader(tt.src))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Read(%q) error = %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	apple := Entry{Word: "apple", Rank: 412, Tags: []string{"food", "noun"}}
+	boil := Entry{Word: "boil", Tags: []string{"food", "verb"}}
+	run := Entry{Word: "run", Rank: 50, Tags: []string{"verb"}}
+
+	for _, tt := range []struct {
+		filter Filter
+		want   []bool // apple, boil, run
+	}{
+		{Filter{}, []bool{true, true, true}},
+		{Filter{Tags: []string{"Food"}}, []bool{true, true, false}},
+		{Filter{Tags: []string{"noun", "verb"}}, []bool{true, true, true}},
+		{Filter{MaxRank: 100}, []bool{false, false, true}},
+		{Filter{Tags: []string{"food"}, MaxRank: 1000}, []bool{true, false, false}},
+	} {
+		var got []bool
+		for _, e := range []Entry{apple, boil, run} {
+			got = append(got, tt.filter.Match(e))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v matches %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+	if !(Filter{}).IsZero() || (Filter{MaxRank: 1}).IsZero() {
+		t.Error("IsZero is wrong")
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"top-1000.txt": "the\nof\nand\n",
+		"medical.tsv":  "#word-killer-dict 1\n#name: Medical\n#language: en\nword\nartery\n",
+		"empty.txt":    "\n",
+		"notes.md":     "not a pack\n",
+		"broken.tsv":   "#word-killer-dict 1\nword\trank\nx\ty\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	packs, skipped, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Pack{
+		{Name: "Medical", Path: filepath.Join(dir, "medical.tsv"), Language: "en", Words: 1},
+		{Name: "top-1000", Path: filepath.Join(dir, "top-1000.txt"), Words: 3},
+	}
+	if !reflect.DeepEqual(packs, want) {
+		t.Errorf("packs = %+v, want %+v", packs, want)
+	}
+	if len(skipped) != 1 || !strings.Contains(skipped[0].Error(), "broken.tsv") {
+		t.Errorf("skipped = %v", skipped)
+	}
+
+	if packs, _, err := Discover(filepath.Join(dir, "missing")); err != nil || len(packs) != 0 {
+		t.Errorf("missing directory: packs = %v, err = %v", packs, err)
+	}
+}
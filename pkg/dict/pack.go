@@ -0,0 +1,95 @@
+package dict
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Pack 词库包：词库包目录中的一个词库文件（纯文本或结构化格式）
+type Pack struct {
+	Name     string // 结构化词库的 #name，没有时为文件名（不含扩展名）
+	Path     string
+	Language string
+	Words    int
+}
+
+// packExtensions 词库包的文件扩展名
+var packExtensions = []string{".txt", ".tsv"}
+
+// LoadPack 读取一个词库包的名称和单词数
+func LoadPack(path string) (Pack, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Pack{}, fmt.Errorf("failed to open pack: %w", err)
+	}
+	defer file.Close()
+
+	d, err := Read(file)
+	if err != nil {
+		return Pack{}, fmt.Errorf("%s: %w", path, err)
+	}
+	p := Pack{
+		Name:     d.Header.Name,
+		Path:     path,
+		Language: d.Header.Language,
+		Words:    len(d.Entries),
+	}
+	if p.Name == "" {
+		p.Name = PackName(path)
+	}
+	return p, nil
+}
+
+// PackName 按文件名得到的词库包名称（不读取文件）
+func PackName(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// Discover 列出目录中的词库包（.txt、.tsv 文件，不含子目录），按名称排序
+// 目录不存在时返回空列表；无法读取或格式错误的文件放在 skipped 中返回，不影响其他词库包
+func Discover(dir string) (packs []Pack, skipped []error, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to read packs directory: %w", err)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !isPackFile(e.Name()) {
+			continue
+		}
+		p, err := LoadPack(filepath.Join(dir, e.Name()))
+		if err != nil {
+			skipped = append(skipped, err)
+			continue
+		}
+		if p.Words == 0 {
+			continue
+		}
+		packs = append(packs, p)
+	}
+
+	sort.SliceStable(packs, func(i, j int) bool {
+		return strings.ToLower(packs[i].Name) < strings.ToLower(packs[j].Name)
+	})
+	return packs, skipped, nil
+}
+
+// isPackFile 文件名是否为词库包
+func isPackFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range packExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Alphabet 词库使用的字母表：加载词库时跳过含有字母表以外字符的单词
+type Alphabet struct {
+	Name  string
+	allow func(r rune) bool
+}
+
+// alphabets 支持的字母表（名称用于配置文件，不要修改已有名称）
+var alphabets = []Alphabet{
+	{"latin", func(r rune) bool { return r >= 'a' && r <= 'z' }},
+	{"latin-extended", func(r rune) bool { return unicode.Is(unicode.Latin, r) }}, // 含重音字母，如 é、ß、ø
+	{"cyrillic", func(r rune) bool { return unicode.Is(unicode.Cyrillic, r) }},
+	{"greek", func(r rune) bool { return unicode.Is(unicode.Greek, r) }},
+	{"any", unicode.IsLetter}, // 任何文字的字母
+}
+
+// DefaultAlphabet 默认字母表（仅 a-z）
+const DefaultAlphabet = "latin"
+
+// Alphabets 按顺序返回所有字母表名称
+func Alphabets() []string {
+	names := make([]string, len(alphabets))
+	for i, a := range alphabets {
+		names[i] = a.Name
+	}
+	return names
+}
+
+// ParseAlphabet 根据名称获取字母表（空名称为默认字母表）
+func ParseAlphabet(name string) (Alphabet, error) {
+	if name == "" {
+		name = DefaultAlphabet
+	}
+	for _, a := range alphabets {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+	return Alphabet{}, fmt.Errorf("unknown alphabet %q (want one of %s)", name, strings.Join(Alphabets(), ", "))
+}
+
+// String 字母表名称（零值为默认字母表）
+func (a Alphabet) String() string {
+	if a.Name == "" {
+		return DefaultAlphabet
+	}
+	return a.Name
+}
+
+// Valid 单词是否只包含字母表中的字母（单词已转为小写；零值按默认字母表检查）
+func (a Alphabet) Valid(word string) bool {
+	allow := a.allow
+	if allow == nil {
+		allow = alphabets[0].allow
+	}
+	for _, r := range word {
+		if !allow(r) {
+			return false
+		}
+	}
+	return word != ""
+}
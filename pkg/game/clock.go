@@ -0,0 +1,110 @@
+package game
+
+import "time"
+
+// PlayClock 暂停感知的游戏时钟：只累计游戏进行中的时间
+// 所有模式的计时（倒计时、单词限时、节奏舞蹈时长等）都读取它
+type PlayClock struct {
+	startedAt time.Time
+	pausedAt  time.Time
+	stoppedAt time.Time
+	paused    time.Duration // 累计暂停时长
+	isPaused  bool
+}
+
+// Start 从零开始计时
+func (c *PlayClock) Start(now time.Time) {
+	*c = PlayClock{startedAt: now}
+}
+
+// Pause 暂停计时
+func (c *PlayClock) Pause(now time.Time) {
+	if c.isPaused || !c.stoppedAt.IsZero() {
+		return
+	}
+	c.pausedAt = now
+	c.isPaused = true
+}
+
+// Resume 恢复计时
+func (c *PlayClock) Resume(now time.Time) {
+	if !c.isPaused {
+		return
+	}
+	c.paused += now.Sub(c.pausedAt)
+	c.isPaused = false
+}
+
+// Stop 停止计时，之后 Elapsed 固定不变
+func (c *PlayClock) Stop(now time.Time) {
+	if !c.stoppedAt.IsZero() {
+		return
+	}
+	c.Resume(now)
+	c.stoppedAt = now
+}
+
+// Elapsed 获取有效游戏时长（不含暂停时间）
+func (c *PlayClock) Elapsed(now time.Time) time.Duration {
+	if c.startedAt.IsZero() {
+		return 0
+	}
+
+	end := now
+	switch {
+	case !c.stoppedAt.IsZero():
+		end = c.stoppedAt
+	case c.isPaused:
+		end = c.pausedAt
+	}
+
+	elapsed := end.Sub(c.startedAt) - c.paused
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed
+}
+
+// Clock 时间来源；游戏中所有时间读取都经过它，测试中可替换为虚拟时钟
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock 使用系统时间的时钟
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock 返回使用系统时间的时钟
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+// ManualClock 手动推进的虚拟时钟（用于测试和确定性模拟）
+type ManualClock struct {
+	now time.Time
+}
+
+// NewManualClock 创建从指定时间开始的虚拟时钟
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now 返回当前虚拟时间
+func (c *ManualClock) Now() time.Time {
+	return c.now
+}
+
+// Advance 将虚拟时间向前推进
+func (c *ManualClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// Set 将虚拟时间设置为指定时刻（不允许倒退）
+func (c *ManualClock) Set(t time.Time) {
+	if t.After(c.now) {
+		c.now = t
+	}
+}
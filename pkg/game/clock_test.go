@@ -0,0 +1,35 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlayClockExcludesPausedTime(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(sec float64) time.Time {
+		return base.Add(time.Duration(sec * float64(time.Second)))
+	}
+
+	var c PlayClock
+	c.Start(at(0))
+	c.Pause(at(10))
+	if got := c.Elapsed(at(100)); got != 10*time.Second {
+		t.Errorf("Elapsed while paused = %v, want 10s", got)
+	}
+	c.Resume(at(100))
+	if got := c.Elapsed(at(105)); got != 15*time.Second {
+		t.Errorf("Elapsed after resume = %v, want 15s", got)
+	}
+	c.Stop(at(110))
+	if got := c.Elapsed(at(500)); got != 20*time.Second {
+		t.Errorf("Elapsed after stop = %v, want 20s", got)
+	}
+}
+
+func TestPlayClockZeroValue(t *testing.T) {
+	var c PlayClock
+	if got := c.Elapsed(time.Now()); got != 0 {
+		t.Errorf("unstarted clock Elapsed = %v, want 0", got)
+	}
+}
@@ -79,13 +79,13 @@ var missFrames = []string{
 }
 
 // NewDanceAnimationState 创建新的舞蹈动画状态
-func NewDanceAnimationState() *DanceAnimationState {
+func NewDanceAnimationState(now time.Time) *DanceAnimationState {
 	return &DanceAnimationState{
 		CurrentAnimation: AnimIdle,
 		FrameIndex:       0,
 		FrameCount:       len(idleFrames),
-		LastUpdate:       time.Now(),
-		AnimationStart:   time.Now(),
+		LastUpdate:       now,
+		AnimationStart:   now,
 	}
 }
 
@@ -96,7 +96,7 @@ func (g *Game) UpdateDanceAnimation() {
 	}
 
 	state := g.RhythmDanceState.DanceAnimState
-	now := time.Now()
+	now := g.clock.Now()
 
 	// 每200ms切换一帧
 	if now.Sub(state.LastUpdate) < 200*time.Millisecond {
@@ -129,30 +129,30 @@ func (g *Game) UpdateDanceAnimation() {
 }
 
 // TriggerJudgmentAnimation 触发判定动画
-func (g *Game) TriggerJudgmentAnimation(judgment string) {
+func (g *Game) TriggerJudgmentAnimation(judgment Judgment) {
 	if g.RhythmDanceState == nil {
 		return
 	}
 
 	// 如果还没有动画状态，创建一个
 	if g.RhythmDanceState.DanceAnimState == nil {
-		g.RhythmDanceState.DanceAnimState = NewDanceAnimationState()
+		g.RhythmDanceState.DanceAnimState = NewDanceAnimationState(g.clock.Now())
 	}
 
 	state := g.RhythmDanceState.DanceAnimState
 
 	// 根据判定类型设置动画
 	switch judgment {
-	case "Perfect":
+	case JudgmentPerfect:
 		state.CurrentAnimation = AnimPerfect
 		state.FrameCount = len(perfectFrames)
-	case "Nice":
+	case JudgmentNice:
 		state.CurrentAnimation = AnimNice
 		state.FrameCount = len(niceFrames)
-	case "OK":
+	case JudgmentOK:
 		state.CurrentAnimation = AnimOK
 		state.FrameCount = len(okFrames)
-	case "Miss":
+	case JudgmentMiss:
 		state.CurrentAnimation = AnimMiss
 		state.FrameCount = len(missFrames)
 	default:
@@ -160,7 +160,7 @@ func (g *Game) TriggerJudgmentAnimation(judgment string) {
 	}
 
 	state.FrameIndex = 0
-	state.AnimationStart = time.Now()
+	state.AnimationStart = g.clock.Now()
 }
 
 // GetCurrentDanceFrame 获取当前舞蹈帧
@@ -0,0 +1,225 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// KeyboardLayout 键盘布局：计算单词难度时用到每个字母所在的排和按键的手指
+type KeyboardLayout struct {
+	Name string
+	keys map[rune]keyPos
+}
+
+// keyPos 字母键的位置
+type keyPos struct {
+	row    int // 0 上排、1 中排、2 下排
+	finger int // 0-3 为左手小指到食指，4-7 为右手食指到小指
+}
+
+// hand 按键的手：0 左手、1 右手
+func (p keyPos) hand() int {
+	return p.finger / 4
+}
+
+// columnFingers 标准指法中每一列由哪根手指按
+var columnFingers = [10]int{0, 1, 2, 3, 3, 4, 4, 5, 6, 7}
+
+// keyboardLayouts 支持的键盘布局：上、中、下三排的按键（名称用于配置文件，不要修改已有名称）
+var keyboardLayouts = func() []KeyboardLayout {
+	layouts := []struct {
+		name string
+		rows [3]string
+	}{
+		{"qwerty", [3]string{"qwertyuiop", "asdfghjkl;", "zxcvbnm,./"}},
+		{"dvorak", [3]string{"',.pyfgcrl", "aoeuidhtns", ";qjkxbmwvz"}},
+		{"colemak", [3]string{"qwfpgjluy;", "arstdhneio", "zxcvbkm,./"}},
+	}
+
+	result := make([]KeyboardLayout, len(layouts))
+	for i, l := range layouts {
+		keys := make(map[rune]keyPos)
+		for row, chars := range l.rows {
+			for col, r := range []rune(chars) {
+				keys[r] = keyPos{row: row, finger: columnFingers[col]}
+			}
+		}
+		result[i] = KeyboardLayout{Name: l.name, keys: keys}
+	}
+	return result
+}()
+
+// DefaultKeyboardLayout 默认键盘布局
+const DefaultKeyboardLayout = "qwerty"
+
+// KeyboardLayouts 按顺序返回所有键盘布局名称
+func KeyboardLayouts() []string {
+	names := make([]string, len(keyboardLayouts))
+	for i, l := range keyboardLayouts {
+		names[i] = l.Name
+	}
+	return names
+}
+
+// ParseKeyboardLayout 根据名称获取键盘布局（空名称为默认布局）
+func ParseKeyboardLayout(name string) (KeyboardLayout, error) {
+	if name == "" {
+		name = DefaultKeyboardLayout
+	}
+	for _, l := range keyboardLayouts {
+		if l.Name == name {
+			return l, nil
+		}
+	}
+	return KeyboardLayout{}, fmt.Errorf("unknown keyboard layout %q (want one of %s)", name, strings.Join(KeyboardLayouts(), ", "))
+}
+
+// String 键盘布局名称（零值为默认布局）
+func (l KeyboardLayout) String() string {
+	if l.Name == "" {
+		return DefaultKeyboardLayout
+	}
+	return l.Name
+}
+
+// letterFrequency 英文字母的出现频率（%），用于计算字母的稀有程度
+var letterFrequency = map[rune]float64{
+	'e': 12.7, 't': 9.1, 'a': 8.2, 'o': 7.5, 'i': 7.0, 'n': 6.7, 's': 6.3, 'h': 6.1, 'r': 6.0,
+	'd': 4.3, 'l': 4.0, 'c': 2.8, 'u': 2.8, 'm': 2.4, 'w': 2.4, 'f': 2.2, 'g': 2.0, 'y': 2.0,
+	'p': 1.9, 'b': 1.5, 'v': 0.98, 'k': 0.77, 'j': 0.15, 'x': 0.15, 'q': 0.095, 'z': 0.074,
+}
+
+// rarity 字母的稀有程度：按频率的对数从 0（e）到 1（z），频率表以外的字母为 0.5
+func rarity(r rune) float64 {
+	f, ok := letterFrequency[r]
+	if !ok {
+		return 0.5
+	}
+	return math.Log(letterFrequency['e']/f) / math.Log(letterFrequency['e']/letterFrequency['z'])
+}
+
+// WordFeatures 单词的难度特征（二连键的占比只统计两个字母都在键盘布局上的二连键）
+type WordFeatures struct {
+	Length     int     // 字母数
+	Rarity     float64 // 字母的平均稀有程度（0-1）
+	SameFinger float64 // 同一根手指连续按两个不同键的二连键占比
+	SameHand   float64 // 同一只手的二连键占比（左右手交替越少越难）
+	RowJumps   float64 // 上排和下排之间跳跃的二连键占比
+}
+
+// Features 计算单词在该键盘布局上的难度特征（零值按默认布局计算）
+func (l KeyboardLayout) Features(word string) WordFeatures {
+	keys := l.keys
+	if keys == nil {
+		keys = keyboardLayouts[0].keys
+	}
+
+	f := WordFeatures{Length: utf8.RuneCountInString(word)}
+	if f.Length == 0 {
+		return f
+	}
+
+	var bigrams, sameFinger, sameHand, rowJumps int
+	prev, prevOK := keyPos{}, false
+	var last rune
+	for _, r := range word {
+		f.Rarity += rarity(r)
+
+		pos, ok := keys[r]
+		if ok && prevOK {
+			bigrams++
+			if pos.finger == prev.finger && r != last {
+				sameFinger++
+			}
+			if pos.hand() == prev.hand() {
+				sameHand++
+			}
+			if pos.row-prev.row == 2 || prev.row-pos.row == 2 {
+				rowJumps++
+			}
+		}
+		prev, prevOK, last = pos, ok, r
+	}
+
+	f.Rarity /= float64(f.Length)
+	if bigrams > 0 {
+		f.SameFinger = float64(sameFinger) / float64(bigrams)
+		f.SameHand = float64(sameHand) / float64(bigrams)
+		f.RowJumps = float64(rowJumps) / float64(bigrams)
+	}
+	return f
+}
+
+// 各特征在难度中的权重（合计 100）
+const (
+	lengthWeight     = 35.0
+	rarityWeight     = 25.0
+	sameFingerWeight = 15.0
+	sameHandWeight   = 10.0
+	rowJumpWeight    = 15.0
+
+	// 长度特征从 3 个字母（0）到 12 个字母（满分）线性增长
+	easyLength = 3
+	hardLength = 12
+)
+
+// Score 单词难度（0-100）
+func (f WordFeatures) Score() float64 {
+	length := float64(f.Length-easyLength) / (hardLength - easyLength)
+	length = math.Min(math.Max(length, 0), 1)
+	return lengthWeight*length +
+		rarityWeight*f.Rarity +
+		sameFingerWeight*f.SameFinger +
+		sameHandWeight*f.SameHand +
+		rowJumpWeight*f.RowJumps
+}
+
+// difficultySpread 按目标难度选词时的宽容度：难度与目标相差这么多的单词，选中权重约为正中目标的 60%
+const difficultySpread = 8.0
+
+// SetDifficulty 设置目标难度（0-100）和计算难度用的键盘布局
+// 目标难度大于 0 时，三个词库合并为一个，按单词难度与目标的接近程度选词，不再按长度配比
+func (g *Game) SetDifficulty(target float64, layout KeyboardLayout) {
+	if layout.String() != g.keyboardLayout.String() {
+		g.difficultyCache = nil
+	}
+	g.difficultyTarget = target
+	g.keyboardLayout = layout
+}
+
+// WordDifficulty 单词在当前键盘布局上的难度（0-100）
+func (g *Game) WordDifficulty(word string) float64 {
+	if d, ok := g.difficultyCache[word]; ok {
+		return d
+	}
+	if g.difficultyCache == nil {
+		g.difficultyCache = make(map[string]float64)
+	}
+	d := g.keyboardLayout.Features(word).Score()
+	g.difficultyCache[word] = d
+	return d
+}
+
+// selectionWeight 单词的选中权重：练习模式的弱项权重，乘以单词难度与目标难度的接近程度
+func (g *Game) selectionWeight(word string) float64 {
+	weight := 1.0
+	if g.wordWeight != nil {
+		weight = g.wordWeight(word)
+	}
+	if g.difficultyTarget > 0 {
+		d := (g.WordDifficulty(word) - g.difficultyTarget) / difficultySpread
+		weight *= math.Exp(-d * d / 2)
+	}
+	return weight
+}
+
+// mergedPool 三个词库合并后的全部单词
+func (g *Game) mergedPool() []string {
+	pool := make([]string, 0, len(g.shortPool)+len(g.mediumPool)+len(g.longPool))
+	pool = append(pool, g.shortPool...)
+	pool = append(pool, g.mediumPool...)
+	pool = append(pool, g.longPool...)
+	return pool
+}
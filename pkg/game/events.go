@@ -0,0 +1,70 @@
+package game
+
+import "time"
+
+// EventType 游戏事件类型
+type EventType string
+
+const (
+	// 玩家输入（回放时重新送入游戏）
+	EventChar      EventType = "char"      // 输入字符
+	EventBackspace EventType = "backspace" // 退格
+	EventEliminate EventType = "eliminate" // 回车确认（消除单词/提交句子）
+	EventJudge     EventType = "judge"     // 节奏舞蹈判定键
+	EventTick      EventType = "tick"      // 动画更新（小鱼游动、节奏指针移动）
+
+	// 游戏输出（回放时用于核对）
+	EventWordSpawned   EventType = "spawn"    // 生成新单词/句子/小鱼
+	EventWordCompleted EventType = "complete" // 单词被消除（小鱼被抓到）
+	EventJudgment      EventType = "judgment" // 节奏判定结果
+	EventFinished      EventType = "finish"   // 游戏结束
+)
+
+// Event 带时间戳的游戏事件
+type Event struct {
+	At      time.Duration `json:"at"`                // 游戏时钟读数（不含暂停时间）
+	Type    EventType     `json:"type"`              // 事件类型
+	Text    string        `json:"text,omitempty"`    // 字符、单词或判定等级
+	Score   int           `json:"score,omitempty"`   // 判定得分
+	Aborted bool          `json:"aborted,omitempty"` // 游戏结束时是否为中止
+}
+
+// IsInput 是否为玩家输入事件
+func (e Event) IsInput() bool {
+	switch e.Type {
+	case EventChar, EventBackspace, EventEliminate, EventJudge, EventTick:
+		return true
+	}
+	return false
+}
+
+// Events 获取本局的事件日志
+func (g *Game) Events() []Event {
+	return g.events
+}
+
+// emit 记录一个事件，时间戳取当前游戏时钟读数
+func (g *Game) emit(e Event) {
+	e.At = g.PlayTime()
+	g.events = append(g.events, e)
+}
+
+// Tick 推进一帧动画（主循环约每100ms调用一次）
+// 小鱼刷新和节奏指针位置依赖调用时机，因此作为输入事件记录，回放时按原时间重放
+func (g *Game) Tick() {
+	if g.Status != StatusRunning {
+		return
+	}
+
+	switch g.Mode {
+	case ModeUnderwaterCountdown:
+		g.emit(Event{Type: EventTick})
+		g.UpdateFishPositions()
+		g.UpdateBackgroundAnimation()
+		g.UpdateCountdown()
+	case ModeRhythmDance:
+		g.emit(Event{Type: EventTick})
+		g.UpdateRhythmPointer()
+		g.UpdateDanceAnimation()
+	}
+}
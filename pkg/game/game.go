@@ -3,11 +3,16 @@ package game
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/word-killer/word-killer/pkg/beatmap"
+	"github.com/word-killer/word-killer/pkg/dict"
 	"github.com/word-killer/word-killer/pkg/stats"
 )
 
@@ -32,13 +37,59 @@ const (
 	ModeRhythmMaster        // 节奏大师 - 每词限时
 	ModeUnderwaterCountdown // 水下倒计时模式
 	ModeRhythmDance         // 节奏舞蹈模式 - 打字+节奏判定
+	ModePractice            // 自适应练习 - 按按键弱项选词
+	ModeReview              // 间隔复习 - 复习到期的难打单词
+	ModeRomanization        // 罗马字模式 - 看中日文输入拼音/罗马字
 )
 
+// modeNames 模式的稳定名称（用于持久化，不要修改已有名称）
+var modeNames = map[GameMode]string{
+	ModeClassic:             "classic",
+	ModeSentence:            "sentence",
+	ModeCountdown:           "countdown",
+	ModeSpeedRun:            "speedrun",
+	ModeRhythmMaster:        "rhythm-master",
+	ModeUnderwaterCountdown: "underwater",
+	ModeRhythmDance:         "rhythm-dance",
+	ModePractice:            "practice",
+	ModeReview:              "review",
+	ModeRomanization:        "romanization",
+}
+
+// String returns the stable name of the mode
+func (m GameMode) String() string {
+	if name, ok := modeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("mode-%d", int(m))
+}
+
+// ParseMode 根据名称解析游戏模式
+func ParseMode(name string) (GameMode, error) {
+	for mode, modeName := range modeNames {
+		if modeName == name {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown game mode %q", name)
+}
+
+// ModeNames 按模式顺序返回所有模式名称
+func ModeNames() []string {
+	names := make([]string, len(modeNames))
+	for mode, name := range modeNames {
+		names[mode] = name
+	}
+	return names
+}
+
 // Word represents a word in the game
 type Word struct {
-	Text         string
-	Completed    bool
-	CompletedAt  time.Time // when the word was completed (for animation)
+	Text        string
+	Completed   bool
+	CompletedAt time.Time // when the word was completed (for animation)
+	Target      string    // 罗马字模式：显示的中日文（Text 为要输入的读音）
+	Syllables   []string  // 罗马字模式：Text 按音节切分
 }
 
 // Game core game logic
@@ -54,26 +105,56 @@ type Game struct {
 	shortPool        []string
 	mediumPool       []string
 	longPool         []string
+	dictAlphabets    [3]Alphabet // 短、中、长词库的字母表（零值为 a-z）
+	dictFilter       dict.Filter // 从词库中挑选单词的条件
 	usedWords        map[string]bool
+	events           []Event // 本局事件日志（用于回放）
 	rng              *rand.Rand
+	seed             int64                                    // 本局随机数种子（相同种子+相同词库=相同单词序列）
+	nextSeed         int64                                    // 下一局使用的种子
+	seedSource       *rand.Rand                               // 为后续每一局生成种子
+	clock            Clock                                    // 时间来源
+	openFile         func(path string) (io.ReadCloser, error) // 词库文件打开方式（nil 时直接读取磁盘）
 	// Normalized difficulty ratios (0-1 range)
-	shortRatio       float64
-	mediumRatio      float64
-	longRatio        float64
+	shortRatio  float64
+	mediumRatio float64
+	longRatio   float64
+	// 目标难度（0 表示按长度配比选词）
+	difficultyTarget float64
+	keyboardLayout   KeyboardLayout
+	difficultyCache  map[string]float64 // 单词难度（切换键盘布局时清空）
 	// Sentence mode fields
-	TargetSentence   string
-	sentences        []string
+	TargetSentence string
+	sentences      []string
+	// Romanization mode dictionary
+	romanPool []RomanEntry
+
+	// 游戏时钟（暂停感知，所有模式计时都读取它）
+	playClock PlayClock
 
 	// 倒计时模式专属字段
-	CountdownDuration  time.Duration // 总倒计时时长（如60秒）
-	CountdownStartTime time.Time     // 倒计时开始时间
+	CountdownDuration time.Duration // 总倒计时时长（如60秒）
 
 	// 极速模式专属字段
-	SpeedRunTargetWords int       // 固定单词数（如25个）
-	SpeedRunStartTime   time.Time // 用于毫秒级计时
+	SpeedRunTargetWords int // 固定单词数（如25个）
+
+	// 经典、倒计时、极速模式的得分（其他模式为 nil）
+	WordScore *WordScore
+
+	// 自适应练习模式专属字段
+	PracticeProfile stats.KeyStats            // 开局前的历史按键统计（由调用方设置，回放时从回放文件恢复）
+	PracticeTarget  int                       // 本局单词总数
+	PracticeFocus   PracticeFocus             // 当前这组单词针对的弱项
+	wordWeight      func(word string) float64 // 选词权重（nil 时等概率）
+
+	// 间隔复习：待复习单词（由调用方在开局前设置，最早到期的在前）
+	ReviewWords []string      // 复习模式的全部单词；经典和倒计时模式按 ReviewMix 混入
+	ReviewMix   float64       // 经典和倒计时模式中待复习单词的比例（0-1）
+	Attempts    []WordAttempt // 本局每个消除单词的输入过程
+	attempt     wordAttempt   // 正在输入的单词
 
 	// 节奏大师模式专属字段
-	CurrentWordStartTime time.Time     // 当前单词开始时间
+	CurrentWordStart     time.Duration // 当前单词开始时的游戏时钟读数
 	WordTimeLimit        time.Duration // 每个单词的时间限制（初始2秒）
 	ConsecutiveSuccesses int           // 连击计数
 	DifficultyLevel      int           // 当前难度等级（每10词递增）
@@ -90,28 +171,60 @@ type Game struct {
 
 	// Rhythm Dance mode fields
 	RhythmDanceState *RhythmDanceState
+	RhythmScoring    ScoringRules     // 节奏舞蹈计分规则
+	Beatmap          *beatmap.Beatmap // 节奏舞蹈曲谱，nil 表示不跟随曲谱
 }
 
-// New creates a new game instance
+// New creates a new game instance using the system clock and a time-based seed
 func New() *Game {
-	return &Game{
+	return NewWithClock(SystemClock(), time.Now().UnixNano())
+}
+
+// NewWithClock creates a game driven by the given clock and random seed.
+// With a ManualClock and a fixed seed a whole session is deterministic.
+// The first round uses seed itself; every later round gets a seed derived from it.
+func NewWithClock(clock Clock, seed int64) *Game {
+	g := &Game{
 		Status:           StatusIdle,
-		Stats:            stats.New(),
 		PauseMenuIndex:   0,
 		ResultsMenuIndex: 0,
 		usedWords:        make(map[string]bool),
-		rng:              rand.New(rand.NewSource(time.Now().UnixNano())),
+		rng:              rand.New(rand.NewSource(seed)),
+		seed:             seed,
+		nextSeed:         seed,
+		seedSource:       rand.New(rand.NewSource(seed)),
+		clock:            clock,
+		RhythmScoring:    DefaultScoringRules,
 	}
+	// 统计的有效耗时读取游戏时钟，暂停只由游戏时钟计量
+	g.Stats = stats.NewWithClock(clock.Now, g.PlayTime)
+	return g
+}
+
+// UseSeed sets the seed for the next round, e.g. to race a ghost on the same word list
+func (g *Game) UseSeed(seed int64) {
+	g.nextSeed = seed
+}
+
+// Seed returns the random seed of the current round
+func (g *Game) Seed() int64 {
+	return g.seed
+}
+
+// Clock returns the game's time source
+func (g *Game) Clock() Clock {
+	return g.clock
 }
 
 // LoadWordDictionaries loads multiple difficulty-based word dictionaries
 func (g *Game) LoadWordDictionaries(shortPath, mediumPath, longPath string, shortRatio, mediumRatio, longRatio float64) error {
 	var hasError bool
 	errorMsg := ""
+	g.shortPool, g.mediumPool, g.longPool = nil, nil, nil // 重新加载时不保留上次的词库
 
 	// Load short dictionary
 	if shortPath != "" && shortRatio > 0 {
-		if err := g.loadDictToPool(shortPath, &g.shortPool); err != nil {
+		if err := g.loadDictToPool(shortPath, g.dictAlphabets[0], &g.shortPool); err != nil {
 			errorMsg += fmt.Sprintf("short dictionary: %v; ", err)
 			hasError = true
 		}
@@ -119,7 +232,7 @@ func (g *Game) LoadWordDictionaries(shortPath, mediumPath, longPath string, shor
 
 	// Load medium dictionary
 	if mediumPath != "" && mediumRatio > 0 {
-		if err := g.loadDictToPool(mediumPath, &g.mediumPool); err != nil {
+		if err := g.loadDictToPool(mediumPath, g.dictAlphabets[1], &g.mediumPool); err != nil {
 			errorMsg += fmt.Sprintf("medium dictionary: %v; ", err)
 			hasError = true
 		}
@@ -127,7 +240,7 @@ func (g *Game) LoadWordDictionaries(shortPath, mediumPath, longPath string, shor
 
 	// Load long dictionary
 	if longPath != "" && longRatio > 0 {
-		if err := g.loadDictToPool(longPath, &g.longPool); err != nil {
+		if err := g.loadDictToPool(longPath, g.dictAlphabets[2], &g.longPool); err != nil {
 			errorMsg += fmt.Sprintf("long dictionary: %v; ", err)
 			hasError = true
 		}
@@ -149,37 +262,128 @@ func (g *Game) LoadWordDictionaries(shortPath, mediumPath, longPath string, shor
 	return nil
 }
 
-// loadDictToPool loads a dictionary file into a word pool
-func (g *Game) loadDictToPool(path string, pool *[]string) error {
-	file, err := os.Open(path)
+// LoadDictionary 从一个词库加载全部三个难度：单词按长度分为短（5 个字符以内）、中（6-8）、长（9 个以上），
+// 与内置的三个词库相同；每个难度使用各自的字母表，比例为 0 的难度不加载
+func (g *Game) LoadDictionary(path string, shortRatio, mediumRatio, longRatio float64) error {
+	d, err := g.readDictionary(path)
 	if err != nil {
-		return fmt.Errorf("failed to open: %w", err)
+		return fmt.Errorf("failed to load dictionary: %w", err)
 	}
-	defer file.Close()
 
-	*pool = make([]string, 0)
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		word := strings.TrimSpace(scanner.Text())
-		if word != "" && isValidWord(word) {
-			*pool = append(*pool, word)
+	pools := [3]*[]string{&g.shortPool, &g.mediumPool, &g.longPool}
+	ratios := [3]float64{shortRatio, mediumRatio, longRatio}
+	for i, pool := range pools {
+		*pool = nil
+		if ratios[i] > 0 {
+			*pool = g.dictWords(d, g.dictAlphabets[i], func(n int) bool { return lengthLevel(n) == i })
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("failed to read: %w", err)
+	if len(g.shortPool) == 0 && len(g.mediumPool) == 0 && len(g.longPool) == 0 {
+		return fmt.Errorf("dictionary %s has no words for the selected difficulty%s", path, g.filterSuffix())
+	}
+
+	g.shortRatio = shortRatio
+	g.mediumRatio = mediumRatio
+	g.longRatio = longRatio
+
+	return nil
+}
+
+// lengthLevel 单词长度对应的难度：0 短、1 中、2 长
+func lengthLevel(n int) int {
+	switch {
+	case n <= 5:
+		return 0
+	case n <= 8:
+		return 1
 	}
+	return 2
+}
+
+// SetFileOpener sets how dictionary and sentence files are opened
+// (e.g. to fall back to dictionaries embedded in the binary)
+func (g *Game) SetFileOpener(open func(path string) (io.ReadCloser, error)) {
+	g.openFile = open
+}
+
+// SetDictAlphabets 设置短、中、长词库的字母表（需在 LoadWordDictionaries 之前调用）
+func (g *Game) SetDictAlphabets(short, medium, long Alphabet) {
+	g.dictAlphabets = [3]Alphabet{short, medium, long}
+}
 
+// SetDictFilter 设置从词库中挑选单词的条件（标签、词频排名；需在加载词库之前调用）
+func (g *Game) SetDictFilter(filter dict.Filter) {
+	g.dictFilter = filter
+}
+
+// open opens a dictionary file with the configured opener
+func (g *Game) open(path string) (io.ReadCloser, error) {
+	if g.openFile != nil {
+		return g.openFile(path)
+	}
+	return os.Open(path)
+}
+
+// loadDictToPool loads a dictionary file into a word pool
+// 含有字母表以外字符的单词和不符合挑选条件的单词会被跳过
+func (g *Game) loadDictToPool(path string, alphabet Alphabet, pool *[]string) error {
+	d, err := g.readDictionary(path)
+	if err != nil {
+		return err
+	}
+
+	*pool = g.dictWords(d, alphabet, nil)
 	if len(*pool) == 0 {
-		return fmt.Errorf("dictionary is empty")
+		return fmt.Errorf("dictionary has no words in the %s alphabet%s", alphabet, g.filterSuffix())
 	}
 
 	return nil
 }
 
+// readDictionary 读取词库文件（纯文本或结构化格式）
+func (g *Game) readDictionary(path string) (*dict.Dictionary, error) {
+	file, err := g.open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open: %w", err)
+	}
+	defer file.Close()
+
+	d, err := dict.Read(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read: %w", err)
+	}
+	return d, nil
+}
+
+// dictWords 词库中属于字母表、符合挑选条件、长度满足 fits 的单词（fits 为 nil 时不限长度）
+// 输入统一为小写，单词也按小写保存
+func (g *Game) dictWords(d *dict.Dictionary, alphabet Alphabet, fits func(n int) bool) []string {
+	words := make([]string, 0, len(d.Entries))
+	for _, e := range d.Entries {
+		word := strings.ToLower(e.Word)
+		if !alphabet.Valid(word) || !g.dictFilter.Match(e) {
+			continue
+		}
+		if fits != nil && !fits(utf8.RuneCountInString(word)) {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// filterSuffix 词库为空时错误信息的补充说明（设置了挑选条件时提示检查条件）
+func (g *Game) filterSuffix() string {
+	if g.dictFilter.IsZero() {
+		return ""
+	}
+	return " matching dictionary_tags / dictionary_max_rank"
+}
+
 // LoadSentences loads sentences from a text file
 func (g *Game) LoadSentences(path string) error {
-	file, err := os.Open(path)
+	file, err := g.open(path)
 	if err != nil {
 		return fmt.Errorf("failed to open sentences file: %w", err)
 	}
@@ -206,6 +410,23 @@ func (g *Game) LoadSentences(path string) error {
 	return nil
 }
 
+// beginRound 重置统计、游戏时钟、事件日志和随机数，开始新的一局
+// 每局使用独立种子，单局回放只需要这一局的种子
+func (g *Game) beginRound() {
+	g.seed = g.nextSeed
+	g.nextSeed = g.seedSource.Int63()
+	g.rng = rand.New(rand.NewSource(g.seed))
+	g.usedWords = make(map[string]bool)
+
+	g.Stats.Reset()
+	g.Stats.Start()
+	g.playClock.Start(g.clock.Now())
+	g.events = nil
+	g.resetWordScore()
+	g.Attempts = nil
+	g.attempt = wordAttempt{}
+}
+
 // Start starts the game
 func (g *Game) Start(wordCount int) error {
 	// Check if dictionaries are loaded
@@ -218,9 +439,7 @@ func (g *Game) Start(wordCount int) error {
 	g.Mode = ModeClassic
 	g.InputBuffer = ""
 	g.Aborted = false
-	g.Stats.Reset()
-	g.Stats.Start()
-	g.usedWords = make(map[string]bool)
+	g.beginRound()
 
 	// Generate game words from multi-pools
 	g.Words = g.generateWordsFromMultiPools(wordCount)
@@ -240,12 +459,12 @@ func (g *Game) StartSentenceMode() error {
 	g.Mode = ModeSentence
 	g.InputBuffer = ""
 	g.Aborted = false
-	g.Stats.Reset()
-	g.Stats.Start()
+	g.beginRound()
 
 	// Randomly select a sentence
 	idx := g.rng.Intn(len(g.sentences))
 	g.TargetSentence = g.sentences[idx]
+	g.emit(Event{Type: EventWordSpawned, Text: g.TargetSentence})
 
 	return nil
 }
@@ -262,13 +481,10 @@ func (g *Game) StartCountdownMode(duration time.Duration) error {
 	g.Mode = ModeCountdown
 	g.InputBuffer = ""
 	g.Aborted = false
-	g.Stats.Reset()
-	g.Stats.Start()
-	g.usedWords = make(map[string]bool)
+	g.beginRound()
 
 	// 初始化倒计时字段
 	g.CountdownDuration = duration
-	g.CountdownStartTime = time.Now()
 
 	// 生成初始单词（30个，后续动态补充）
 	g.Words = g.generateWordsFromMultiPools(30)
@@ -288,13 +504,10 @@ func (g *Game) StartSpeedRunMode(targetWords int) error {
 	g.Mode = ModeSpeedRun
 	g.InputBuffer = ""
 	g.Aborted = false
-	g.Stats.Reset()
-	g.Stats.Start()
-	g.usedWords = make(map[string]bool)
+	g.beginRound()
 
 	// 初始化极速模式字段
 	g.SpeedRunTargetWords = targetWords
-	g.SpeedRunStartTime = time.Now()
 
 	// 生成固定数量的单词（25个）
 	g.Words = g.generateWordsFromMultiPools(targetWords)
@@ -314,9 +527,7 @@ func (g *Game) StartRhythmMasterMode() error {
 	g.Mode = ModeRhythmMaster
 	g.InputBuffer = ""
 	g.Aborted = false
-	g.Stats.Reset()
-	g.Stats.Start()
-	g.usedWords = make(map[string]bool)
+	g.beginRound()
 
 	// 初始化节奏大师字段
 	g.WordTimeLimit = time.Duration(g.RhythmInitialTimeLimit * float64(time.Second)) // 使用配置的初始时间限制
@@ -327,9 +538,7 @@ func (g *Game) StartRhythmMasterMode() error {
 	g.Words = g.generateWordsFromMultiPools(50)
 
 	// 标记第一个单词的开始时间
-	if len(g.Words) > 0 {
-		g.CurrentWordStartTime = time.Now()
-	}
+	g.CurrentWordStart = 0
 
 	return nil
 }
@@ -340,6 +549,34 @@ func (g *Game) generateWordsFromMultiPools(count int) []Word {
 		count = 20 // default count
 	}
 
+	// 经典和倒计时模式按比例混入待复习单词，其余从词库中选
+	review := g.mixedReviewWords(count)
+	count -= len(review)
+
+	words := make([]Word, 0, count+len(review))
+	if g.difficultyTarget > 0 {
+		// 按目标难度从合并的词库中选词
+		words = append(words, g.selectWordsFromPool(g.mergedPool(), count)...)
+	} else {
+		words = append(words, g.selectWordsByRatio(count)...)
+	}
+	words = append(words, review...)
+
+	// Shuffle the words to mix difficulties
+	for i := len(words) - 1; i > 0; i-- {
+		j := g.rng.Intn(i + 1)
+		words[i], words[j] = words[j], words[i]
+	}
+
+	for _, w := range words {
+		g.emit(Event{Type: EventWordSpawned, Text: w.Text})
+	}
+
+	return words
+}
+
+// selectWordsByRatio 按长度配比从短、中、长三个词库中选词，某个词库不够时从其他词库补足
+func (g *Game) selectWordsByRatio(count int) []Word {
 	// Calculate target counts for each difficulty
 	shortCount := int(float64(count) * g.shortRatio)
 	mediumCount := int(float64(count) * g.mediumRatio)
@@ -369,14 +606,10 @@ func (g *Game) generateWordsFromMultiPools(count int) []Word {
 	// If we don't have enough words, try to fill from other pools
 	if len(words) < count {
 		needed := count - len(words)
-		allPools := make([]string, 0)
-		allPools = append(allPools, g.shortPool...)
-		allPools = append(allPools, g.mediumPool...)
-		allPools = append(allPools, g.longPool...)
 
 		// Filter out already used words
 		available := make([]string, 0)
-		for _, w := range allPools {
+		for _, w := range g.mergedPool() {
 			if !g.usedWords[w] {
 				available = append(available, w)
 			}
@@ -385,12 +618,6 @@ func (g *Game) generateWordsFromMultiPools(count int) []Word {
 		words = append(words, g.selectWordsFromPool(available, needed)...)
 	}
 
-	// Shuffle the words to mix difficulties
-	for i := len(words) - 1; i > 0; i-- {
-		j := g.rng.Intn(i + 1)
-		words[i], words[j] = words[j], words[i]
-	}
-
 	return words
 }
 
@@ -421,7 +648,7 @@ func (g *Game) selectWordsFromPool(pool []string, count int) []Word {
 	words := make([]Word, 0, count)
 	for i := 0; i < count && len(available) > 0; i++ {
 		// Randomly select a word
-		idx := g.rng.Intn(len(available))
+		idx := g.pickWord(available)
 		word := available[idx]
 
 		words = append(words, Word{Text: word, Completed: false})
@@ -434,25 +661,49 @@ func (g *Game) selectWordsFromPool(pool []string, count int) []Word {
 	return words
 }
 
+// pickWord 随机选出一个候选单词的下标：没有选词权重和目标难度时等概率，否则按权重加权
+func (g *Game) pickWord(available []string) int {
+	if g.wordWeight == nil && g.difficultyTarget == 0 {
+		return g.rng.Intn(len(available))
+	}
+
+	weights := make([]float64, len(available))
+	total := 0.0
+	for i, w := range available {
+		weights[i] = g.selectionWeight(w)
+		total += weights[i]
+	}
+	r := g.rng.Float64() * total
+	for i, w := range weights {
+		if r < w {
+			return i
+		}
+		r -= w
+	}
+	return len(available) - 1
+}
+
 // AddChar 添加字符到输入缓冲区
 func (g *Game) AddChar(ch rune) {
 	if g.Status != StatusRunning {
 		return
 	}
+	if g.Mode == ModeRomanization {
+		ch = foldTone(ch) // 声调不参与匹配
+	}
+	g.emit(Event{Type: EventChar, Text: string(ch)})
 
 	// 新增：模式特定的时间检查
 	switch g.Mode {
 	case ModeCountdown:
 		// 检查倒计时是否耗尽
-		elapsed := time.Since(g.CountdownStartTime)
-		if elapsed >= g.CountdownDuration {
+		if g.GetCountdownRemaining() <= 0 {
 			g.finish(false) // 时间到，游戏结束
 			return
 		}
 	case ModeRhythmMaster:
 		// 检查当前单词是否超时
-		wordElapsed := time.Since(g.CurrentWordStartTime)
-		if wordElapsed >= g.WordTimeLimit {
+		if g.GetWordRemaining() <= 0 {
 			g.finish(false) // 超时，节奏失败
 			return
 		}
@@ -461,49 +712,62 @@ func (g *Game) AddChar(ch rune) {
 	// Handle based on game mode
 	if g.Mode == ModeSentence {
 		// Sentence mode: accept all printable characters
-		if ch >= 32 && ch <= 126 { // ASCII printable range
+		if unicode.IsPrint(ch) {
+			g.Stats.AddKey(expectedKey([]string{g.TargetSentence}, g.InputBuffer, ch), ch)
 			g.InputBuffer += string(ch)
 			g.Stats.AddKeystroke()
 
-			// Check if the character matches the target at this position
-			pos := len(g.InputBuffer) - 1
-			if pos < len(g.TargetSentence) {
-				if g.InputBuffer[pos] == g.TargetSentence[pos] {
-					g.Stats.AddCorrectChar()
-				}
+			// Check if the character matches the target at this position（按字符而不是字节比较）
+			target := []rune(g.TargetSentence)
+			pos := utf8.RuneCountInString(g.InputBuffer) - 1
+			correct := pos < len(target) && ch == target[pos]
+			if correct {
+				g.Stats.AddCorrectChar()
 			}
+			g.Stats.AddTypedChar(correct)
 
 			// Check if sentence is completed
-			if len(g.InputBuffer) == len(g.TargetSentence) {
+			if pos+1 == len(target) {
 				// Sentence completed, but don't finish until Enter is pressed
 			}
 		}
 	} else if g.Mode == ModeRhythmDance {
 		// Rhythm Dance mode: 只接受字母，检查是否匹配当前单词
-		if (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') {
+		if unicode.IsLetter(ch) {
+			if g.RhythmDanceState != nil {
+				currentWord := g.RhythmDanceState.WordQueue[g.RhythmDanceState.CurrentWordIndex]
+				g.Stats.AddKey(expectedKey([]string{currentWord}, g.InputBuffer, ch), ch)
+			}
 			g.InputBuffer += string(ch)
 			g.Stats.AddKeystroke()
 
 			// 检查是否匹配当前单词（队列中间位置，索引2）
+			correct := false
 			if g.RhythmDanceState != nil {
 				currentWord := g.RhythmDanceState.WordQueue[g.RhythmDanceState.CurrentWordIndex]
-				if len(g.InputBuffer) <= len(currentWord) &&
-					strings.HasPrefix(currentWord, g.InputBuffer) {
+				if strings.HasPrefix(currentWord, g.InputBuffer) {
 					g.Stats.AddValidKeystroke()
 					g.Stats.AddCorrectChar()
+					correct = true
 				}
 			}
+			g.Stats.AddTypedChar(correct)
 		}
 	} else {
-		// Classic mode: only accept letters
+		// Word modes: the caller only passes letters, lowercased
+		g.Stats.AddKey(expectedKey(g.targetWords(), g.InputBuffer, ch), ch)
 		g.InputBuffer += string(ch)
 		g.Stats.AddKeystroke()
+		g.trackAttempt()
 
 		// 检查是否匹配
 		if g.hasMatch() {
 			g.Stats.AddValidKeystroke()
 			g.Stats.AddCorrectChar()
+		} else {
+			g.markDirty()
 		}
+		g.Stats.AddTypedChar(g.inputOnTarget())
 	}
 }
 
@@ -512,10 +776,16 @@ func (g *Game) Backspace() {
 	if g.Status != StatusRunning {
 		return
 	}
+	g.emit(Event{Type: EventBackspace})
 
 	if len(g.InputBuffer) > 0 {
-		g.InputBuffer = g.InputBuffer[:len(g.InputBuffer)-1]
+		_, size := utf8.DecodeLastRuneInString(g.InputBuffer)
+		g.InputBuffer = g.InputBuffer[:len(g.InputBuffer)-size]
 		g.Stats.AddKeystroke()
+		g.Stats.AddCorrection()
+		g.markDirty()
+		g.attempt.corrections++
+		g.trackAttempt()
 	}
 }
 
@@ -524,11 +794,12 @@ func (g *Game) TryEliminate() {
 	if g.Status != StatusRunning {
 		return
 	}
+	g.emit(Event{Type: EventEliminate, Text: g.InputBuffer})
 
 	if g.Mode == ModeSentence {
 		// Sentence mode: finish if input length matches target
 		// Don't count Enter key as a keystroke in sentence mode
-		if len(g.InputBuffer) == len(g.TargetSentence) {
+		if utf8.RuneCountInString(g.InputBuffer) == utf8.RuneCountInString(g.TargetSentence) {
 			g.finish(false)
 		}
 		// Otherwise ignore Enter key
@@ -546,14 +817,18 @@ func (g *Game) TryEliminate() {
 			if !fish.Completed && fish.Word == g.InputBuffer {
 				// 抓到小鱼！
 				fish.Completed = true
-				fish.CompletedAt = time.Now()
+				fish.CompletedAt = g.clock.Now()
 				fish.Glowing = true
-				g.Stats.AddCompletedWord(len(fish.Word))
+				g.Stats.AddCompletedWord(utf8.RuneCountInString(fish.Word))
 				g.Stats.AddCorrectChar() // Enter键计为正确
+				g.Stats.AddSubmit(true)
+				g.emit(Event{Type: EventWordCompleted, Text: fish.Word})
+				g.completeAttempt(fish.Word)
 				g.InputBuffer = ""
 				return
 			}
 		}
+		g.Stats.AddSubmit(false)
 		return
 	}
 
@@ -570,9 +845,13 @@ func (g *Game) TryEliminate() {
 			// Eliminate word - this Enter key should be counted as correct
 			g.Stats.AddCorrectChar()
 			g.Words[i].Completed = true
-			g.Words[i].CompletedAt = time.Now() // record completion time for animation
-			g.Stats.AddCompletedWord(len(g.Words[i].Text))
+			g.Words[i].CompletedAt = g.clock.Now() // record completion time for animation
+			g.Stats.AddCompletedWord(utf8.RuneCountInString(g.Words[i].Text))
+			g.Stats.AddSubmit(true)
+			g.scoreWord(g.Words[i].Text)
+			g.completeAttempt(g.Words[i].Text)
 			g.InputBuffer = ""
+			g.emit(Event{Type: EventWordCompleted, Text: g.Words[i].Text})
 
 			// 新增：模式特定的完成后处理
 			switch g.Mode {
@@ -613,12 +892,7 @@ func (g *Game) TryEliminate() {
 				}
 
 				// 为下一个单词启动计时器
-				for _, w := range g.Words {
-					if !w.Completed {
-						g.CurrentWordStartTime = time.Now()
-						break
-					}
-				}
+				g.CurrentWordStart = g.PlayTime()
 
 				// 动态补充单词
 				remainingWords := 0
@@ -631,15 +905,22 @@ func (g *Game) TryEliminate() {
 					newWords := g.generateWordsFromMultiPools(20)
 					g.Words = append(g.Words, newWords...)
 				}
+
+			case ModePractice:
+				g.practiceWordCompleted()
 			}
 
 			// Check if all completed
-			if g.Mode == ModeClassic && g.isAllCompleted() {
+			if (g.Mode == ModeClassic || g.Mode == ModeReview || g.Mode == ModeRomanization) && g.isAllCompleted() {
 				g.finish(false)
 			}
 			return
 		}
 	}
+
+	// 没有完全匹配的单词
+	g.Stats.AddSubmit(false)
+	g.penalizeWrongEnter()
 }
 
 // CheckTimeouts 检查模式特定的超时条件
@@ -652,18 +933,19 @@ func (g *Game) CheckTimeouts() {
 	switch g.Mode {
 	case ModeCountdown:
 		// 检查倒计时
-		elapsed := time.Since(g.CountdownStartTime)
-		if elapsed >= g.CountdownDuration {
+		if g.GetCountdownRemaining() <= 0 {
 			g.finish(false) // 时间到
 		}
 
 	case ModeRhythmMaster:
 		// 检查当前活动单词是否超时
-		wordElapsed := time.Since(g.CurrentWordStartTime)
-		if wordElapsed >= g.WordTimeLimit {
+		if g.GetWordRemaining() <= 0 {
 			g.finish(false) // 节奏失败
 		}
 
+	case ModeUnderwaterCountdown:
+		g.UpdateCountdown()
+
 	case ModeRhythmDance:
 		// 检查节奏舞蹈模式倒计时
 		g.CheckRhythmTimeout()
@@ -675,7 +957,7 @@ func (g *Game) Pause() {
 	if g.Status == StatusRunning {
 		g.Status = StatusPaused
 		g.PauseMenuIndex = 0
-		g.Stats.Pause()
+		g.playClock.Pause(g.clock.Now())
 	}
 }
 
@@ -683,7 +965,7 @@ func (g *Game) Pause() {
 func (g *Game) Resume() {
 	if g.Status == StatusPaused {
 		g.Status = StatusRunning
-		g.Stats.Resume()
+		g.playClock.Resume(g.clock.Now())
 	}
 }
 
@@ -710,8 +992,8 @@ func (g *Game) MoveResultsMenu(delta int) {
 	g.ResultsMenuIndex += delta
 	if g.ResultsMenuIndex < 0 {
 		g.ResultsMenuIndex = 0
-	} else if g.ResultsMenuIndex > 2 {
-		g.ResultsMenuIndex = 2
+	} else if g.ResultsMenuIndex > 3 {
+		g.ResultsMenuIndex = 3
 	}
 }
 
@@ -728,6 +1010,31 @@ func (g *Game) finish(aborted bool) {
 	g.Aborted = aborted
 	g.ResultsMenuIndex = 0
 	g.Stats.Finish()
+	g.playClock.Stop(g.clock.Now())
+	g.emit(Event{Type: EventFinished, Aborted: aborted})
+}
+
+// PlayTime 获取本局的有效游戏时长（不含暂停时间）
+func (g *Game) PlayTime() time.Duration {
+	return g.playClock.Elapsed(g.clock.Now())
+}
+
+// GetCountdownRemaining 获取倒计时模式的剩余时间
+func (g *Game) GetCountdownRemaining() time.Duration {
+	remaining := g.CountdownDuration - g.PlayTime()
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// GetWordRemaining 获取节奏大师模式当前单词的剩余时间
+func (g *Game) GetWordRemaining() time.Duration {
+	remaining := g.WordTimeLimit - (g.PlayTime() - g.CurrentWordStart)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
 }
 
 // GetAllWords returns all words (including completed ones)
@@ -781,28 +1088,65 @@ func (g *Game) hasMatch() bool {
 	return false
 }
 
-// isAllCompleted 检查是否全部完成
-func (g *Game) isAllCompleted() bool {
+// inputOnTarget 当前输入是否仍是某个目标的前缀（水下模式的目标是小鱼）
+func (g *Game) inputOnTarget() bool {
+	if g.Mode != ModeUnderwaterCountdown || g.UnderwaterState == nil {
+		return g.hasMatch()
+	}
+	for _, fish := range g.UnderwaterState.Fishes {
+		if !fish.Completed && strings.HasPrefix(fish.Word, g.InputBuffer) {
+			return true
+		}
+	}
+	return false
+}
+
+// targetWords 当前还可以输入的目标单词（水下模式是小鱼身上的单词）
+func (g *Game) targetWords() []string {
+	var words []string
+	if g.Mode == ModeUnderwaterCountdown && g.UnderwaterState != nil {
+		for _, fish := range g.UnderwaterState.Fishes {
+			if !fish.Completed {
+				words = append(words, fish.Word)
+			}
+		}
+		return words
+	}
 	for _, w := range g.Words {
 		if !w.Completed {
-			return false
+			words = append(words, w.Text)
 		}
 	}
-	return true
+	return words
 }
 
-// isValidWord 验证单词格式
-func isValidWord(word string) bool {
-	if len(word) == 0 {
-		return false
+// expectedKey 输入 prefix 之后目标的下一个字符，用于按键统计
+// typed 能接上某个目标时就是 typed；否则按第一个能接上 prefix 的目标算作按错；
+// prefix 已经偏离所有目标时返回 0
+func expectedKey(targets []string, prefix string, typed rune) rune {
+	var expected rune
+	for _, w := range targets {
+		if len(w) <= len(prefix) || !strings.HasPrefix(w, prefix) {
+			continue
+		}
+		next, _ := utf8.DecodeRuneInString(w[len(prefix):])
+		if next == typed {
+			return typed
+		}
+		if expected == 0 {
+			expected = next
+		}
 	}
+	return expected
+}
 
-	for _, ch := range word {
-		if !((ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')) {
+// isAllCompleted 检查是否全部完成
+func (g *Game) isAllCompleted() bool {
+	for _, w := range g.Words {
+		if !w.Completed {
 			return false
 		}
 	}
-
 	return true
 }
 
@@ -812,15 +1156,13 @@ func (g *Game) StartUnderwaterCountdown(durationSeconds int) error {
 	g.Mode = ModeUnderwaterCountdown
 	g.InputBuffer = ""
 	g.Aborted = false
-	g.Stats.Reset()
-	g.Stats.Start()
+	g.beginRound()
 
 	// 初始化海底状态
 	g.UnderwaterState = &UnderwaterState{
-		CountdownStart:   time.Now(),
 		BackgroundFrame:  0,
 		SeaweedPositions: generateSeaweedPositions(),
-		BubbleStreams:    generateBubbleStreams(),
+		BubbleStreams:    g.generateBubbleStreams(),
 	}
 
 	// 生成10条小鱼
@@ -836,7 +1178,7 @@ func (g *Game) UpdateCountdown() {
 		return
 	}
 
-	elapsed := time.Since(g.UnderwaterState.CountdownStart).Seconds()
+	elapsed := g.PlayTime().Seconds()
 	remaining := float64(g.CountdownDurationSecs) - elapsed
 
 	if remaining <= 0 {
@@ -850,7 +1192,7 @@ func (g *Game) GetRemainingTime() int {
 		return 0
 	}
 
-	elapsed := time.Since(g.UnderwaterState.CountdownStart).Seconds()
+	elapsed := g.PlayTime().Seconds()
 	remaining := float64(g.CountdownDurationSecs) - elapsed
 	if remaining < 0 {
 		return 0
@@ -0,0 +1,929 @@
+package game
+
+import (
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/word-killer/word-killer/data"
+	"github.com/word-killer/word-killer/pkg/beatmap"
+	"github.com/word-killer/word-killer/pkg/dict"
+	"github.com/word-killer/word-killer/pkg/stats"
+)
+
+var testPool = []string{
+	"apple", "brave", "cloud", "dance", "eagle", "flame", "grape", "house",
+	"input", "joker", "knife", "lemon", "mango", "night", "ocean", "piano",
+	"queen", "river", "stone", "tiger", "unity", "voice", "whale", "xenon",
+	"yacht", "zebra", "amber", "bloom", "crane", "drift", "ember", "frost",
+}
+
+// newTestGame 创建使用虚拟时钟和固定种子的游戏，词库直接注入
+func newTestGame(seed int64) (*Game, *ManualClock) {
+	clock := NewManualClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	g := NewWithClock(clock, seed)
+	g.shortPool = append([]string(nil), testPool...)
+	g.shortRatio = 1
+	return g, clock
+}
+
+func typeWord(g *Game, word string) {
+	for _, ch := range word {
+		g.AddChar(ch)
+	}
+	g.TryEliminate()
+}
+
+func TestSameSeedGeneratesSameWords(t *testing.T) {
+	a, _ := newTestGame(42)
+	b, _ := newTestGame(42)
+	if err := a.Start(10); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Start(10); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a.GetActiveWords(), b.GetActiveWords()) {
+		t.Errorf("same seed produced different words:\n%v\n%v", a.GetActiveWords(), b.GetActiveWords())
+	}
+}
+
+func TestTryEliminateFinishesClassicGame(t *testing.T) {
+	g, clock := newTestGame(1)
+	if err := g.Start(3); err != nil {
+		t.Fatal(err)
+	}
+
+	typeWord(g, "nomatch")
+	g.InputBuffer = ""
+	if done := len(g.Words) - len(g.GetActiveWords()); done != 0 {
+		t.Fatalf("wrong input eliminated %d words", done)
+	}
+
+	for _, w := range g.GetActiveWords() {
+		clock.Advance(2 * time.Second)
+		typeWord(g, w)
+	}
+	if g.Status != StatusFinished || g.Aborted {
+		t.Fatalf("status = %v, aborted = %v; want finished", g.Status, g.Aborted)
+	}
+	if g.Stats.WordsCompleted != 3 {
+		t.Errorf("WordsCompleted = %d, want 3", g.Stats.WordsCompleted)
+	}
+	if got := g.Stats.GetElapsedSeconds(); got != 6 {
+		t.Errorf("elapsed = %vs, want 6s", got)
+	}
+}
+
+func TestCheckTimeoutsCountdown(t *testing.T) {
+	g, clock := newTestGame(1)
+	if err := g.StartCountdownMode(60 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(30 * time.Second)
+	g.Pause()
+	clock.Advance(time.Hour)
+	g.Resume()
+
+	clock.Advance(29 * time.Second)
+	g.CheckTimeouts()
+	if g.Status != StatusRunning {
+		t.Fatalf("finished after 59s of play")
+	}
+
+	clock.Advance(time.Second)
+	g.CheckTimeouts()
+	if g.Status != StatusFinished {
+		t.Errorf("still running after 60s of play")
+	}
+	if got := g.Stats.GetElapsedSeconds(); got != g.PlayTime().Seconds() || got != 60 {
+		t.Errorf("stats elapsed = %vs, play time = %v; want both 60s", got, g.PlayTime())
+	}
+}
+
+func TestCheckTimeoutsRhythmMaster(t *testing.T) {
+	g, clock := newTestGame(1)
+	g.RhythmInitialTimeLimit = 2
+	g.RhythmMinTimeLimit = 1
+	g.RhythmDifficultyStep = 0.5
+	g.RhythmWordsPerLevel = 1
+	if err := g.StartRhythmMasterMode(); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(1500 * time.Millisecond)
+	typeWord(g, g.GetActiveWords()[0])
+	if g.WordTimeLimit != 1500*time.Millisecond {
+		t.Errorf("WordTimeLimit = %v, want 1.5s after one level", g.WordTimeLimit)
+	}
+
+	clock.Advance(1400 * time.Millisecond)
+	g.CheckTimeouts()
+	if g.Status != StatusRunning {
+		t.Fatal("word timer should restart after each word")
+	}
+	clock.Advance(100 * time.Millisecond)
+	g.CheckTimeouts()
+	if g.Status != StatusFinished {
+		t.Error("expected timeout after the word limit")
+	}
+}
+
+func TestJudgeRhythmTiming(t *testing.T) {
+	tests := []struct {
+		offset time.Duration // 相对指针到达黄金点的时刻
+		want   Judgment
+		score  int
+	}{
+		{0, JudgmentPerfect, 5},
+		{-100 * time.Millisecond, JudgmentNice, 3},
+		{200 * time.Millisecond, JudgmentOK, 1},
+		{-400 * time.Millisecond, JudgmentMiss, -1},
+	}
+
+	// 判定窗口按时间计算，与指针速度无关
+	for _, speed := range []float64{0.02, 0.05, 0.08} {
+		g, clock := newTestGame(1)
+		if err := g.StartRhythmDanceMode(60, speed, 0); err != nil {
+			t.Fatal(err)
+		}
+		state := g.RhythmDanceState
+		arrive := time.Duration(state.GoldenRatio / speed * float64(rhythmPointerFrame))
+
+		for _, tt := range tests {
+			clock.Advance(arrive + tt.offset)
+			g.UpdateRhythmPointer()
+			judgment, score := g.JudgeRhythmTiming()
+			if judgment != tt.want || score != tt.score {
+				t.Errorf("speed %g, offset %v: got %s/%d, want %s/%d", speed, tt.offset, judgment, score, tt.want, tt.score)
+			}
+			if d := state.LastJudgmentOffset - tt.offset; d > time.Millisecond || d < -time.Millisecond {
+				t.Errorf("speed %g: LastJudgmentOffset = %v, want %v", speed, state.LastJudgmentOffset, tt.offset)
+			}
+		}
+		if state.TotalScore != 8 || state.MaxCombo != 2 {
+			t.Errorf("speed %g: TotalScore = %d, MaxCombo = %d; want 8, 2", speed, state.TotalScore, state.MaxCombo)
+		}
+	}
+}
+
+func TestRhythmDanceFollowsBeatmap(t *testing.T) {
+	g, clock := newTestGame(1)
+	bm, err := beatmap.Parse([]byte(`{"title": "t", "bpm": 120, "offset_ms": 500, "beats": 16}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Beatmap = bm
+	if err := g.StartRhythmDanceMode(60, 0.05, 0.01); err != nil {
+		t.Fatal(err)
+	}
+	state := g.RhythmDanceState
+	if state.Duration != 8500*time.Millisecond {
+		t.Errorf("Duration = %v, want the beatmap length 8.5s", state.Duration)
+	}
+
+	// 每拍 500ms：正拍时指针在黄金点
+	clock.Advance(1500 * time.Millisecond)
+	g.UpdateRhythmPointer()
+	if math.Abs(state.PointerPosition-state.GoldenRatio) > 1e-9 {
+		t.Errorf("PointerPosition on the beat = %v, want %v", state.PointerPosition, state.GoldenRatio)
+	}
+
+	// 按拍子时间判定，判定后指针继续跟随曲谱
+	clock.Advance(-30 * time.Millisecond)
+	g.UpdateRhythmPointer()
+	if judgment, _ := g.JudgeRhythmTiming(); judgment != JudgmentPerfect || state.LastJudgmentOffset != -30*time.Millisecond {
+		t.Errorf("30ms early: got %s (%v), want Perfect (-30ms)", judgment, state.LastJudgmentOffset)
+	}
+	if state.PointerPosition == 0 {
+		t.Error("pointer was reset although it follows the beatmap")
+	}
+	clock.Advance(280 * time.Millisecond)
+	g.UpdateRhythmPointer()
+	if judgment, _ := g.JudgeRhythmTiming(); judgment != JudgmentMiss {
+		t.Errorf("between beats: got %s, want Miss", judgment)
+	}
+	if w := state.ZoneWidth(50 * time.Millisecond); math.Abs(w-0.1) > 1e-9 {
+		t.Errorf("ZoneWidth(50ms) at 120 BPM = %v, want 0.1", w)
+	}
+}
+
+func TestRhythmPointerFollowsClock(t *testing.T) {
+	g, clock := newTestGame(1)
+	if err := g.StartRhythmDanceMode(60, 0.05, 0); err != nil {
+		t.Fatal(err)
+	}
+	state := g.RhythmDanceState
+
+	// 每帧 0.05，250ms 走 2.5 帧；与 Tick 调用次数无关
+	clock.Advance(250 * time.Millisecond)
+	g.UpdateRhythmPointer()
+	g.UpdateRhythmPointer()
+	if math.Abs(state.PointerPosition-0.125) > 1e-9 {
+		t.Errorf("PointerPosition = %v, want 0.125", state.PointerPosition)
+	}
+
+	// 一轮 2 秒后回到起点
+	clock.Advance(2 * time.Second)
+	g.UpdateRhythmPointer()
+	if math.Abs(state.PointerPosition-0.125) > 1e-9 {
+		t.Errorf("PointerPosition after wrap = %v, want 0.125", state.PointerPosition)
+	}
+	if w := state.ZoneWidth(100 * time.Millisecond); math.Abs(w-0.05) > 1e-9 {
+		t.Errorf("ZoneWidth(100ms) = %v, want 0.05", w)
+	}
+}
+
+func TestGenerateFishesIsDeterministic(t *testing.T) {
+	a, _ := newTestGame(7)
+	b, _ := newTestGame(7)
+	fa := a.GenerateFishes(10)
+	fb := b.GenerateFishes(10)
+	if len(fa) != 10 {
+		t.Fatalf("generated %d fishes, want 10", len(fa))
+	}
+	if !reflect.DeepEqual(fa, fb) {
+		t.Error("same seed produced different fishes")
+	}
+}
+
+func TestCaughtFishRespawnsAfterGlow(t *testing.T) {
+	g, clock := newTestGame(3)
+	if err := g.StartUnderwaterCountdown(60); err != nil {
+		t.Fatal(err)
+	}
+	word := g.UnderwaterState.Fishes[0].Word
+	typeWord(g, word)
+	if !g.UnderwaterState.Fishes[0].Completed {
+		t.Fatal("fish was not caught")
+	}
+
+	clock.Advance(time.Second)
+	g.UpdateFishPositions()
+	if len(g.UnderwaterState.Fishes) != 10 {
+		t.Errorf("fish count = %d after respawn, want 10", len(g.UnderwaterState.Fishes))
+	}
+	for _, f := range g.UnderwaterState.Fishes {
+		if f.Completed {
+			t.Error("caught fish should be replaced after its glow animation")
+		}
+	}
+}
+
+func TestRhythmScoringRules(t *testing.T) {
+	rules := ScoringRules{
+		Windows:          DefaultRhythmWindows,
+		Points:           map[Judgment]int{JudgmentPerfect: 4, JudgmentNice: 2, JudgmentOK: 1, JudgmentMiss: -2},
+		ComboMultipliers: []ComboMultiplier{{Combo: 2, Multiplier: 1.5}, {Combo: 3, Multiplier: 2}},
+		MissLimit:        2,
+		OKBreaksCombo:    false,
+	}
+	g, _ := newTestGame(1)
+	g.RhythmScoring = rules
+	if err := g.StartRhythmDanceMode(60, 0.05, 0); err != nil {
+		t.Fatal(err)
+	}
+	state := g.RhythmDanceState
+
+	// 连击 1、2、3 的倍率分别为 1、1.5、2；OK 不中断连击，扣分不乘倍率
+	for i, tt := range []struct {
+		judgment Judgment
+		score    int
+		combo    int
+	}{
+		{JudgmentPerfect, 4, 1},
+		{JudgmentNice, 3, 2},
+		{JudgmentOK, 2, 3},
+		{JudgmentMiss, -2, 0},
+	} {
+		if score := g.recordJudgment(tt.judgment, 0); score != tt.score || state.CurrentCombo != tt.combo {
+			t.Errorf("#%d %s: score %d combo %d, want %d and %d", i, tt.judgment, score, state.CurrentCombo, tt.score, tt.combo)
+		}
+	}
+	if state.TotalScore != 7 || state.MaxCombo != 3 {
+		t.Errorf("TotalScore = %d, MaxCombo = %d; want 7, 3", state.TotalScore, state.MaxCombo)
+	}
+
+	// 达到 Miss 上限时结束
+	g.CheckRhythmTimeout()
+	if g.Status != StatusRunning {
+		t.Fatal("finished before reaching the miss limit")
+	}
+	g.recordJudgment(JudgmentMiss, 0)
+	g.CheckRhythmTimeout()
+	if g.Status != StatusFinished {
+		t.Error("expected game over at the miss limit")
+	}
+
+	// 默认规则下 OK 中断连击
+	g, _ = newTestGame(1)
+	if err := g.StartRhythmDanceMode(60, 0.05, 0); err != nil {
+		t.Fatal(err)
+	}
+	g.recordJudgment(JudgmentPerfect, 0)
+	g.recordJudgment(JudgmentOK, 0)
+	if g.RhythmDanceState.CurrentCombo != 0 {
+		t.Errorf("default rules: combo after OK = %d, want 0", g.RhythmDanceState.CurrentCombo)
+	}
+}
+
+func TestParseJudgment(t *testing.T) {
+	for _, j := range []Judgment{JudgmentMiss, JudgmentOK, JudgmentNice, JudgmentPerfect} {
+		if got, err := ParseJudgment(j.String()); err != nil || got != j {
+			t.Errorf("ParseJudgment(%q) = %v, %v", j.String(), got, err)
+		}
+	}
+	if _, err := ParseJudgment("Great"); err == nil {
+		t.Error("expected an error for an unknown judgment")
+	}
+}
+
+func TestWordScore(t *testing.T) {
+	if got := WordPoints("quiz"); got != 22 {
+		t.Errorf(`WordPoints("quiz") = %d, want 22`, got)
+	}
+
+	g, _ := newTestGame(1)
+	if err := g.StartCountdownMode(60 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	s := g.WordScore
+	if s == nil {
+		t.Fatal("countdown mode should keep a score")
+	}
+
+	// 前 5 个干净消除：第 5 个起倍率 ×1.5
+	want := 0
+	for i := 1; i <= 5; i++ {
+		word := g.GetActiveWords()[0]
+		want += int(float64(WordPoints(word)) * comboMultiplier(wordStreakMultipliers, i))
+		typeWord(g, word)
+	}
+	if s.Score != want || s.Streak != 5 || s.Multiplier() != 1.5 {
+		t.Fatalf("after 5 clean words: score %d streak %d ×%g; want %d, 5, ×1.5", s.Score, s.Streak, s.Multiplier(), want)
+	}
+
+	// 退格后的单词照常得分，但中断连击
+	word := g.GetActiveWords()[0]
+	g.AddChar(rune(word[0]))
+	g.Backspace()
+	typeWord(g, word)
+	want += WordPoints(word)
+	if s.Score != want || s.Streak != 0 || s.MaxStreak != 5 {
+		t.Errorf("after a corrected word: score %d streak %d max %d; want %d, 0, 5", s.Score, s.Streak, s.MaxStreak, want)
+	}
+
+	// 错误回车扣分
+	typeWord(g, "nomatch")
+	if s.Score != want-wrongEnterPenalty || s.Penalties != 1 {
+		t.Errorf("after a wrong Enter: score %d penalties %d; want %d, 1", s.Score, s.Penalties, want-wrongEnterPenalty)
+	}
+
+	// 不计分的模式没有得分状态
+	if err := g.StartRhythmMasterMode(); err != nil {
+		t.Fatal(err)
+	}
+	if g.WordScore != nil {
+		t.Error("rhythm master should not keep a word score")
+	}
+}
+
+func TestKeyStatsFollowTargets(t *testing.T) {
+	targets := []string{"stone", "storm", "apple"}
+	tests := []struct {
+		prefix string
+		typed  rune
+		want   rune
+	}{
+		{"", 'a', 'a'},    // 接上 apple
+		{"sto", 'r', 'r'}, // 接上 storm
+		{"sto", 'x', 'n'}, // 按错时算作第一个候选目标的下一个字母
+		{"stx", 'o', 0},   // 已经偏离所有目标
+		{"stone", 's', 0}, // 目标已经输完
+	}
+	for _, tt := range tests {
+		if got := expectedKey(targets, tt.prefix, tt.typed); got != tt.want {
+			t.Errorf("expectedKey(%q, %q) = %q, want %q", tt.prefix, tt.typed, got, tt.want)
+		}
+	}
+
+	g, clock := newTestGame(1)
+	if err := g.Start(1); err != nil {
+		t.Fatal(err)
+	}
+	word := g.GetActiveWords()[0]
+	wrong := 'q'
+	if word[1] == 'q' {
+		wrong = 'z'
+	}
+	g.AddChar(rune(word[0]))
+	clock.Advance(300 * time.Millisecond)
+	g.AddChar(wrong)
+	g.Backspace()
+	typeWord(g, word[1:])
+
+	next := g.Stats.Keys.Keys[string(word[1])]
+	if next.Attempts != 2 || next.Errors != 1 {
+		t.Errorf("key %q = %+v, want 2 attempts with 1 error", word[1], next)
+	}
+	if n := g.Stats.Keys.Substitutions[string(word[1])+string(wrong)]; n != 1 {
+		t.Errorf("substitution %c→%c counted %d times, want 1", word[1], wrong, n)
+	}
+}
+
+func TestPracticeFocusesOnWeakKeys(t *testing.T) {
+	profile := stats.KeyStats{
+		Keys: map[string]stats.KeyStat{
+			"q": {Attempts: 10, Errors: 6, Timed: 10, LatencyMS: 4000},
+			"e": {Attempts: 50, Errors: 1, Timed: 50, LatencyMS: 5000},
+			"a": {Attempts: 50, Timed: 50, LatencyMS: 5000},
+			"n": {Attempts: 50, Timed: 50, LatencyMS: 6000},
+			" ": {Attempts: 50, Errors: 40, Timed: 50, LatencyMS: 50000}, // 句子模式的空格不参与选词
+		},
+		Bigrams: map[string]stats.KeyStat{
+			"qu": {Attempts: 5, Errors: 3},
+			"an": {Attempts: 20, Timed: 20, LatencyMS: 2000},
+		},
+	}
+	focus := NewPracticeFocus(profile)
+	if focus.String() != "q · qu" {
+		t.Fatalf("focus = %q, want \"q · qu\"", focus.String())
+	}
+
+	// 有弱项时包含 q 的单词明显更常出现
+	withQueen := func(profile stats.KeyStats) int {
+		n := 0
+		for seed := int64(1); seed <= 20; seed++ {
+			g, _ := newTestGame(seed)
+			g.PracticeProfile = profile
+			if err := g.StartPracticeMode(20); err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range g.GetActiveWords() {
+				if w == "queen" {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	if weighted, plain := withQueen(profile), withQueen(stats.KeyStats{}); weighted <= plain+5 {
+		t.Errorf("queen picked in %d of 20 weighted batches, %d of 20 plain ones", weighted, plain)
+	}
+}
+
+func TestPracticeRefillsInBatches(t *testing.T) {
+	g, _ := newTestGame(1)
+	if err := g.StartPracticeMode(12); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(g.GetActiveWords()); n != practiceBatch {
+		t.Fatalf("first batch has %d words, want %d", n, practiceBatch)
+	}
+	for _, w := range g.GetActiveWords() {
+		typeWord(g, w)
+	}
+	if n := len(g.GetActiveWords()); n != 2 || g.Status != StatusRunning {
+		t.Fatalf("second batch has %d words (status %v), want the remaining 2", n, g.Status)
+	}
+	for _, w := range g.GetActiveWords() {
+		typeWord(g, w)
+	}
+	if g.Status != StatusFinished || g.Stats.WordsCompleted != 12 {
+		t.Errorf("status = %v after %d words, want finished after 12", g.Status, g.Stats.WordsCompleted)
+	}
+}
+
+func TestWordAttemptsRecordFumbles(t *testing.T) {
+	g, clock := newTestGame(1)
+	if err := g.Start(3); err != nil {
+		t.Fatal(err)
+	}
+	words := g.GetActiveWords()
+
+	// 第一个单词：打错一个键并退格，中途停顿 1.5 秒
+	first := words[0]
+	clock.Advance(3 * time.Second) // 第一个键之前的反应时间不计
+	g.AddChar(rune(first[0]))
+	g.AddChar('#')
+	g.Backspace()
+	clock.Advance(1500 * time.Millisecond)
+	for _, ch := range first[1:] {
+		clock.Advance(100 * time.Millisecond)
+		g.AddChar(ch)
+	}
+	g.TryEliminate()
+
+	// 第二个单词：一次打对
+	for _, ch := range words[1] {
+		clock.Advance(100 * time.Millisecond)
+		g.AddChar(ch)
+	}
+	g.TryEliminate()
+
+	want := []WordAttempt{
+		{Word: first, Corrections: 1, Pause: 1600 * time.Millisecond},
+		{Word: words[1], Corrections: 0, Pause: 100 * time.Millisecond},
+	}
+	if !reflect.DeepEqual(g.Attempts, want) {
+		t.Errorf("Attempts = %+v, want %+v", g.Attempts, want)
+	}
+}
+
+func TestReviewWords(t *testing.T) {
+	due := []string{"rhythm", "queue", "fjord"}
+
+	// 经典模式按比例混入最早到期的单词
+	g, _ := newTestGame(1)
+	g.ReviewWords = due
+	g.ReviewMix = 0.2
+	if err := g.Start(10); err != nil {
+		t.Fatal(err)
+	}
+	words := g.GetActiveWords()
+	mixed := 0
+	for _, w := range words {
+		if w == "rhythm" || w == "queue" {
+			mixed++
+		}
+		if w == "fjord" {
+			t.Error("only 20% of the words should come from the review queue")
+		}
+	}
+	if len(words) != 10 || mixed != 2 {
+		t.Errorf("got %d words with %d review words, want 10 with 2: %v", len(words), mixed, words)
+	}
+
+	// 其他模式不混入
+	if err := g.StartSpeedRunMode(10); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range g.GetActiveWords() {
+		if w == "rhythm" || w == "queue" {
+			t.Errorf("speed run should not mix in review words: %v", g.GetActiveWords())
+		}
+	}
+
+	// 复习模式输入全部到期单词，完成后结束
+	if err := g.StartReviewMode(0); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.GetActiveWords(); len(got) != len(due) {
+		t.Fatalf("review words = %v, want %v", got, due)
+	}
+	for _, w := range g.GetActiveWords() {
+		typeWord(g, w)
+	}
+	if g.Status != StatusFinished || len(g.Attempts) != len(due) {
+		t.Errorf("status = %v with %d attempts, want finished with %d", g.Status, len(g.Attempts), len(due))
+	}
+
+	g.ReviewWords = nil
+	if err := g.StartReviewMode(0); err == nil {
+		t.Error("review mode should not start without due words")
+	}
+}
+
+func TestDictAlphabets(t *testing.T) {
+	dicts := map[string]string{
+		"ru.txt": "Привет\nмир\nhello\nдом-2\n",
+		"el.txt": "Λόγος\nναι\nword\n",
+		"fr.txt": "Éclair\ngarçon\nstraße\nnaïve\n日本\n",
+	}
+	g, _ := newTestGame(1)
+	g.SetFileOpener(func(path string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(dicts[path])), nil
+	})
+	alphabet := func(name string) Alphabet {
+		a, err := ParseAlphabet(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	g.SetDictAlphabets(alphabet("cyrillic"), alphabet("greek"), alphabet("latin-extended"))
+	if err := g.LoadWordDictionaries("ru.txt", "el.txt", "fr.txt", 0.4, 0.3, 0.3); err != nil {
+		t.Fatal(err)
+	}
+
+	// 单词转为小写，字母表以外的单词被跳过
+	for _, tt := range []struct {
+		pool []string
+		want []string
+	}{
+		{g.shortPool, []string{"привет", "мир"}},
+		{g.mediumPool, []string{"λόγος", "ναι"}},
+		{g.longPool, []string{"éclair", "garçon", "straße", "naïve"}},
+	} {
+		if !reflect.DeepEqual(tt.pool, tt.want) {
+			t.Errorf("pool = %q, want %q", tt.pool, tt.want)
+		}
+	}
+
+	if _, err := ParseAlphabet("klingon"); err == nil {
+		t.Error("unknown alphabet should be rejected")
+	}
+	if err := g.loadDictToPool("ru.txt", alphabet("greek"), new([]string)); err == nil {
+		t.Error("dictionary without words in its alphabet should fail to load")
+	}
+}
+
+func TestLoadDictionary(t *testing.T) {
+	src := "#word-killer-dict 1\n" +
+		"word\trank\ttags\n" +
+		"apple\t100\tfood,noun\n" +
+		"pepper\t900\tfood,noun\n" +
+		"breakfast\t300\tfood,noun\n" +
+		"run\t50\tverb\n" +
+		"saucepan\t\tkitchen\n"
+	g, _ := newTestGame(1)
+	g.SetFileOpener(func(path string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(src)), nil
+	})
+
+	// 单词按长度分到短、中、长三个词库
+	if err := g.LoadDictionary("words.tsv", 0.4, 0.3, 0.3); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		pool []string
+		want []string
+	}{
+		{g.shortPool, []string{"apple", "run"}},
+		{g.mediumPool, []string{"pepper", "saucepan"}},
+		{g.longPool, []string{"breakfast"}},
+	} {
+		if !reflect.DeepEqual(tt.pool, tt.want) {
+			t.Errorf("pool = %q, want %q", tt.pool, tt.want)
+		}
+	}
+
+	// 按标签和词频排名挑选；比例为 0 的难度不加载
+	g.SetDictFilter(dict.Filter{Tags: []string{"food"}, MaxRank: 500})
+	if err := g.LoadDictionary("words.tsv", 0.5, 0, 0.5); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"apple"}; !reflect.DeepEqual(g.shortPool, want) {
+		t.Errorf("short pool = %q, want %q", g.shortPool, want)
+	}
+	if len(g.mediumPool) != 0 {
+		t.Errorf("medium pool = %q, want empty", g.mediumPool)
+	}
+	if want := []string{"breakfast"}; !reflect.DeepEqual(g.longPool, want) {
+		t.Errorf("long pool = %q, want %q", g.longPool, want)
+	}
+
+	g.SetDictFilter(dict.Filter{Tags: []string{"sports"}})
+	if err := g.LoadDictionary("words.tsv", 0.4, 0.3, 0.3); err == nil || !strings.Contains(err.Error(), "dictionary_tags") {
+		t.Errorf("empty selection error = %v", err)
+	}
+
+	// 纯文本词表按常用程度排列，词频排名取文件中的顺序
+	g.SetFileOpener(func(path string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("the\nof\nand\nkitchen\nbreakfast\n")), nil
+	})
+	g.SetDictFilter(dict.Filter{MaxRank: 3})
+	if err := g.LoadDictionary("top.txt", 1, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"the", "of", "and"}; !reflect.DeepEqual(g.shortPool, want) {
+		t.Errorf("plain dictionary with max rank 3: short pool = %q, want %q", g.shortPool, want)
+	}
+}
+
+func TestWordFeatures(t *testing.T) {
+	qwerty, err := ParseKeyboardLayout("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dvorak, _ := ParseKeyboardLayout("dvorak")
+
+	// "minimum" 在 QWERTY 上全部用右手，um 是同一根手指、上排跳到下排
+	f := qwerty.Features("minimum")
+	if f.Length != 7 || f.SameHand != 1 || f.SameFinger != 2.0/6 || f.RowJumps != 1 {
+		t.Errorf("minimum features = %+v", f)
+	}
+	// "ate" 在 QWERTY 上全部用左手，在 Dvorak 上左右手交替
+	if f := qwerty.Features("ate"); f.SameHand != 1 {
+		t.Errorf("ate on qwerty = %+v", f)
+	}
+	if f := dvorak.Features("ate"); f.SameHand != 0 || f.SameFinger != 0 {
+		t.Errorf("ate on dvorak = %+v", f)
+	}
+	// 双写字母不算同指
+	if f := qwerty.Features("ll"); f.SameFinger != 0 {
+		t.Errorf("ll features = %+v", f)
+	}
+	// 布局以外的字母只影响稀有程度
+	if f := qwerty.Features("мир"); f.Rarity != 0.5 || f.SameHand != 0 {
+		t.Errorf("мир features = %+v", f)
+	}
+
+	order := []string{"the", "and", "pizza", "minimum"}
+	for i := 1; i < len(order); i++ {
+		if a, b := qwerty.Features(order[i-1]).Score(), qwerty.Features(order[i]).Score(); a >= b {
+			t.Errorf("%s (%.1f) should be easier than %s (%.1f)", order[i-1], a, order[i], b)
+		}
+	}
+
+	if _, err := ParseKeyboardLayout("azerty"); err == nil {
+		t.Error("unknown keyboard layout should be rejected")
+	}
+}
+
+func TestDifficultyTarget(t *testing.T) {
+	easy := []string{"the", "and", "eat", "tie", "sit", "ten", "hen", "tan", "net", "ate"}
+	hard := []string{"minimum", "pizzazz", "puzzle", "jukebox", "quizzed", "mummy", "yummy", "buzz", "junk", "unmix"}
+
+	for _, tt := range []struct {
+		target int
+		want   []string
+	}{
+		{5, easy},
+		{50, hard},
+	} {
+		g, _ := newTestGame(1)
+		g.shortPool = easy
+		g.mediumPool = hard
+		g.longPool = nil
+		g.SetDifficulty(float64(tt.target), KeyboardLayout{})
+
+		if err := g.Start(8); err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range g.Words {
+			found := false
+			for _, want := range tt.want {
+				found = found || w.Text == want
+			}
+			if !found {
+				t.Errorf("target %d picked %q (difficulty %.1f)", tt.target, w.Text, g.WordDifficulty(w.Text))
+			}
+		}
+	}
+}
+
+func TestUnicodeInput(t *testing.T) {
+	g, _ := newTestGame(1)
+	g.shortPool = []string{"привет", "мир", "дом"}
+	if err := g.Start(3); err != nil {
+		t.Fatal(err)
+	}
+
+	// 退格删除一个字符而不是一个字节
+	g.AddChar('м')
+	g.AddChar('ж')
+	g.Backspace()
+	if g.InputBuffer != "м" {
+		t.Fatalf("after backspace input = %q, want %q", g.InputBuffer, "м")
+	}
+	g.Backspace()
+
+	for _, w := range g.GetActiveWords() {
+		typeWord(g, w)
+	}
+	if g.Status != StatusFinished || g.Stats.WordsCompleted != 3 {
+		t.Fatalf("status = %v, completed = %d; want finished with 3", g.Status, g.Stats.WordsCompleted)
+	}
+	if got := g.Stats.Keys.Keys["д"]; got.Attempts != 1 {
+		t.Errorf("key д = %+v, want 1 attempt", got)
+	}
+	if got := expectedKey([]string{"привет"}, "пр", 'x'); got != 'и' {
+		t.Errorf("expectedKey = %q, want 'и'", got)
+	}
+
+	// 句子模式按字符比较位置
+	g.sentences = []string{"Déjà vu."}
+	if err := g.StartSentenceMode(); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range "Déjà vu." {
+		g.AddChar(r)
+	}
+	if g.Stats.CorrectChars != 8 {
+		t.Errorf("correct chars = %d, want 8", g.Stats.CorrectChars)
+	}
+	g.TryEliminate()
+	if g.Status != StatusFinished {
+		t.Errorf("sentence of 8 characters should finish after 8 runes, status %v", g.Status)
+	}
+}
+
+func TestParseReading(t *testing.T) {
+	tests := []struct {
+		reading string
+		want    []string
+	}{
+		{"nǐ hǎo", []string{"ni", "hao"}},
+		{"ni3 hao3", []string{"ni", "hao"}},
+		{"nü3 ér", []string{"nv", "er"}},
+		{"lǚ se", []string{"lv", "se"}}, // 分解形式的 ǚ
+		{"xī'ān", []string{"xi", "an"}},
+		{"Tō-kyō", []string{"to", "kyo"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseReading(tt.reading)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseReading(%q) = %q, %v; want %q", tt.reading, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "3", "нет"} {
+		if _, err := ParseReading(bad); err == nil {
+			t.Errorf("ParseReading(%q) should fail", bad)
+		}
+	}
+}
+
+func TestRomanizationMode(t *testing.T) {
+	g, _ := newTestGame(1)
+	g.SetFileOpener(func(string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("# 注释\n你好\tnǐ hǎo\n绿色\tlǜ sè\n没有读音\n是\tshì\n事\tshi4\n")), nil
+	})
+	if err := g.StartRomanizationMode(3); err == nil {
+		t.Fatal("romanization mode should not start without a dictionary")
+	}
+	if err := g.LoadRomanDictionary("zh.tsv"); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.romanPool) != 4 {
+		t.Fatalf("loaded %d entries, want 4 (lines without a reading are skipped)", len(g.romanPool))
+	}
+
+	if err := g.StartRomanizationMode(10); err != nil {
+		t.Fatal(err)
+	}
+	// 读音相同的词（是/事）一局只出现一次
+	if len(g.Words) != 3 {
+		t.Fatalf("got %d words, want 3 distinct readings", len(g.Words))
+	}
+
+	// 声调不参与匹配：带声调的字母按基本字母输入
+	for _, w := range g.Words {
+		if w.Target == "绿色" {
+			for _, r := range "lǜse" {
+				g.AddChar(r)
+			}
+			g.TryEliminate()
+		}
+	}
+	if g.Stats.WordsCompleted != 1 {
+		t.Fatalf("typing lǜse should eliminate 绿色, completed %d", g.Stats.WordsCompleted)
+	}
+	for _, w := range g.GetActiveWords() {
+		typeWord(g, w)
+	}
+	if g.Status != StatusFinished {
+		t.Errorf("status = %v, want finished once every word is typed", g.Status)
+	}
+}
+
+func TestBuiltinRomanDictionaries(t *testing.T) {
+	for _, path := range []string{"data/pinyin.tsv", "data/romaji.tsv"} {
+		g, _ := newTestGame(1)
+		g.SetFileOpener(data.Open)
+		if err := g.LoadRomanDictionary(path); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		lines := 0
+		f, _ := data.Open(path)
+		b, _ := io.ReadAll(f)
+		f.Close()
+		for _, line := range strings.Split(string(b), "\n") {
+			if line != "" && !strings.HasPrefix(line, "#") {
+				lines++
+			}
+		}
+		if len(g.romanPool) != lines {
+			t.Errorf("%s: %d of %d entries parsed", path, len(g.romanPool), lines)
+		}
+	}
+}
+
+func TestRomajiDictionaryUsesIMESpelling(t *testing.T) {
+	g, _ := newTestGame(1)
+	g.SetFileOpener(data.Open)
+	if err := g.LoadRomanDictionary("data/romaji.tsv"); err != nil {
+		t.Fatal(err)
+	}
+	readings := make(map[string]string)
+	for _, e := range g.romanPool {
+		readings[e.Target] = strings.Join(e.Syllables, " ")
+	}
+	// 长音按输入法的拼写输入，不能因为去掉长音符号而变成短音
+	for target, want := range map[string]string{
+		"東京":    "tou kyou",
+		"大阪":    "oo sa ka",
+		"ありがとう": "a ri ga tou",
+		"ラーメン":  "raa men",
+		"コーヒー":  "koo hii",
+		"音楽":    "on ga ku",
+	} {
+		if got := readings[target]; got != want {
+			t.Errorf("%s reads %q, want %q", target, got, want)
+		}
+	}
+}
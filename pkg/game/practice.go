@@ -0,0 +1,158 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/word-killer/word-killer/pkg/stats"
+)
+
+const (
+	practiceBatch        = 10  // 每组单词数；每组开始前按最新的按键统计重新计算弱项
+	practiceDefaultWords = 20  // 单词总数为 0 时的默认值（与经典模式一致）
+	practiceFocusKeys    = 3   // 每组最多针对的弱字母数
+	practiceFocusBigrams = 3   // 每组最多针对的弱二连键数
+	practiceBoost        = 4.0 // 弱项权重系数：包含一个平均程度弱项的单词被选中的概率约为普通单词的 5 倍
+)
+
+// FocusItem 练习针对的一个弱项
+type FocusItem struct {
+	Keys   string  // 字母或二连键
+	Weight float64 // 单词中每出现一次该弱项增加的选中权重
+}
+
+// PracticeFocus 一组练习单词针对的弱项（没有足够的按键统计时为空，按普通方式随机选词）
+type PracticeFocus struct {
+	Letters []FocusItem
+	Bigrams []FocusItem
+}
+
+// NewPracticeFocus 从按键统计中选出比平均水平弱的最弱字母和二连键
+func NewPracticeFocus(keys stats.KeyStats) PracticeFocus {
+	return PracticeFocus{
+		Letters: focusItems(keys.WeakestKeys(len(keys.Keys)), practiceFocusKeys),
+		Bigrams: focusItems(keys.WeakestBigrams(len(keys.Bigrams)), practiceFocusBigrams),
+	}
+}
+
+// focusItems 从按弱项程度排好序的列表中取前 n 个比平均水平弱的纯字母项
+// 权重按弱项程度相对平均值的倍数放大，越弱的项在单词中越常出现
+func focusItems(spots []stats.WeakSpot, n int) []FocusItem {
+	var letters []stats.WeakSpot
+	var total time.Duration
+	for _, s := range spots {
+		if isLetters(s.Keys) {
+			letters = append(letters, s)
+			total += s.Weakness()
+		}
+	}
+	if len(letters) == 0 {
+		return nil
+	}
+
+	mean := float64(total) / float64(len(letters))
+	var items []FocusItem
+	for _, s := range letters {
+		w := float64(s.Weakness())
+		if len(items) == n || w <= mean {
+			break
+		}
+		items = append(items, FocusItem{Keys: s.Keys, Weight: practiceBoost * w / mean})
+	}
+	return items
+}
+
+// isLetters 是否只包含字母（单词库中只有字母，空格和标点不参与选词）
+func isLetters(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Empty 是否没有任何弱项
+func (f PracticeFocus) Empty() bool {
+	return len(f.Letters) == 0 && len(f.Bigrams) == 0
+}
+
+// String 弱项的简短描述，如 "q r e · th er"
+func (f PracticeFocus) String() string {
+	var parts []string
+	for _, group := range [][]FocusItem{f.Letters, f.Bigrams} {
+		var keys []string
+		for _, item := range group {
+			keys = append(keys, item.Keys)
+		}
+		if len(keys) > 0 {
+			parts = append(parts, strings.Join(keys, " "))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// wordWeight 单词的选中权重：1 加上单词中每次出现的弱字母和弱二连键的权重
+func (f PracticeFocus) wordWeight(word string) float64 {
+	weight := 1.0
+	for _, group := range [][]FocusItem{f.Letters, f.Bigrams} {
+		for _, item := range group {
+			weight += float64(strings.Count(word, item.Keys)) * item.Weight
+		}
+	}
+	return weight
+}
+
+// StartPracticeMode 启动自适应练习模式
+// 开局前由调用方把历史按键统计放进 PracticeProfile；wordCount 为 0 时使用默认单词数
+func (g *Game) StartPracticeMode(wordCount int) error {
+	// 检查词库是否加载
+	if len(g.shortPool) == 0 && len(g.mediumPool) == 0 && len(g.longPool) == 0 {
+		return fmt.Errorf("word dictionaries not loaded")
+	}
+	if wordCount <= 0 {
+		wordCount = practiceDefaultWords
+	}
+
+	// 重置游戏状态
+	g.Status = StatusRunning
+	g.Mode = ModePractice
+	g.InputBuffer = ""
+	g.Aborted = false
+	g.beginRound()
+
+	g.PracticeTarget = wordCount
+	g.nextPracticeBatch()
+
+	return nil
+}
+
+// nextPracticeBatch 按历史和本局至今的按键统计重新计算弱项，生成下一组单词
+// 本局的数据随练习累积：练好的字母逐渐退出重点，新的弱项补上
+func (g *Game) nextPracticeBatch() {
+	keys := g.PracticeProfile.Clone()
+	keys.Merge(g.Stats.Keys)
+	g.PracticeFocus = NewPracticeFocus(keys)
+
+	count := min(practiceBatch, g.PracticeTarget-g.Stats.WordsCompleted)
+	if !g.PracticeFocus.Empty() {
+		g.wordWeight = g.PracticeFocus.wordWeight
+		defer func() { g.wordWeight = nil }()
+	}
+	g.Words = g.generateWordsFromMultiPools(count)
+}
+
+// practiceWordCompleted 一组单词全部完成后生成下一组；达到单词总数（或词库用尽）时结束
+func (g *Game) practiceWordCompleted() {
+	if !g.isAllCompleted() {
+		return
+	}
+	if g.Stats.WordsCompleted < g.PracticeTarget {
+		g.nextPracticeBatch()
+	}
+	if len(g.GetActiveWords()) == 0 {
+		g.finish(false)
+	}
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// WordAttempt 一个已消除单词的输入过程（用于间隔复习：退格多、中途卡壳的单词需要复习）
+type WordAttempt struct {
+	Word        string
+	Corrections int           // 输入过程中的退格次数
+	Pause       time.Duration // 相邻两次按键之间最长的间隔（第一个键之前的反应时间不计）
+}
+
+// wordAttempt 正在输入的单词的过程数据，从上一个单词消除后的第一个键开始
+type wordAttempt struct {
+	started     bool
+	lastKey     time.Duration // 上一次按键时的游戏时钟读数
+	corrections int
+	pause       time.Duration
+}
+
+// trackAttempt 记录一次按键（字母或退格），更新最长停顿
+func (g *Game) trackAttempt() {
+	now := g.PlayTime()
+	if gap := now - g.attempt.lastKey; g.attempt.started && gap > g.attempt.pause {
+		g.attempt.pause = gap
+	}
+	g.attempt.started = true
+	g.attempt.lastKey = now
+}
+
+// completeAttempt 单词被消除，保存它的输入过程并开始记录下一个单词
+func (g *Game) completeAttempt(word string) {
+	g.Attempts = append(g.Attempts, WordAttempt{
+		Word:        word,
+		Corrections: g.attempt.corrections,
+		Pause:       g.attempt.pause,
+	})
+	g.attempt = wordAttempt{}
+}
+
+// mixedReviewWords 经典和倒计时模式中按 ReviewMix 比例取出本局还没出现过的待复习单词
+func (g *Game) mixedReviewWords(count int) []Word {
+	if g.Mode != ModeClassic && g.Mode != ModeCountdown {
+		return nil
+	}
+	return g.takeReviewWords(int(math.Round(float64(count) * g.ReviewMix)))
+}
+
+// takeReviewWords 按到期顺序取出最多 n 个本局还没出现过的待复习单词
+func (g *Game) takeReviewWords(n int) []Word {
+	var words []Word
+	for _, w := range g.ReviewWords {
+		if len(words) >= n {
+			break
+		}
+		if g.usedWords[w] {
+			continue
+		}
+		g.usedWords[w] = true
+		words = append(words, Word{Text: w})
+	}
+	return words
+}
+
+// StartReviewMode 启动间隔复习模式：输入调用方放进 ReviewWords 的到期单词
+// wordCount 限制单词数（0 表示全部到期的单词），优先复习最早到期的
+func (g *Game) StartReviewMode(wordCount int) error {
+	if len(g.ReviewWords) == 0 {
+		return fmt.Errorf("no words due for review")
+	}
+	if wordCount <= 0 {
+		wordCount = len(g.ReviewWords)
+	}
+
+	// 重置游戏状态
+	g.Status = StatusRunning
+	g.Mode = ModeReview
+	g.InputBuffer = ""
+	g.Aborted = false
+	g.beginRound()
+
+	// 打乱顺序，避免按到期顺序出现
+	words := g.takeReviewWords(wordCount)
+	g.rng.Shuffle(len(words), func(i, j int) { words[i], words[j] = words[j], words[i] })
+	for _, w := range words {
+		g.emit(Event{Type: EventWordSpawned, Text: w.Text})
+	}
+	g.Words = words
+
+	return nil
+}
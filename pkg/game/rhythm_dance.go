@@ -4,19 +4,49 @@ import (
 	"fmt"
 	"math"
 	"time"
+	"unicode/utf8"
+
+	"github.com/word-killer/word-killer/pkg/beatmap"
 )
 
+// rhythmPointerFrame 指针速度的时间单位：配置中的速度是每帧（游戏逻辑每100ms更新一次）移动的距离
+const rhythmPointerFrame = 100 * time.Millisecond
+
+// RhythmWindows 节奏判定窗口：指针经过黄金点前后多少时间内按下算作对应等级
+// 按时间而不是按节奏条格数判定，界面宽度和指针速度都不影响判定的松紧
+type RhythmWindows struct {
+	Perfect time.Duration
+	Nice    time.Duration
+	OK      time.Duration
+}
+
+// DefaultRhythmWindows 默认判定窗口（初始速度下与原先按格数判定的松紧相当），与配置中 standard 的窗口一致
+var DefaultRhythmWindows = RhythmWindows{
+	Perfect: 50 * time.Millisecond,
+	Nice:    120 * time.Millisecond,
+	OK:      230 * time.Millisecond,
+}
+
 // RhythmDanceState 节奏舞蹈模式的状态
 type RhythmDanceState struct {
 	// 指针位置和移动
-	PointerPosition  float64 // 指针当前位置 [0.0, 1.0]
-	PointerDirection int     // 摆动方向: 1=右, -1=左
-	PointerSpeed     float64 // 摆动速度（每帧移动距离）
-	speedIncrement   float64 // 每完成一个单词的速度增量（未导出，仅内部使用）
+	PointerPosition  float64       // 指针当前位置 [0.0, 1.0]
+	PointerDirection int           // 摆动方向: 1=右, -1=左
+	PointerSpeed     float64       // 摆动速度（每帧移动距离）
+	speedIncrement   float64       // 每完成一个单词的速度增量（未导出，仅内部使用）
+	sweepStart       time.Duration // 本轮摆动开始时的游戏时钟读数，指针位置由此计算
+	pointerTime      time.Duration // 上次更新指针时的游戏时钟读数
+	beatsBefore      int           // 本轮摆动之前指针经过黄金点的次数
 
 	// 黄金分割点
 	GoldenRatio float64 // 黄金分割点位置（约 0.618）
 
+	// 计分规则：判定窗口、得分、连击倍率和 Miss 上限
+	Rules ScoringRules
+
+	// 曲谱：不为 nil 时指针每拍扫过一次节奏条，正拍时刚好经过黄金点，按拍子时间判定
+	Beatmap *beatmap.Beatmap
+
 	// 统计数据
 	CompletedWords int // 已完成单词数
 	TotalScore     int // 总分
@@ -27,9 +57,8 @@ type RhythmDanceState struct {
 	OKCount      int
 	MissCount    int
 
-	// 倒计时
-	Duration  time.Duration // 总时长
-	StartTime time.Time     // 开始时间
+	// 倒计时（从游戏时钟读取已用时间）
+	Duration time.Duration // 总时长
 
 	// 单词队列管理（多单词显示）
 	WordQueue        []string // 固定长度5: [history-2, history-1, current, next+1, next+2]
@@ -42,12 +71,13 @@ type RhythmDanceState struct {
 	MaxCombo     int
 
 	// 最近判定（用于显示特效）
-	LastJudgment         string    // "Perfect", "Nice", "OK", "Miss"
-	LastJudgmentTime     time.Time // 上次判定时间
-	LastJudgmentPosition float64   // 上次判定的指针位置 [0.0, 1.0]（用于显示箭头）
+	LastJudgment         Judgment      // 最近一次判定，JudgmentNone 表示还没有判定
+	LastJudgmentTime     time.Time     // 上次判定时间
+	LastJudgmentPosition float64       // 上次判定的指针位置 [0.0, 1.0]（用于显示箭头）
+	LastJudgmentOffset   time.Duration // 上次判定与黄金点的时间差：负数为提前，正数为滞后
 
 	// 判定历史记录（按顺序记录每次判定结果）
-	JudgmentHistory []string // 存储每次判定的结果："Perfect", "Nice", "OK", "Miss"
+	JudgmentHistory []Judgment // 存储每次判定的结果
 
 	// 舞蹈动画状态
 	DanceAnimState *DanceAnimationState
@@ -65,8 +95,7 @@ func (g *Game) StartRhythmDanceMode(duration int, initialSpeed float64, speedInc
 	g.Mode = ModeRhythmDance
 	g.InputBuffer = ""
 	g.Aborted = false
-	g.Stats.Reset()
-	g.Stats.Start()
+	g.beginRound()
 
 	// 初始化节奏舞蹈状态
 	g.RhythmDanceState = &RhythmDanceState{
@@ -74,6 +103,8 @@ func (g *Game) StartRhythmDanceMode(duration int, initialSpeed float64, speedInc
 		PointerDirection: 1,                                   // 向右
 		PointerSpeed:     initialSpeed,                        // 使用传入的初始速度
 		GoldenRatio:      0.618,                               // 黄金分割点
+		Rules:            g.RhythmScoring,
+		Beatmap:          g.Beatmap,
 		CompletedWords:   0,
 		TotalScore:       0,
 		PerfectCount:     0,
@@ -81,17 +112,23 @@ func (g *Game) StartRhythmDanceMode(duration int, initialSpeed float64, speedInc
 		OKCount:          0,
 		MissCount:        0,
 		Duration:         time.Duration(duration) * time.Second,
-		StartTime:        time.Now(),
 		CurrentCombo:     0,
 		MaxCombo:         0,
-		JudgmentHistory:  []string{},               // 初始化判定历史
-		DanceAnimState:   NewDanceAnimationState(), // 初始化动画状态
-		WordQueue:        make([]string, 5),        // 初始化单词队列（固定长度5）
-		CurrentWordIndex: 2,                        // 当前单词在中间位置
+		JudgmentHistory:  []Judgment{},                          // 初始化判定历史
+		DanceAnimState:   NewDanceAnimationState(g.clock.Now()), // 初始化动画状态
+		WordQueue:        make([]string, 5),                     // 初始化单词队列（固定长度5）
+		CurrentWordIndex: 2,                                     // 当前单词在中间位置
 	}
 
 	// 保存速度增量配置，用于CompleteRhythmWord
 	g.RhythmDanceState.speedIncrement = speedIncrement
+	g.RhythmDanceState.sweepStart = g.PlayTime()
+
+	// 曲谱规定了总拍数时，最后一拍结束游戏
+	if g.Beatmap != nil && g.Beatmap.Duration() > 0 {
+		g.RhythmDanceState.Duration = g.Beatmap.Duration()
+	}
+	g.UpdateRhythmPointer()
 
 	// 初始化单词队列
 	// 前2个位置（索引0-1）设为空字符串作为历史区占位符
@@ -108,6 +145,7 @@ func (g *Game) StartRhythmDanceMode(duration int, initialSpeed float64, speedInc
 		}
 		usedWords[word] = true
 		g.RhythmDanceState.WordQueue[i] = word
+		g.emit(Event{Type: EventWordSpawned, Text: word})
 	}
 
 	// 保持向后兼容，设置 CurrentWord（已废弃，使用 WordQueue[2] 替代）
@@ -117,92 +155,134 @@ func (g *Game) StartRhythmDanceMode(duration int, initialSpeed float64, speedInc
 }
 
 // UpdateRhythmPointer 更新指针位置
+// 位置由本轮摆动开始后经过的游戏时间计算，与调用频率无关
 func (g *Game) UpdateRhythmPointer() {
 	if g.RhythmDanceState == nil {
 		return
 	}
 
 	state := g.RhythmDanceState
+	state.pointerTime = g.PlayTime()
+
+	// 跟随曲谱：拍数的小数部分就是本拍的进度，正拍时指针在黄金点
+	if state.Beatmap != nil {
+		beat := state.Beatmap.BeatAt(state.pointerTime) + state.GoldenRatio
+		state.PointerPosition = beat - math.Floor(beat)
+		return
+	}
 
-	// 更新位置
-	state.PointerPosition += state.PointerSpeed * float64(state.PointerDirection)
+	// 到达终点后从起点重新开始
+	elapsed := (state.pointerTime - state.sweepStart).Seconds()
+	state.PointerPosition = math.Mod(elapsed*state.barsPerSecond(), 1.0)
+}
 
-	// 检查边界
-	if state.PointerPosition >= 1.0 {
-		state.PointerPosition = 0.0
+// RhythmBeat 到当前时刻为止指针经过黄金点的次数（节拍编号，只增不减）
+// 跟随曲谱时就是已经过的正拍数；第一次经过之前为 0
+func (g *Game) RhythmBeat() int {
+	state := g.RhythmDanceState
+	if state == nil {
+		return 0
 	}
+	now := g.PlayTime()
+	if state.Beatmap != nil {
+		return max(int(math.Floor(state.Beatmap.BeatAt(now)))+1, 0)
+	}
+	return state.beatsBefore + state.passesSince(now)
 }
 
-// JudgeRhythmTiming 判定节奏时机
-// 返回判定等级 ("Perfect", "Nice", "OK", "Miss") 和得分
-func (g *Game) JudgeRhythmTiming() (string, int) {
-	if g.RhythmDanceState == nil {
-		return "Miss", 0
+// passesSince 本轮摆动开始后到 t 为止指针经过黄金点的次数
+func (s *RhythmDanceState) passesSince(t time.Duration) int {
+	travelled := (t - s.sweepStart).Seconds() * s.barsPerSecond()
+	return max(int(math.Floor(travelled-s.GoldenRatio))+1, 0)
+}
+
+// barsPerSecond 指针每秒移动的距离（节奏条总长为 1）
+func (s *RhythmDanceState) barsPerSecond() float64 {
+	if s.Beatmap != nil {
+		return s.Beatmap.BPMAt(s.pointerTime) / 60
 	}
+	return s.PointerSpeed / rhythmPointerFrame.Seconds()
+}
 
-	state := g.RhythmDanceState
+// Song 当前曲谱的显示信息（标题、速度和段落），没有曲谱时为空
+func (s *RhythmDanceState) Song() string {
+	if s.Beatmap == nil {
+		return ""
+	}
+	song := fmt.Sprintf("%s · %g BPM", s.Beatmap.Title, s.Beatmap.BPMAt(s.pointerTime))
+	if section := s.Beatmap.SectionAt(s.pointerTime); section != "" {
+		song += " · " + section
+	}
+	return song
+}
+
+// ZoneWidth 判定窗口在当前速度下对应的节奏条宽度（黄金点单侧，节奏条总长为 1）
+// 界面据此按自己的显示宽度绘制判定区域
+func (s *RhythmDanceState) ZoneWidth(window time.Duration) float64 {
+	return window.Seconds() * s.barsPerSecond()
+}
+
+// timingOffset 指针与黄金点的时间差：负数表示还没到黄金点，正数表示已经越过
+// 取最近的一次经过（刚回到起点时算作上一轮的滞后）；跟随曲谱时就是与最近一拍的时间差
+func (s *RhythmDanceState) timingOffset() time.Duration {
+	if s.Beatmap != nil {
+		_, at := s.Beatmap.NearestBeat(s.pointerTime)
+		return s.pointerTime - at
+	}
+	distance := s.PointerPosition - s.GoldenRatio
+	if distance < -0.5 {
+		distance += 1
+	} else if distance >= 0.5 {
+		distance -= 1
+	}
+	speed := s.barsPerSecond()
+	if speed <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(distance / speed * float64(time.Second))
+}
 
-	// 节奏条宽度（与UI渲染保持一致）
-	const barWidth = 35
-
-	// 计算指针和黄金点的字符位置
-	pointerPos := int(state.PointerPosition * float64(barWidth))
-	goldenPos := int(state.GoldenRatio * float64(barWidth))
-
-	// 计算字符距离（绝对距离）
-	charDistance := int(math.Abs(float64(pointerPos - goldenPos)))
-
-	var judgment string
-	var score int
-
-	// 根据字符距离判定等级
-	// Pattern: --4433223344--
-	//          Miss OK Nice Perfect Nice OK Miss
-	if charDistance == 0 {
-		// Perfect: 黄金点本身（1个字符）
-		judgment = "Perfect"
-		score = 5
-		state.PerfectCount++
-		state.CurrentCombo++
-	} else if charDistance <= 2 {
-		// Nice: 距离1-2个字符（左右各2个）
-		judgment = "Nice"
-		score = 3
-		state.NiceCount++
-		state.CurrentCombo++
-	} else if charDistance <= 4 {
-		// OK: 距离3-4个字符（左右各2个）
-		judgment = "OK"
-		score = 1
-		state.OKCount++
-		state.CurrentCombo = 0 // OK 重置连击
-	} else {
-		// Miss: 距离>4个字符
-		judgment = "Miss"
-		score = -1 // Miss 扣1分
-		state.MissCount++
-		state.CurrentCombo = 0 // Miss 重置连击
+// Judge 按时间差判定等级
+// Pattern: Miss | OK | Nice | Perfect | Nice | OK | Miss
+func (w RhythmWindows) Judge(offset time.Duration) Judgment {
+	if offset < 0 {
+		offset = -offset
+	}
+	switch {
+	case offset <= w.Perfect:
+		return JudgmentPerfect
+	case offset <= w.Nice:
+		return JudgmentNice
+	case offset <= w.OK:
+		return JudgmentOK
+	default:
+		return JudgmentMiss
 	}
+}
 
-	// 更新最大连击
-	if state.CurrentCombo > state.MaxCombo {
-		state.MaxCombo = state.CurrentCombo
+// JudgeRhythmTiming 判定节奏时机
+// 返回判定等级和按计分规则得到的分数
+func (g *Game) JudgeRhythmTiming() (Judgment, int) {
+	if g.RhythmDanceState == nil {
+		return JudgmentMiss, 0
 	}
 
-	// 更新总分
-	state.TotalScore += score
+	state := g.RhythmDanceState
 
-	// 记录最近判定（用于特效显示）
-	state.LastJudgment = judgment
-	state.LastJudgmentTime = time.Now()
-	state.LastJudgmentPosition = state.PointerPosition // 记录判定时的指针位置
+	// 按下的时刻与指针经过黄金点的时间差
+	offset := state.timingOffset()
+	judgment := state.Rules.Windows.Judge(offset)
 
-	// 判定后将指针重置到起点
-	state.PointerPosition = 0.0
-	state.PointerDirection = 1 // 重新从左向右移动
+	state.LastJudgmentPosition = state.PointerPosition // 记录判定时的指针位置
+	score := g.recordJudgment(judgment, offset)
 
-	// 添加到判定历史记录
-	state.JudgmentHistory = append(state.JudgmentHistory, judgment)
+	// 判定后将指针重置到起点（跟随曲谱时指针不受判定影响）
+	if state.Beatmap == nil {
+		state.beatsBefore += state.passesSince(g.PlayTime())
+		state.PointerPosition = 0.0
+		state.PointerDirection = 1 // 重新从左向右移动
+		state.sweepStart = g.PlayTime()
+	}
 
 	return judgment, score
 }
@@ -217,6 +297,7 @@ func (g *Game) CompleteRhythmWord() {
 
 	// 增加完成计数
 	state.CompletedWords++
+	g.emit(Event{Type: EventWordCompleted, Text: state.WordQueue[state.CurrentWordIndex]})
 
 	// 增加速度（使用保存的速度增量）
 	state.PointerSpeed += state.speedIncrement
@@ -230,6 +311,7 @@ func (g *Game) CompleteRhythmWord() {
 	// 生成新单词追加到末尾（索引4）
 	newWord := g.generateUniqueWord(state.WordQueue)
 	state.WordQueue = append(state.WordQueue, newWord)
+	g.emit(Event{Type: EventWordSpawned, Text: newWord})
 
 	// 队列长度保持为5，当前单词始终在索引2
 	// （移除1个+追加1个，自动满足）
@@ -244,15 +326,14 @@ func (g *Game) CheckRhythmTimeout() {
 		return
 	}
 
-	// 检查Miss次数，达到10次提前结束游戏
-	if g.RhythmDanceState.MissCount >= 10 {
+	// 检查Miss次数，达到计分规则的上限时提前结束游戏（0 表示不限）
+	if limit := g.RhythmDanceState.Rules.MissLimit; limit > 0 && g.RhythmDanceState.MissCount >= limit {
 		g.finish(false) // Miss过多，游戏结束
 		return
 	}
 
 	// 检查时间是否到
-	elapsed := time.Since(g.RhythmDanceState.StartTime)
-	if elapsed >= g.RhythmDanceState.Duration {
+	if g.PlayTime() >= g.RhythmDanceState.Duration {
 		g.finish(false) // 时间到，游戏结束
 	}
 }
@@ -263,8 +344,7 @@ func (g *Game) GetRhythmRemainingTime() int {
 		return 0
 	}
 
-	elapsed := time.Since(g.RhythmDanceState.StartTime)
-	remaining := g.RhythmDanceState.Duration - elapsed
+	remaining := g.RhythmDanceState.Duration - g.PlayTime()
 
 	if remaining < 0 {
 		return 0
@@ -315,40 +395,38 @@ func (g *Game) generateUniqueWord(existingWords []string) string {
 
 // TryRhythmJudgment 尝试进行节奏判定（按空格键触发）
 func (g *Game) TryRhythmJudgment() {
-	if g.Mode != ModeRhythmDance || g.RhythmDanceState == nil {
+	if g.Status != StatusRunning || g.Mode != ModeRhythmDance || g.RhythmDanceState == nil {
 		return
 	}
+	g.emit(Event{Type: EventJudge})
 
 	// 获取当前单词（队列中间位置，索引2）
 	currentWord := g.RhythmDanceState.WordQueue[g.RhythmDanceState.CurrentWordIndex]
 
 	// 检查单词是否完全正确
 	if g.InputBuffer != currentWord {
-		// 单词不正确或不完整，判定为 Miss，扣1分
-		g.RhythmDanceState.MissCount++
-		g.RhythmDanceState.CurrentCombo = 0
-		g.RhythmDanceState.TotalScore -= 1 // Miss 扣1分
-		g.RhythmDanceState.LastJudgment = "Miss"
-		g.RhythmDanceState.LastJudgmentTime = time.Now()
-
-		// 添加到判定历史记录
-		g.RhythmDanceState.JudgmentHistory = append(g.RhythmDanceState.JudgmentHistory, "Miss")
+		// 单词不正确或不完整，判定为 Miss（与时机无关）
+		g.Stats.AddSubmit(false)
+		g.Stats.ClearInput()
+		g.recordJudgment(JudgmentMiss, 0)
 
 		// 触发Miss动画
-		g.TriggerJudgmentAnimation("Miss")
+		g.TriggerJudgmentAnimation(JudgmentMiss)
 
 		g.InputBuffer = "" // 清空输入，重新输入
 		return
 	}
 
-	// 单词正确，执行节奏判定
+	// 单词正确，按此刻的指针位置执行节奏判定
+	g.Stats.AddSubmit(true)
+	g.UpdateRhythmPointer()
 	judgment, score := g.JudgeRhythmTiming()
 
 	// 触发对应的舞蹈动画
 	g.TriggerJudgmentAnimation(judgment)
 
 	// 记录统计
-	g.Stats.AddCompletedWord(len(currentWord))
+	g.Stats.AddCompletedWord(utf8.RuneCountInString(currentWord))
 
 	// 完成单词并切换到下一个
 	g.CompleteRhythmWord()
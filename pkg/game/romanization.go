@@ -0,0 +1,179 @@
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// RomanEntry 罗马字词库中的一条：中日文目标和它的读音（去掉声调，按音节切分）
+type RomanEntry struct {
+	Target    string
+	Syllables []string
+}
+
+// toneFolds 带声调或长音符号的字母对应的基本字母（ü 按拼音输入法的习惯输入为 v）
+var toneFolds = func() map[rune]rune {
+	folds := make(map[rune]rune)
+	for base, marked := range map[rune]string{
+		'a': "āáǎàâä",
+		'e': "ēéěèêë",
+		'i': "īíǐìîï",
+		'o': "ōóǒòôö",
+		'u': "ūúǔùû",
+		'v': "üǖǘǚǜ",
+		'n': "ńňǹ",
+		'm': "ḿ",
+	} {
+		for _, r := range marked {
+			folds[r] = base
+		}
+	}
+	return folds
+}()
+
+// foldTone 去掉字母的声调和长音符号并转为小写
+func foldTone(r rune) rune {
+	r = unicode.ToLower(r)
+	if base, ok := toneFolds[r]; ok {
+		return base
+	}
+	return r
+}
+
+// ParseReading 把拼音或罗马字读音切分为音节，去掉声调
+// 音节之间用空格、撇号或连字符分隔；声调可以是符号（nǐ hǎo）或数字（ni3 hao3），
+// 也可以是分解形式的组合符号。去掉声调后只能剩下 a-z
+func ParseReading(reading string) ([]string, error) {
+	fields := strings.FieldsFunc(reading, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '\'' || r == '’' || r == '-'
+	})
+
+	var syllables []string
+	for _, field := range fields {
+		var b []byte
+		for _, r := range field {
+			switch {
+			case r >= '0' && r <= '5':
+				// 数字标调
+				continue
+			case r == '\u0308' && len(b) > 0 && b[len(b)-1] == 'u':
+				// 分解形式的 ü
+				b[len(b)-1] = 'v'
+				continue
+			case unicode.Is(unicode.Mn, r):
+				// 分解形式的声调符号
+				continue
+			}
+			r = foldTone(r)
+			if r < 'a' || r > 'z' {
+				return nil, fmt.Errorf("unsupported character %q in reading %q", r, reading)
+			}
+			b = append(b, byte(r))
+		}
+		if len(b) > 0 {
+			syllables = append(syllables, string(b))
+		}
+	}
+	if len(syllables) == 0 {
+		return nil, fmt.Errorf("empty reading")
+	}
+	return syllables, nil
+}
+
+// LoadRomanDictionary 加载罗马字模式的词库：每行为 "中日文<TAB>读音"，# 开头的行是注释
+// 格式不对或读音无法识别的行被跳过
+func (g *Game) LoadRomanDictionary(path string) error {
+	file, err := g.open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open romanization dictionary: %w", err)
+	}
+	defer file.Close()
+
+	entries := make([]RomanEntry, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		target, reading, ok := strings.Cut(line, "\t")
+		target = strings.TrimSpace(target)
+		if !ok || target == "" {
+			continue
+		}
+		syllables, err := ParseReading(reading)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, RomanEntry{Target: target, Syllables: syllables})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read romanization dictionary: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return fmt.Errorf("romanization dictionary has no target<TAB>reading lines")
+	}
+
+	g.romanPool = entries
+	return nil
+}
+
+// HasRomanDictionary 罗马字模式的词库是否已加载
+func (g *Game) HasRomanDictionary() bool {
+	return len(g.romanPool) > 0
+}
+
+// StartRomanizationMode 启动罗马字模式：显示中日文，输入不带声调的拼音或罗马字消除
+// wordCount 为本局的词数（0 时为 20 个），全部消除后结束
+func (g *Game) StartRomanizationMode(wordCount int) error {
+	if len(g.romanPool) == 0 {
+		return fmt.Errorf("romanization dictionary not loaded")
+	}
+	if wordCount <= 0 {
+		wordCount = 20
+	}
+
+	// 重置游戏状态
+	g.Status = StatusRunning
+	g.Mode = ModeRomanization
+	g.InputBuffer = ""
+	g.Aborted = false
+	g.beginRound()
+
+	g.Words = g.selectRomanWords(wordCount)
+	for _, w := range g.Words {
+		g.emit(Event{Type: EventWordSpawned, Text: w.Text})
+	}
+
+	return nil
+}
+
+// selectRomanWords 从词库中随机选出不重复的词（读音相同的词一局只出现一次，避免输入有歧义）
+func (g *Game) selectRomanWords(count int) []Word {
+	available := make([]RomanEntry, 0, len(g.romanPool))
+	for _, e := range g.romanPool {
+		if !g.usedWords[strings.Join(e.Syllables, "")] {
+			available = append(available, e)
+		}
+	}
+
+	words := make([]Word, 0, count)
+	for len(words) < count && len(available) > 0 {
+		idx := g.rng.Intn(len(available))
+		e := available[idx]
+		available[idx] = available[len(available)-1]
+		available = available[:len(available)-1]
+
+		text := strings.Join(e.Syllables, "")
+		if g.usedWords[text] {
+			continue
+		}
+		g.usedWords[text] = true
+		words = append(words, Word{Text: text, Target: e.Target, Syllables: e.Syllables})
+	}
+	return words
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"fmt"
+	"time"
+)
+
+// Judgment 节奏舞蹈的判定等级
+type Judgment int
+
+const (
+	JudgmentNone    Judgment = iota // 还没有判定
+	JudgmentMiss                    // 单词错误或时机超出 OK 窗口
+	JudgmentOK                      // OK 窗口内
+	JudgmentNice                    // Nice 窗口内
+	JudgmentPerfect                 // Perfect 窗口内
+)
+
+var judgmentNames = map[Judgment]string{
+	JudgmentMiss:    "Miss",
+	JudgmentOK:      "OK",
+	JudgmentNice:    "Nice",
+	JudgmentPerfect: "Perfect",
+}
+
+// String 返回判定等级的显示名称（事件和录像中也使用这个名称）
+func (j Judgment) String() string {
+	return judgmentNames[j]
+}
+
+// ParseJudgment 按显示名称解析判定等级
+func ParseJudgment(s string) (Judgment, error) {
+	for j, name := range judgmentNames {
+		if name == s {
+			return j, nil
+		}
+	}
+	return JudgmentNone, fmt.Errorf("unknown judgment %q", s)
+}
+
+// ComboMultiplier 连击倍率的一档：连击数达到 Combo 后得分乘以 Multiplier
+type ComboMultiplier struct {
+	Combo      int
+	Multiplier float64
+}
+
+// ScoringRules 节奏舞蹈的计分规则
+type ScoringRules struct {
+	Windows          RhythmWindows
+	Points           map[Judgment]int  // 各判定的得分
+	ComboMultipliers []ComboMultiplier // 按 Combo 递增排列
+	MissLimit        int               // Miss 达到此次数时游戏结束，0 表示不限
+	OKBreaksCombo    bool              // OK 是否中断连击（Miss 总是中断连击）
+}
+
+// DefaultScoringRules 默认计分规则，必须与配置中的 standard 一致（cmd/word-killer 的测试会检查）
+var DefaultScoringRules = ScoringRules{
+	Windows: DefaultRhythmWindows,
+	Points: map[Judgment]int{
+		JudgmentPerfect: 5,
+		JudgmentNice:    3,
+		JudgmentOK:      1,
+		JudgmentMiss:    -1,
+	},
+	MissLimit:     10,
+	OKBreaksCombo: true,
+}
+
+// Multiplier 连击数为 combo 时的得分倍率
+func (r ScoringRules) Multiplier(combo int) float64 {
+	return comboMultiplier(r.ComboMultipliers, combo)
+}
+
+// comboMultiplier 在按 Combo 递增排列的倍率表中查找连击数对应的倍率，未达到第一档时为 1
+func comboMultiplier(tiers []ComboMultiplier, combo int) float64 {
+	multiplier := 1.0
+	for _, m := range tiers {
+		if combo < m.Combo {
+			break
+		}
+		multiplier = m.Multiplier
+	}
+	return multiplier
+}
+
+// Score 按规则计算一次判定的得分；倍率只作用于正分，扣分不随连击放大
+func (r ScoringRules) Score(j Judgment, combo int) int {
+	points := r.Points[j]
+	if points <= 0 {
+		return points
+	}
+	return int(float64(points) * r.Multiplier(combo))
+}
+
+// breaksCombo 判定是否中断连击
+func (r ScoringRules) breaksCombo(j Judgment) bool {
+	return j == JudgmentMiss || (j == JudgmentOK && r.OKBreaksCombo)
+}
+
+// recordJudgment 按计分规则记录一次判定：计数、连击、得分、历史和事件
+// 返回本次得分
+func (g *Game) recordJudgment(j Judgment, offset time.Duration) int {
+	state := g.RhythmDanceState
+	rules := state.Rules
+
+	switch j {
+	case JudgmentPerfect:
+		state.PerfectCount++
+	case JudgmentNice:
+		state.NiceCount++
+	case JudgmentOK:
+		state.OKCount++
+	default:
+		state.MissCount++
+	}
+
+	if rules.breaksCombo(j) {
+		state.CurrentCombo = 0
+	} else {
+		state.CurrentCombo++
+	}
+	if state.CurrentCombo > state.MaxCombo {
+		state.MaxCombo = state.CurrentCombo
+	}
+
+	score := rules.Score(j, state.CurrentCombo)
+	state.TotalScore += score
+
+	// 记录最近判定（用于特效显示）
+	state.LastJudgment = j
+	state.LastJudgmentTime = g.clock.Now()
+	state.LastJudgmentOffset = offset
+
+	state.JudgmentHistory = append(state.JudgmentHistory, j)
+	g.emit(Event{Type: EventJudgment, Text: j.String(), Score: score})
+	return score
+}
@@ -1,8 +1,8 @@
 package game
 
 import (
-	"math/rand"
 	"time"
+	"unicode/utf8"
 )
 
 // getFishASCII 根据大小获取小鱼ASCII（内部函数避免循环导入）
@@ -35,7 +35,6 @@ type Fish struct {
 // UnderwaterState 海底模式状态
 type UnderwaterState struct {
 	Fishes           []Fish
-	CountdownStart   time.Time      // 倒计时开始时间
 	BackgroundFrame  int            // 背景动画帧
 	SeaweedPositions []int          // 海藻X位置 (5列)
 	BubbleStreams    []BubbleStream // 气泡流
@@ -70,11 +69,11 @@ func (g *Game) GenerateFishes(count int) []Fish {
 		}
 
 		// 随机选择一个单词
-		word := words[rand.Intn(len(words))]
+		word := words[g.rng.Intn(len(words))]
 
 		// 根据单词长度确定小鱼大小
 		var size int
-		wordLen := len(word)
+		wordLen := utf8.RuneCountInString(word)
 		if wordLen <= 5 {
 			size = 1 // 小
 		} else if wordLen <= 10 {
@@ -84,7 +83,7 @@ func (g *Game) GenerateFishes(count int) []Fish {
 		}
 
 		// 计算小鱼+单词所需的总宽度（>word< 格式）
-		totalWidth := len(word) + 2 + 6 // 单词+2个括号+6格缓冲
+		totalWidth := wordLen + 2 + 6 // 单词+2个括号+6格缓冲
 
 		// 尝试找到一个不重叠的位置（最多尝试100次）
 		var fish Fish
@@ -103,20 +102,20 @@ func (g *Game) GenerateFishes(count int) []Fish {
 					}
 				}
 				// 在该行附近随机偏移±1行
-				if rand.Float64() < 0.3 && y > 0 {
+				if g.rng.Float64() < 0.3 && y > 0 {
 					y--
-				} else if rand.Float64() < 0.3 && y < fishRows-1 {
+				} else if g.rng.Float64() < 0.3 && y < fishRows-1 {
 					y++
 				}
 			} else {
 				// 后50次尝试：完全随机
-				y = rand.Intn(fishRows)
+				y = g.rng.Intn(fishRows)
 			}
 
 			// Y坐标从2开始（顶部2行留给波浪）
 			actualY := y + 2
 
-			x := rand.Float64()
+			x := g.rng.Float64()
 			xPos := int(x * 72)
 
 			// 检查是否与已有小鱼重叠
@@ -129,8 +128,8 @@ func (g *Game) GenerateFishes(count int) []Fish {
 					Word:      word,
 					X:         x,
 					Y:         actualY,
-					Speed:     0.003 + rand.Float64()*0.007, // 减慢速度：0.003-0.010
-					Direction: []int{-1, 1}[rand.Intn(2)],
+					Speed:     0.003 + g.rng.Float64()*0.007, // 减慢速度：0.003-0.010
+					Direction: []int{-1, 1}[g.rng.Intn(2)],
 					Size:      size,
 					Completed: false,
 					Glowing:   false,
@@ -143,6 +142,7 @@ func (g *Game) GenerateFishes(count int) []Fish {
 		// 如果找到了合适位置就添加
 		if placed {
 			fishes = append(fishes, fish)
+			g.emit(Event{Type: EventWordSpawned, Text: fish.Word})
 		}
 	}
 
@@ -206,7 +206,7 @@ func (g *Game) UpdateFishPositions() {
 
 		// 检查发光动画是否结束（800ms后）
 		if fish.Completed && fish.Glowing {
-			if time.Since(fish.CompletedAt).Milliseconds() > 800 {
+			if g.clock.Now().Sub(fish.CompletedAt).Milliseconds() > 800 {
 				fish.Glowing = false // 结束发光状态
 				toRemove = append(toRemove, i)
 			}
@@ -283,13 +283,13 @@ func generateSeaweedPositions() []int {
 }
 
 // generateBubbleStreams 生成气泡流
-func generateBubbleStreams() []BubbleStream {
+func (g *Game) generateBubbleStreams() []BubbleStream {
 	streams := make([]BubbleStream, 8)
 	for i := 0; i < 8; i++ {
 		streams[i] = BubbleStream{
-			X:      5 + i*9,                        // 均匀分布
-			Y:      rand.Float64() * 20,            // 随机初始高度（适配20行）
-			Speed:  0.08 + rand.Float64()*0.04,     // 0.08-0.12
+			X:      5 + i*9,                     // 均匀分布
+			Y:      g.rng.Float64() * 20,        // 随机初始高度（适配20行）
+			Speed:  0.08 + g.rng.Float64()*0.04, // 0.08-0.12
 			Active: true,
 		}
 	}
@@ -0,0 +1,112 @@
+package game
+
+// 单词模式（经典、倒计时、极速）的计分：
+// 每个单词按字母稀有度计分（常用字母 1 分，q、z 等罕见字母最高 10 分，越长的单词分数越高），
+// 连续"干净"消除（输入过程中没有退格和错误按键）按连击数提高倍率，回车时没有匹配的单词则扣分。
+
+import "unicode"
+
+// wrongEnterPenalty 错误回车扣除的分数
+const wrongEnterPenalty = 5
+
+// letterPoints 各字母的分值（按英文中的出现频率，越罕见分值越高）
+var letterPoints = [26]int{
+	1, 3, 3, 2, 1, 4, 2, 4, 1, 8, 5, 1, 3, // a-m
+	1, 1, 3, 10, 1, 1, 1, 1, 4, 4, 8, 4, 10, // n-z
+}
+
+// otherLetterPoints a-z 以外的字母（重音字母、西里尔字母、希腊字母等）的分值：没有频率数据，按中等稀有度计分
+const otherLetterPoints = 2
+
+// wordStreakMultipliers 连续干净消除的倍率
+var wordStreakMultipliers = []ComboMultiplier{
+	{Combo: 5, Multiplier: 1.5},
+	{Combo: 10, Multiplier: 2},
+	{Combo: 20, Multiplier: 3},
+}
+
+// WordScore 单词模式的得分状态
+type WordScore struct {
+	Score     int // 总分（错误回车可能使其为负数）
+	Streak    int // 当前连续干净消除的单词数
+	MaxStreak int // 最长连续干净消除
+	Penalties int // 错误回车次数
+
+	dirty bool // 当前单词输入过程中是否退格或按错
+}
+
+// WordPoints 单词的基础分：各字母分值之和（非字母不计分）
+func WordPoints(word string) int {
+	points := 0
+	for _, ch := range word {
+		switch {
+		case ch >= 'a' && ch <= 'z':
+			points += letterPoints[ch-'a']
+		case ch >= 'A' && ch <= 'Z':
+			points += letterPoints[ch-'A']
+		case unicode.IsLetter(ch):
+			points += otherLetterPoints
+		}
+	}
+	return points
+}
+
+// Multiplier 当前连击对应的得分倍率
+func (s *WordScore) Multiplier() float64 {
+	return comboMultiplier(wordStreakMultipliers, s.Streak)
+}
+
+// scoresWords 当前模式是否使用单词计分
+func (g *Game) scoresWords() bool {
+	switch g.Mode {
+	case ModeClassic, ModeCountdown, ModeSpeedRun:
+		return true
+	}
+	return false
+}
+
+// resetWordScore 开局时重置得分（不计分的模式为 nil）
+func (g *Game) resetWordScore() {
+	g.WordScore = nil
+	if g.scoresWords() {
+		g.WordScore = &WordScore{}
+	}
+}
+
+// markDirty 当前单词出现退格或错误按键，消除后不计入连击
+func (g *Game) markDirty() {
+	if g.WordScore != nil {
+		g.WordScore.dirty = true
+	}
+}
+
+// scoreWord 消除单词后计分并返回本次得分
+func (g *Game) scoreWord(word string) int {
+	s := g.WordScore
+	if s == nil {
+		return 0
+	}
+	if s.dirty {
+		s.Streak = 0
+	} else {
+		s.Streak++
+		s.MaxStreak = max(s.MaxStreak, s.Streak)
+	}
+	s.dirty = false
+
+	points := int(float64(WordPoints(word)) * s.Multiplier())
+	s.Score += points
+	return points
+}
+
+// penalizeWrongEnter 回车时没有匹配的单词：扣分并中断连击
+func (g *Game) penalizeWrongEnter() {
+	s := g.WordScore
+	if s == nil {
+		return
+	}
+	s.Score -= wrongEnterPenalty
+	s.Penalties++
+	s.Streak = 0
+	s.dirty = false
+}
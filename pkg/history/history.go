@@ -0,0 +1,302 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/stats"
+)
+
+// 历史文件格式标识与版本
+const (
+	FileFormat     = "word-killer-history"
+	CurrentVersion = 1
+)
+
+// header 历史文件的第一行
+type header struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+}
+
+// Record 一局已结束游戏的完整记录
+type Record struct {
+	Mode      string          `json:"mode"`
+	StartedAt time.Time       `json:"started_at"`
+	EndedAt   time.Time       `json:"ended_at"`
+	Aborted   bool            `json:"aborted"`
+	Config    config.Config   `json:"config"`
+	Stats     stats.Snapshot  `json:"stats"`
+	Replay    string          `json:"replay,omitempty"` // 回放文件路径
+	Keys      *stats.KeyStats `json:"keys,omitempty"`   // 按键统计（旧记录中没有）
+
+	// 模式专属数据（只有对应模式才会填写）
+	RhythmMaster *RhythmMasterSummary `json:"rhythm_master,omitempty"`
+	RhythmDance  *RhythmDanceSummary  `json:"rhythm_dance,omitempty"`
+	WordScore    *WordScoreSummary    `json:"word_score,omitempty"` // 经典、倒计时、极速模式
+}
+
+// WordScoreSummary 单词模式的得分
+type WordScoreSummary struct {
+	Score     int `json:"score"`
+	MaxStreak int `json:"max_streak"`
+	Penalties int `json:"penalties"` // 错误回车次数
+}
+
+// RhythmMasterSummary 节奏大师模式的结束状态
+type RhythmMasterSummary struct {
+	DifficultyLevel      int     `json:"difficulty_level"`
+	ConsecutiveSuccesses int     `json:"consecutive_successes"`
+	FinalTimeLimit       float64 `json:"final_time_limit"` // 秒
+}
+
+// RhythmDanceSummary 节奏舞蹈模式的结束状态
+type RhythmDanceSummary struct {
+	TotalScore     int `json:"total_score"`
+	CompletedWords int `json:"completed_words"`
+	MaxCombo       int `json:"max_combo"`
+	PerfectCount   int `json:"perfect_count"`
+	NiceCount      int `json:"nice_count"`
+	OKCount        int `json:"ok_count"`
+	MissCount      int `json:"miss_count"`
+}
+
+// Store 基于 JSON Lines 文件的历史记录存储
+// 文件第一行是格式头，之后每行一条 Record，只追加不改写
+type Store struct {
+	path string
+}
+
+// Open 创建指向指定文件的存储（文件不存在时首次写入会自动创建）
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path 返回历史文件路径
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append 追加一条记录
+func (s *Store) Append(rec Record) error {
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create history directory: %w", err)
+		}
+	}
+
+	// 已有文件时先确认版本兼容
+	isNew := false
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		isNew = true
+	} else if err != nil {
+		return fmt.Errorf("failed to stat history file: %w", err)
+	} else if err := s.checkHeader(); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	if isNew {
+		if err := encoder.Encode(header{Format: FileFormat, Version: CurrentVersion}); err != nil {
+			return fmt.Errorf("failed to write history header: %w", err)
+		}
+	}
+	if err := encoder.Encode(rec); err != nil {
+		return fmt.Errorf("failed to write history record: %w", err)
+	}
+
+	return nil
+}
+
+// checkHeader 读取并校验文件头
+func (s *Store) checkHeader() error {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read history file: %w", err)
+		}
+		return fmt.Errorf("history file %s is empty", s.path)
+	}
+	return parseHeader(scanner.Bytes())
+}
+
+// parseHeader 解析文件头并检查版本
+func parseHeader(line []byte) error {
+	var h header
+	if err := json.Unmarshal(line, &h); err != nil || h.Format != FileFormat {
+		return fmt.Errorf("not a word-killer history file")
+	}
+	if h.Version > CurrentVersion {
+		return fmt.Errorf("history file version %d is newer than supported version %d", h.Version, CurrentVersion)
+	}
+	return nil
+}
+
+// Load 读取全部记录（按写入顺序）
+func (s *Store) Load() ([]Record, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil // 还没有历史记录
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	records := make([]Record, 0)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if lineNo == 1 {
+			if err := parseHeader(line); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var rec Record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("failed to parse history line %d: %w", lineNo, err)
+		}
+		records = append(records, rec)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return records, nil
+}
+
+// Query 历史查询条件（零值字段表示不过滤）
+type Query struct {
+	Mode           string    // 模式名称，如 "countdown"
+	Since          time.Time // 结束时间 >= Since
+	Until          time.Time // 结束时间 < Until
+	IncludeAborted bool      // 是否包含中止的对局
+	Limit          int       // 只返回最近的 N 条
+}
+
+// Query 按条件查询记录，结果按结束时间升序排列
+func (s *Store) Query(q Query) ([]Record, error) {
+	all, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	return Filter(all, q), nil
+}
+
+// Filter 在内存中按条件过滤记录，结果按结束时间升序排列
+func Filter(records []Record, q Query) []Record {
+	result := make([]Record, 0, len(records))
+	for _, rec := range records {
+		if q.Mode != "" && rec.Mode != q.Mode {
+			continue
+		}
+		if !q.IncludeAborted && rec.Aborted {
+			continue
+		}
+		if !q.Since.IsZero() && rec.EndedAt.Before(q.Since) {
+			continue
+		}
+		if !q.Until.IsZero() && !rec.EndedAt.Before(q.Until) {
+			continue
+		}
+		result = append(result, rec)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].EndedAt.Before(result[j].EndedAt)
+	})
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[len(result)-q.Limit:]
+	}
+	return result
+}
+
+// PeriodSummary 一段时间内的汇总数据
+type PeriodSummary struct {
+	Start                time.Time // 周期开始（周一 00:00，本地时间）
+	Games                int
+	TotalWords           int
+	AvgLettersPerSecond  float64
+	AvgAccuracy          float64
+	BestLettersPerSecond float64
+}
+
+// WeeklyProgress 把记录按自然周（周一开始）汇总，结果按时间升序排列
+func WeeklyProgress(records []Record) []PeriodSummary {
+	buckets := make(map[time.Time]*PeriodSummary)
+	for _, rec := range records {
+		start := weekStart(rec.EndedAt)
+		sum, ok := buckets[start]
+		if !ok {
+			sum = &PeriodSummary{Start: start}
+			buckets[start] = sum
+		}
+		sum.Games++
+		sum.TotalWords += rec.Stats.WordsCompleted
+		sum.AvgLettersPerSecond += rec.Stats.LettersPerSecond
+		sum.AvgAccuracy += rec.Stats.AccuracyPercent
+		if rec.Stats.LettersPerSecond > sum.BestLettersPerSecond {
+			sum.BestLettersPerSecond = rec.Stats.LettersPerSecond
+		}
+	}
+
+	result := make([]PeriodSummary, 0, len(buckets))
+	for _, sum := range buckets {
+		sum.AvgLettersPerSecond /= float64(sum.Games)
+		sum.AvgAccuracy /= float64(sum.Games)
+		result = append(result, *sum)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+// KeyProfile 汇总多局的按键统计（没有按键统计的旧记录跳过）
+func KeyProfile(records []Record) stats.KeyStats {
+	var profile stats.KeyStats
+	for _, rec := range records {
+		if rec.Keys != nil {
+			profile.Merge(*rec.Keys)
+		}
+	}
+	return profile
+}
+
+// weekStart 返回所在周周一零点
+func weekStart(t time.Time) time.Time {
+	t = t.Local()
+	offset := (int(t.Weekday()) + 6) % 7 // 周一为0
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,113 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/stats"
+)
+
+func TestAppendAndQuery(t *testing.T) {
+	store := Open(filepath.Join(t.TempDir(), "sub", "history.jsonl"))
+
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local) // 周一
+	records := []Record{
+		{Mode: "countdown", EndedAt: base, Config: *config.DefaultConfig(), Stats: stats.Snapshot{WordsCompleted: 10, LettersPerSecond: 2}},
+		{Mode: "speedrun", EndedAt: base.Add(time.Hour), Stats: stats.Snapshot{WordsCompleted: 25}},
+		{Mode: "countdown", EndedAt: base.AddDate(0, 0, 8), Stats: stats.Snapshot{WordsCompleted: 14, LettersPerSecond: 3}},
+		{Mode: "countdown", EndedAt: base.AddDate(0, 0, 9), Aborted: true},
+		{Mode: "rhythm-dance", EndedAt: base.AddDate(0, 0, 9), RhythmDance: &RhythmDanceSummary{TotalScore: 42, PerfectCount: 3}},
+	}
+	for _, rec := range records {
+		if err := store.Append(rec); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	all, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(all) != len(records) {
+		t.Fatalf("Load returned %d records, want %d", len(all), len(records))
+	}
+	if all[0].Config.CountdownDuration != 60 {
+		t.Errorf("config snapshot not persisted: %+v", all[0].Config)
+	}
+	if all[4].RhythmDance == nil || all[4].RhythmDance.TotalScore != 42 {
+		t.Errorf("rhythm dance summary not persisted: %+v", all[4].RhythmDance)
+	}
+
+	countdown, err := store.Query(Query{Mode: "countdown"})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(countdown) != 2 {
+		t.Fatalf("Query(countdown) = %d records, want 2 (aborted excluded)", len(countdown))
+	}
+
+	latest := Filter(all, Query{Mode: "countdown", IncludeAborted: true, Limit: 1})
+	if len(latest) != 1 || !latest[0].Aborted {
+		t.Errorf("Limit should keep the most recent record, got %+v", latest)
+	}
+
+	weeks := WeeklyProgress(countdown)
+	if len(weeks) != 2 {
+		t.Fatalf("WeeklyProgress = %d weeks, want 2", len(weeks))
+	}
+	if weeks[0].TotalWords != 10 || weeks[1].TotalWords != 14 {
+		t.Errorf("unexpected weekly totals: %+v", weeks)
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	content := `{"format":"word-killer-history","version":99}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := Open(path)
+	if _, err := store.Load(); err == nil {
+		t.Error("expected error for newer file version")
+	}
+	if err := store.Append(Record{Mode: "classic"}); err == nil {
+		t.Error("expected Append to refuse a newer file version")
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	records, err := Open(filepath.Join(t.TempDir(), "none.jsonl")).Load()
+	if err != nil || len(records) != 0 {
+		t.Errorf("Load on missing file = %v, %v; want empty, nil", records, err)
+	}
+}
+
+func TestKeyProfile(t *testing.T) {
+	store := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	games := []*stats.KeyStats{
+		{Keys: map[string]stats.KeyStat{"e": {Attempts: 3, Errors: 1}}, Substitutions: map[string]int{"er": 1}},
+		nil, // 旧记录没有按键统计
+		{Keys: map[string]stats.KeyStat{"e": {Attempts: 2}, "t": {Attempts: 1}}, Substitutions: map[string]int{"er": 2}},
+	}
+	for _, keys := range games {
+		if err := store.Append(Record{Mode: "classic", Keys: keys}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	all, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	profile := KeyProfile(all)
+	if e := profile.Keys["e"]; e.Attempts != 5 || e.Errors != 1 {
+		t.Errorf("e = %+v, want 5 attempts with 1 error", e)
+	}
+	if profile.Keys["t"].Attempts != 1 || profile.Substitutions["er"] != 3 {
+		t.Errorf("profile = %+v", profile)
+	}
+}
@@ -0,0 +1,121 @@
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	appName = "word-killer"
+
+	// ConfigEnv 指定配置文件路径的环境变量
+	ConfigEnv = "WORD_KILLER_CONFIG"
+
+	configFile = "config.json"
+)
+
+// ConfigSource 配置文件的来源
+type ConfigSource int
+
+const (
+	SourceDefaults ConfigSource = iota // 没有找到配置文件，使用内置默认值
+	SourceFlag                         // --config 参数
+	SourceEnv                          // $WORD_KILLER_CONFIG
+	SourceXDG                          // $XDG_CONFIG_HOME/word-killer/config.json
+)
+
+// ConfigDir 返回配置目录：$XDG_CONFIG_HOME/word-killer，未设置时使用系统默认位置
+func ConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, appName)
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, appName)
+}
+
+// DefaultConfigPath 返回保存配置的默认位置：$XDG_CONFIG_HOME/word-killer/config.json
+func DefaultConfigPath() string {
+	dir := ConfigDir()
+	if dir == "" {
+		return configFile
+	}
+	return filepath.Join(dir, configFile)
+}
+
+// DataDir 返回数据目录（历史、排行榜、回放）：$XDG_DATA_HOME/word-killer
+// 未设置时 Linux/macOS 使用 ~/.local/share/word-killer，Windows 使用 %LocalAppData%\word-killer
+func DataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, appName)
+	}
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("LocalAppData"); dir != "" {
+			return filepath.Join(dir, appName)
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	return filepath.Join(home, ".local", "share", appName)
+}
+
+// FindConfig 按搜索顺序查找配置文件：
+// --config 参数 → $WORD_KILLER_CONFIG → $XDG_CONFIG_HOME/word-killer/config.json
+// 显式指定的路径（参数或环境变量）直接返回，不检查是否存在；都没有找到时返回 SourceDefaults
+// 不读取当前目录，使用哪个配置与从哪里启动无关
+func FindConfig(flagPath string) (string, ConfigSource) {
+	if flagPath != "" {
+		return flagPath, SourceFlag
+	}
+	if path := os.Getenv(ConfigEnv); path != "" {
+		return path, SourceEnv
+	}
+	if dir := ConfigDir(); dir != "" {
+		path := filepath.Join(dir, configFile)
+		if fileExists(path) {
+			return path, SourceXDG
+		}
+	}
+	return "", SourceDefaults
+}
+
+// MigrateLegacyConfig 旧版本读取当前目录下的 config.json：XDG 配置目录中还没有配置文件时复制过去（原文件保留）
+// XDG 位置已有配置文件后不再复制，因此只迁移一次。返回复制到的路径，没有迁移时为空
+func MigrateLegacyConfig() (string, error) {
+	dir := ConfigDir()
+	if dir == "" || !fileExists(configFile) {
+		return "", nil
+	}
+	path := filepath.Join(dir, configFile)
+	if fileExists(path) {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read legacy config: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to migrate legacy config: %w", err)
+	}
+	return path, nil
+}
+
+// IsExplicit 配置来源是否由用户显式指定
+func (s ConfigSource) IsExplicit() bool {
+	return s == SourceFlag || s == SourceEnv
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,76 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindConfigSearchOrder(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Setenv(ConfigEnv, "")
+	t.Chdir(t.TempDir())
+
+	if path, src := FindConfig(""); src != SourceDefaults || path != "" {
+		t.Errorf("no config anywhere: got %q, %v", path, src)
+	}
+
+	// 当前目录下的 config.json 不参与查找
+	os.WriteFile(configFile, []byte("{}"), 0o644)
+	if path, src := FindConfig(""); src != SourceDefaults || path != "" {
+		t.Errorf("./config.json must not be used: got %q, %v", path, src)
+	}
+
+	xdgConfig := filepath.Join(xdg, appName, configFile)
+	os.MkdirAll(filepath.Dir(xdgConfig), 0o755)
+	os.WriteFile(xdgConfig, []byte("{}"), 0o644)
+	if path, src := FindConfig(""); src != SourceXDG || path != xdgConfig {
+		t.Errorf("XDG config: got %q, %v", path, src)
+	}
+
+	t.Setenv(ConfigEnv, "/etc/wk.json")
+	if path, src := FindConfig(""); src != SourceEnv || path != "/etc/wk.json" {
+		t.Errorf("env config: got %q, %v", path, src)
+	}
+
+	if path, src := FindConfig("my.json"); src != SourceFlag || path != "my.json" || !src.IsExplicit() {
+		t.Errorf("flag config: got %q, %v", path, src)
+	}
+}
+
+func TestMigrateLegacyConfig(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Chdir(t.TempDir())
+
+	if path, err := MigrateLegacyConfig(); err != nil || path != "" {
+		t.Errorf("nothing to migrate: got %q, %v", path, err)
+	}
+
+	os.WriteFile(configFile, []byte(`{"word_count": 7}`), 0o644)
+	path, err := MigrateLegacyConfig()
+	xdgConfig := filepath.Join(xdg, appName, configFile)
+	if err != nil || path != xdgConfig {
+		t.Fatalf("MigrateLegacyConfig() = %q, %v; want %q", path, err, xdgConfig)
+	}
+	if data, _ := os.ReadFile(xdgConfig); string(data) != `{"word_count": 7}` {
+		t.Errorf("migrated config = %q", data)
+	}
+
+	// 只迁移一次：之后的改动不会覆盖 XDG 位置的配置
+	os.WriteFile(configFile, []byte(`{"word_count": 9}`), 0o644)
+	if path, err := MigrateLegacyConfig(); err != nil || path != "" {
+		t.Errorf("second migration: got %q, %v", path, err)
+	}
+	if data, _ := os.ReadFile(xdgConfig); string(data) != `{"word_count": 7}` {
+		t.Errorf("config overwritten by a second migration: %q", data)
+	}
+}
+
+func TestDataDirUsesXDG(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/tmp/xdg-data")
+	if got := DataDir(); got != filepath.Join("/tmp/xdg-data", appName) {
+		t.Errorf("DataDir() = %q", got)
+	}
+}
@@ -0,0 +1,353 @@
+package records
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/history"
+)
+
+// 记录文件版本
+const CurrentVersion = 1
+
+// DefaultLimit 每个排行榜保留的成绩条数
+const DefaultLimit = 10
+
+// Category 排行榜分类：模式 + 影响成绩可比性的配置变体
+type Category struct {
+	Key           string // 唯一键，如 "speedrun/words=25/ratios=30:50:20"
+	Label         string // 显示名称
+	Unit          string // 成绩单位，如 "s"、"words"、"pts"
+	LowerIsBetter bool   // true 表示数值越小越好（用时类）
+}
+
+// Entry 排行榜上的一条成绩
+type Entry struct {
+	Value    float64   `json:"value"`
+	Achieved time.Time `json:"achieved"`
+	Words    int       `json:"words"`
+	Accuracy float64   `json:"accuracy"`
+	Replay   string    `json:"replay,omitempty"` // 回放文件路径（用于幽灵赛跑）
+}
+
+// Board 单个分类的排行榜（已排序，最好的在前）
+type Board struct {
+	Label         string  `json:"label"`
+	Unit          string  `json:"unit"`
+	LowerIsBetter bool    `json:"lower_is_better"`
+	Entries       []Entry `json:"entries"`
+}
+
+// book 记录文件的内容
+type book struct {
+	Version int               `json:"version"`
+	Boards  map[string]*Board `json:"boards"`
+}
+
+// Result 提交成绩后的结果
+type Result struct {
+	Category    Category
+	Value       float64
+	Rank        int  // 1 开始的名次，0 表示未上榜
+	IsNewRecord bool // 是否刷新该分类的最佳成绩
+	Previous    *Entry
+}
+
+// Store 个人最佳成绩存储
+type Store struct {
+	path  string
+	limit int
+	data  book
+}
+
+// Load 从文件加载记录（文件不存在时返回空存储）
+func Load(path string) (*Store, error) {
+	s := &Store{
+		path:  path,
+		limit: DefaultLimit,
+		data:  book{Version: CurrentVersion, Boards: make(map[string]*Board)},
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open records file: %w", err)
+	}
+	defer file.Close()
+
+	var data book
+	if err := json.NewDecoder(file).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse records file: %w", err)
+	}
+	if data.Version > CurrentVersion {
+		return nil, fmt.Errorf("records file version %d is newer than supported version %d", data.Version, CurrentVersion)
+	}
+	if data.Boards == nil {
+		data.Boards = make(map[string]*Board)
+	}
+	data.Version = CurrentVersion
+	s.data = data
+
+	return s, nil
+}
+
+// Save 保存记录到文件
+func (s *Store) Save() error {
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create records directory: %w", err)
+		}
+	}
+
+	file, err := os.Create(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to create records file: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(&s.data); err != nil {
+		return fmt.Errorf("failed to write records file: %w", err)
+	}
+
+	return nil
+}
+
+// Board 获取指定分类的排行榜
+func (s *Store) Board(key string) (*Board, bool) {
+	board, ok := s.data.Boards[key]
+	return board, ok
+}
+
+// Keys 返回所有排行榜分类键（已排序）
+func (s *Store) Keys() []string {
+	keys := make([]string, 0, len(s.data.Boards))
+	for key := range s.data.Boards {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Replays 返回所有排行榜成绩引用的回放文件路径
+func (s *Store) Replays() map[string]bool {
+	paths := make(map[string]bool)
+	for _, board := range s.data.Boards {
+		for _, e := range board.Entries {
+			if e.Replay != "" {
+				paths[filepath.Clean(e.Replay)] = true
+			}
+		}
+	}
+	return paths
+}
+
+// Best 获取指定分类的最佳成绩
+func (s *Store) Best(key string) (Entry, bool) {
+	board, ok := s.data.Boards[key]
+	if !ok || len(board.Entries) == 0 {
+		return Entry{}, false
+	}
+	return board.Entries[0], true
+}
+
+// Submit 根据历史记录提交成绩；不计入排行榜的对局返回 ok=false
+func (s *Store) Submit(rec history.Record) (Result, bool) {
+	cat, value, ok := Classify(rec)
+	if !ok {
+		return Result{}, false
+	}
+
+	entry := Entry{
+		Value:    value,
+		Achieved: rec.EndedAt,
+		Words:    rec.Stats.WordsCompleted,
+		Accuracy: rec.Stats.AccuracyPercent,
+		Replay:   rec.Replay,
+	}
+	return s.insert(cat, entry), true
+}
+
+// insert 把成绩插入排行榜并返回名次
+func (s *Store) insert(cat Category, entry Entry) Result {
+	board, ok := s.data.Boards[cat.Key]
+	if !ok {
+		board = &Board{}
+		s.data.Boards[cat.Key] = board
+	}
+	board.Label = cat.Label
+	board.Unit = cat.Unit
+	board.LowerIsBetter = cat.LowerIsBetter
+
+	result := Result{Category: cat, Value: entry.Value}
+	if len(board.Entries) > 0 {
+		prev := board.Entries[0]
+		result.Previous = &prev
+	}
+
+	// 新成绩必须严格更好才能排到同分成绩之前
+	pos := sort.Search(len(board.Entries), func(i int) bool {
+		return board.better(entry.Value, board.Entries[i].Value)
+	})
+	if pos >= s.limit {
+		return result
+	}
+
+	board.Entries = append(board.Entries, Entry{})
+	copy(board.Entries[pos+1:], board.Entries[pos:])
+	board.Entries[pos] = entry
+	if len(board.Entries) > s.limit {
+		board.Entries = board.Entries[:s.limit]
+	}
+
+	result.Rank = pos + 1
+	result.IsNewRecord = pos == 0
+	return result
+}
+
+// better 判断 a 是否严格优于 b
+func (b *Board) better(a, other float64) bool {
+	if b.LowerIsBetter {
+		return a < other
+	}
+	return a > other
+}
+
+// CategoryFor 根据模式和配置确定排行榜分类
+func CategoryFor(mode string, cfg config.Config) Category {
+	ratios := fmt.Sprintf("ratios=%g:%g:%g", cfg.ShortRatio, cfg.MediumRatio, cfg.LongRatio)
+	// 按目标难度选词时长度配比不起作用，单词难度取决于键盘布局
+	if cfg.DifficultyTarget > 0 {
+		layout := cfg.KeyboardLayout
+		if layout == "" {
+			layout = "qwerty"
+		}
+		ratios = fmt.Sprintf("difficulty=%d/%s", cfg.DifficultyTarget, layout)
+	}
+	// 混入待复习单词的经典、倒计时成绩单独排名（旧记录没有该字段，按不混入处理）
+	if cfg.ReviewMix > 0 && (mode == "classic" || mode == "countdown") {
+		ratios += fmt.Sprintf("/review=%d%%", cfg.ReviewMix)
+	}
+	// 使用词库包的成绩按词库包分别排名
+	if cfg.Pack != "" {
+		base := filepath.Base(cfg.Pack)
+		ratios += "/pack=" + strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	switch mode {
+	case "classic":
+		return Category{
+			Key:           fmt.Sprintf("classic/words=%d/%s", cfg.WordCount, ratios),
+			Label:         fmt.Sprintf("Classic · %d words · %s", cfg.WordCount, ratios),
+			Unit:          "s",
+			LowerIsBetter: true,
+		}
+	case "sentence":
+		return Category{Key: "sentence", Label: "Sentence", Unit: "l/s"}
+	case "countdown":
+		// 按得分排名；按单词数排名的旧榜单保留在不带 /score 的键下
+		return Category{
+			Key:   fmt.Sprintf("countdown/%ds/%s/score", cfg.CountdownDuration, ratios),
+			Label: fmt.Sprintf("Countdown · %ds · %s", cfg.CountdownDuration, ratios),
+			Unit:  "pts",
+		}
+	case "speedrun":
+		return Category{
+			Key:           fmt.Sprintf("speedrun/words=%d/%s", cfg.SpeedRunWordCount, ratios),
+			Label:         fmt.Sprintf("Speed Run · %d words · %s", cfg.SpeedRunWordCount, ratios),
+			Unit:          "s",
+			LowerIsBetter: true,
+		}
+	case "rhythm-master":
+		variant := fmt.Sprintf("limit=%g-%g/step=%g/per=%d",
+			cfg.RhythmInitialTimeLimit, cfg.RhythmMinTimeLimit, cfg.RhythmDifficultyStep, cfg.RhythmWordsPerLevel)
+		return Category{
+			Key:   "rhythm-master/" + variant,
+			Label: "Rhythm Master · " + variant,
+			Unit:  "words",
+		}
+	case "underwater":
+		return Category{
+			Key:   fmt.Sprintf("underwater/%ds", cfg.CountdownDuration),
+			Label: fmt.Sprintf("Underwater · %ds", cfg.CountdownDuration),
+			Unit:  "fish",
+		}
+	case "rhythm-dance":
+		variant := fmt.Sprintf("%ds/speed=%g+%g",
+			cfg.RhythmDanceDuration, cfg.RhythmDanceInitialSpeed, cfg.RhythmDanceSpeedIncrement)
+		// 跟随曲谱时速度由曲谱决定，按曲谱文件名排名
+		if cfg.RhythmDanceBeatmapPath != "" {
+			song := strings.TrimSuffix(filepath.Base(cfg.RhythmDanceBeatmapPath), filepath.Ext(cfg.RhythmDanceBeatmapPath))
+			variant = fmt.Sprintf("%ds/song=%s", cfg.RhythmDanceDuration, song)
+		}
+		// 非默认计分规则单独排名（旧记录没有该字段，按默认处理）
+		if cfg.RhythmDanceScoring != "" && cfg.RhythmDanceScoring != config.DefaultScoringPreset {
+			variant += "/rules=" + cfg.RhythmDanceScoring
+		}
+		// 覆盖后与计分规则不同的判定窗口也单独排名
+		preset := cfg
+		preset.RhythmDancePerfectWindow, preset.RhythmDanceNiceWindow, preset.RhythmDanceOKWindow = 0, 0, 0
+		rules, err1 := cfg.ScoringRules()
+		base, err2 := preset.ScoringRules()
+		if err1 == nil && err2 == nil && (rules.PerfectWindowMS != base.PerfectWindowMS ||
+			rules.NiceWindowMS != base.NiceWindowMS || rules.OKWindowMS != base.OKWindowMS) {
+			variant += fmt.Sprintf("/judge=%d-%d-%dms", rules.PerfectWindowMS, rules.NiceWindowMS, rules.OKWindowMS)
+		}
+		return Category{
+			Key:   "rhythm-dance/" + variant,
+			Label: "Rhythm Dance · " + variant,
+			Unit:  "pts",
+		}
+	default:
+		return Category{Key: mode, Label: mode}
+	}
+}
+
+// Classify 确定历史记录所属分类以及用于排名的成绩值
+// 中止的对局以及未完成目标的用时类对局不计入排行榜
+func Classify(rec history.Record) (Category, float64, bool) {
+	if rec.Aborted {
+		return Category{}, 0, false
+	}
+	cat := CategoryFor(rec.Mode, rec.Config)
+
+	switch rec.Mode {
+	case "classic":
+		if rec.Stats.WordsCompleted < rec.Config.WordCount {
+			return cat, 0, false
+		}
+		return cat, rec.Stats.ElapsedSeconds, true
+	case "sentence":
+		return cat, rec.Stats.LettersPerSecond, true
+	case "countdown":
+		// 旧记录没有得分，不参与排名
+		if rec.WordScore == nil {
+			return cat, 0, false
+		}
+		return cat, float64(rec.WordScore.Score), true
+	case "underwater", "rhythm-master":
+		return cat, float64(rec.Stats.WordsCompleted), true
+	case "speedrun":
+		if rec.Stats.WordsCompleted < rec.Config.SpeedRunWordCount {
+			return cat, 0, false
+		}
+		return cat, rec.Stats.ElapsedSeconds, true
+	case "rhythm-dance":
+		if rec.RhythmDance == nil {
+			return cat, 0, false
+		}
+		return cat, float64(rec.RhythmDance.TotalScore), true
+	default:
+		return cat, 0, false
+	}
+}
@@ -0,0 +1,161 @@
+package records
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/history"
+	"github.com/word-killer/word-killer/pkg/stats"
+)
+
+func speedRun(seconds float64, words int) history.Record {
+	return history.Record{
+		Mode:    "speedrun",
+		EndedAt: time.Now(),
+		Config:  *config.DefaultConfig(),
+		Stats:   stats.Snapshot{ElapsedSeconds: seconds, WordsCompleted: words},
+	}
+}
+
+func TestSubmitRanksByModeMetric(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.json")
+	store, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if res, ok := store.Submit(speedRun(40, 25)); !ok || res.Rank != 1 || !res.IsNewRecord {
+		t.Fatalf("first run: %+v, %v", res, ok)
+	}
+	if res, _ := store.Submit(speedRun(50, 25)); res.Rank != 2 || res.IsNewRecord {
+		t.Errorf("slower run should rank 2nd: %+v", res)
+	}
+	if res, _ := store.Submit(speedRun(30, 25)); res.Rank != 1 || !res.IsNewRecord || res.Previous.Value != 40 {
+		t.Errorf("faster run should be a new record: %+v", res)
+	}
+	if _, ok := store.Submit(speedRun(10, 5)); ok {
+		t.Error("unfinished speed run should not be ranked")
+	}
+
+	dance := history.Record{
+		Mode:        "rhythm-dance",
+		Config:      *config.DefaultConfig(),
+		RhythmDance: &history.RhythmDanceSummary{TotalScore: 12},
+	}
+	if res, _ := store.Submit(dance); res.Rank != 1 || res.Category.LowerIsBetter {
+		t.Errorf("rhythm dance should rank by score: %+v", res)
+	}
+
+	// 倒计时按得分排名，单词多但得分低的一局排在后面
+	countdown := func(words, score int) history.Record {
+		return history.Record{
+			Mode:      "countdown",
+			Config:    *config.DefaultConfig(),
+			Stats:     stats.Snapshot{WordsCompleted: words},
+			WordScore: &history.WordScoreSummary{Score: score},
+		}
+	}
+	store.Submit(countdown(20, 300))
+	if res, _ := store.Submit(countdown(25, 250)); res.Rank != 2 || res.Value != 250 {
+		t.Errorf("countdown should rank by score: %+v", res)
+	}
+	if _, ok := store.Submit(history.Record{Mode: "countdown", Config: *config.DefaultConfig()}); ok {
+		t.Error("countdown record without a score should not be ranked")
+	}
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	best, ok := reloaded.Best(CategoryFor("speedrun", *config.DefaultConfig()).Key)
+	if !ok || best.Value != 30 {
+		t.Errorf("Best = %+v, %v; want 30", best, ok)
+	}
+}
+
+func TestVariantsAreSeparateBoards(t *testing.T) {
+	cfg := *config.DefaultConfig()
+	other := cfg
+	other.CountdownDuration = 30
+
+	if CategoryFor("countdown", cfg).Key == CategoryFor("countdown", other).Key {
+		t.Error("countdown durations should use different boards")
+	}
+
+	// 计分规则不同分开排名；旧记录没有规则名称，与 standard 同榜
+	hardcore := cfg
+	hardcore.RhythmDanceScoring = "hardcore"
+	legacy := cfg
+	legacy.RhythmDanceScoring = ""
+	legacy.RhythmDancePerfectWindow, legacy.RhythmDanceNiceWindow, legacy.RhythmDanceOKWindow = 50, 120, 230
+	key := CategoryFor("rhythm-dance", cfg).Key
+	if CategoryFor("rhythm-dance", hardcore).Key == key {
+		t.Error("scoring presets should use different boards")
+	}
+	if got := CategoryFor("rhythm-dance", legacy).Key; got != key {
+		t.Errorf("legacy rhythm dance record: key %q, want %q", got, key)
+	}
+
+	// 混入复习单词的经典成绩单独排名，不影响极速模式
+	mixed := cfg
+	mixed.ReviewMix = 20
+	if CategoryFor("classic", mixed).Key == CategoryFor("classic", cfg).Key {
+		t.Error("classic games with review words should use a different board")
+	}
+	if CategoryFor("speedrun", mixed).Key != CategoryFor("speedrun", cfg).Key {
+		t.Error("review_mix should not split speed run boards")
+	}
+
+	// 按目标难度选词的成绩不受长度配比影响，按难度和键盘布局排名
+	target := cfg
+	target.DifficultyTarget = 40
+	other = target
+	other.ShortRatio = 90
+	if got := CategoryFor("classic", target).Key; got != CategoryFor("classic", other).Key || !strings.Contains(got, "difficulty=40/qwerty") {
+		t.Errorf("classic with difficulty target: key %q", got)
+	}
+	other.KeyboardLayout = "dvorak"
+	if CategoryFor("classic", other).Key == CategoryFor("classic", target).Key {
+		t.Error("keyboard layouts should use different boards")
+	}
+
+	// 不同词库包的成绩分别排名
+	pack := cfg
+	pack.Pack = "/home/me/.local/share/word-killer/packs/go-keywords.txt"
+	if got := CategoryFor("speedrun", pack).Key; got == CategoryFor("speedrun", cfg).Key || !strings.Contains(got, "/pack=go-keywords") {
+		t.Errorf("speed run with a pack: key %q", got)
+	}
+}
+
+func TestBoardKeepsTopN(t *testing.T) {
+	store, _ := Load(filepath.Join(t.TempDir(), "records.json"))
+	for i := 0; i < DefaultLimit+5; i++ {
+		store.Submit(speedRun(float64(100-i), 25))
+	}
+	board, _ := store.Board(CategoryFor("speedrun", *config.DefaultConfig()).Key)
+	if len(board.Entries) != DefaultLimit {
+		t.Fatalf("board has %d entries, want %d", len(board.Entries), DefaultLimit)
+	}
+	if res, _ := store.Submit(speedRun(500, 25)); res.Rank != 0 {
+		t.Errorf("slow run should not qualify, got rank %d", res.Rank)
+	}
+}
+
+func TestReplaysListsReferencedFiles(t *testing.T) {
+	store, _ := Load(filepath.Join(t.TempDir(), "records.json"))
+	fast := speedRun(30, 25)
+	fast.Replay = filepath.Join("replays", "fast.json")
+	store.Submit(fast)
+	store.Submit(speedRun(40, 25)) // 没有回放的成绩
+
+	got := store.Replays()
+	if len(got) != 1 || !got[fast.Replay] {
+		t.Errorf("Replays() = %v, want only %s", got, fast.Replay)
+	}
+}
@@ -0,0 +1,86 @@
+package replay
+
+import (
+	"time"
+
+	"github.com/word-killer/word-killer/pkg/game"
+)
+
+// Ghost 从回放中提取的对手进度，用于和历史最佳赛跑
+type Ghost struct {
+	seed        int64
+	words       []string     // 开局生成的单词（按顺序）
+	completions []completion // 按时间排序的消除记录
+	finishedAt  time.Duration
+}
+
+// completion 幽灵消除一个单词的时刻
+type completion struct {
+	At   time.Duration
+	Word string
+}
+
+// NewGhost 根据回放构建幽灵对手
+func NewGhost(r *Replay) *Ghost {
+	g := &Ghost{seed: r.Seed}
+	for _, e := range r.Events {
+		switch e.Type {
+		case game.EventWordSpawned:
+			if e.At == 0 {
+				g.words = append(g.words, e.Text)
+			}
+		case game.EventWordCompleted:
+			g.completions = append(g.completions, completion{At: e.At, Word: e.Text})
+		case game.EventFinished:
+			g.finishedAt = e.At
+		}
+	}
+	return g
+}
+
+// Seed 幽灵对局的随机种子（用它开局即可得到相同的单词序列）
+func (g *Ghost) Seed() int64 {
+	return g.seed
+}
+
+// SameWords 检查单词序列是否与幽灵对局一致（词库改动后不再可比）
+func (g *Ghost) SameWords(words []string) bool {
+	if len(words) != len(g.words) {
+		return false
+	}
+	for i := range words {
+		if words[i] != g.words[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// WordsAt 幽灵在游戏时钟 t 时已消除的单词数
+func (g *Ghost) WordsAt(t time.Duration) int {
+	n := 0
+	for _, c := range g.completions {
+		if c.At > t {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// CompletedAt 幽灵在游戏时钟 t 时已消除的单词
+func (g *Ghost) CompletedAt(t time.Duration) map[string]bool {
+	done := make(map[string]bool)
+	for _, c := range g.completions {
+		if c.At > t {
+			break
+		}
+		done[c.Word] = true
+	}
+	return done
+}
+
+// FinishTime 幽灵完成对局的时间
+func (g *Ghost) FinishTime() time.Duration {
+	return g.finishedAt
+}
@@ -0,0 +1,105 @@
+package replay
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/word-killer/word-killer/pkg/game"
+)
+
+// Player 按原始时间把回放事件重新送入一局新游戏
+type Player struct {
+	Game *game.Game
+
+	replay *Replay
+	clock  *game.ManualClock
+	start  time.Time
+	next   int // 下一个待处理事件的索引
+}
+
+// NewPlayer 创建回放播放器
+// setup 负责加载词库并按回放的模式和配置启动游戏，词库必须与录制时一致
+func NewPlayer(r *Replay, start time.Time, setup func(g *game.Game) error) (*Player, error) {
+	if _, err := game.ParseMode(r.Mode); err != nil {
+		return nil, err
+	}
+
+	clock := game.NewManualClock(start)
+	g := game.NewWithClock(clock, r.Seed)
+	if err := setup(g); err != nil {
+		return nil, fmt.Errorf("failed to start replay: %w", err)
+	}
+
+	return &Player{
+		Game:   g,
+		replay: r,
+		clock:  clock,
+		start:  start,
+	}, nil
+}
+
+// Replay 获取正在播放的回放
+func (p *Player) Replay() *Replay {
+	return p.replay
+}
+
+// AdvanceTo 播放到回放开始后的 elapsed 时刻，处理期间的所有事件
+func (p *Player) AdvanceTo(elapsed time.Duration) {
+	for p.next < len(p.replay.Events) {
+		e := p.replay.Events[p.next]
+		if e.At > elapsed {
+			break
+		}
+		p.clock.Set(p.start.Add(e.At))
+		p.apply(e)
+		p.next++
+	}
+	p.clock.Set(p.start.Add(elapsed))
+}
+
+// RunToEnd 立即播放完所有事件
+func (p *Player) RunToEnd() {
+	p.AdvanceTo(p.replay.Duration())
+}
+
+// Done 是否已处理完所有事件
+func (p *Player) Done() bool {
+	return p.next >= len(p.replay.Events)
+}
+
+// Matches 回放结果是否与原始对局一致（词库改动会导致不一致）
+// 用时和原始对局一样来自游戏时钟，因此也必须完全相同
+func (p *Player) Matches() bool {
+	got, want := p.Game.Stats.Snapshot(), p.replay.Result
+	return got.ElapsedSeconds == want.ElapsedSeconds &&
+		got.TotalKeystrokes == want.TotalKeystrokes &&
+		got.ValidKeystrokes == want.ValidKeystrokes &&
+		got.CorrectChars == want.CorrectChars &&
+		got.WordsCompleted == want.WordsCompleted &&
+		got.TotalLetters == want.TotalLetters
+}
+
+// apply 把单个事件送入游戏
+func (p *Player) apply(e game.Event) {
+	g := p.Game
+	switch e.Type {
+	case game.EventChar:
+		for _, ch := range e.Text {
+			g.AddChar(ch)
+		}
+	case game.EventBackspace:
+		g.Backspace()
+	case game.EventEliminate:
+		g.TryEliminate()
+	case game.EventJudge:
+		g.TryRhythmJudgment()
+	case game.EventTick:
+		g.Tick()
+	case game.EventFinished:
+		// 超时结束由游戏时钟自然触发，中止则直接结束
+		g.CheckTimeouts()
+		if e.Aborted {
+			g.Abort()
+		}
+	}
+}
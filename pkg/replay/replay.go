@@ -0,0 +1,149 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/game"
+	"github.com/word-killer/word-killer/pkg/stats"
+)
+
+// 回放文件格式标识与版本
+const (
+	FileFormat     = "word-killer-replay"
+	CurrentVersion = 1
+)
+
+// Replay 一局游戏的完整回放：种子 + 配置 + 事件日志
+// 用相同种子和词库重新送入输入事件即可复现整局游戏
+type Replay struct {
+	Format     string         `json:"format"`
+	Version    int            `json:"version"`
+	Mode       string         `json:"mode"`
+	Seed       int64          `json:"seed"`
+	RecordedAt time.Time      `json:"recorded_at"`
+	Config     config.Config  `json:"config"`
+	Result     stats.Snapshot `json:"result"` // 原始对局的最终统计（用于核对回放）
+	Events     []game.Event   `json:"events"`
+
+	// 自适应练习开局时的历史按键统计（选词依赖它，回放时需要原样恢复）
+	PracticeProfile *stats.KeyStats `json:"practice_profile,omitempty"`
+
+	// 开局时的待复习单词（复习模式和混入复习单词的经典、倒计时模式依赖它选词）
+	ReviewWords []string `json:"review_words,omitempty"`
+}
+
+// FromGame 根据已结束的游戏构建回放
+func FromGame(g *game.Game, cfg config.Config) *Replay {
+	r := &Replay{
+		Format:     FileFormat,
+		Version:    CurrentVersion,
+		Mode:       g.Mode.String(),
+		Seed:       g.Seed(),
+		RecordedAt: g.Stats.EndTime,
+		Config:     cfg,
+		Result:     g.Stats.Snapshot(),
+		Events:     append([]game.Event(nil), g.Events()...),
+	}
+	if g.Mode == game.ModePractice && !g.PracticeProfile.Empty() {
+		profile := g.PracticeProfile.Clone()
+		r.PracticeProfile = &profile
+	}
+	switch g.Mode {
+	case game.ModeReview, game.ModeClassic, game.ModeCountdown:
+		r.ReviewWords = append([]string(nil), g.ReviewWords...)
+	}
+	return r
+}
+
+// Duration 回放总时长（最后一个事件的时间）
+func (r *Replay) Duration() time.Duration {
+	if len(r.Events) == 0 {
+		return 0
+	}
+	return r.Events[len(r.Events)-1].At
+}
+
+// Save 保存回放到文件
+func (r *Replay) Save(path string) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create replay directory: %w", err)
+		}
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode replay: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write replay: %w", err)
+	}
+	return nil
+}
+
+// Load 从文件读取回放
+func Load(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay: %w", err)
+	}
+
+	var r Replay
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse replay: %w", err)
+	}
+	if r.Format != FileFormat {
+		return nil, fmt.Errorf("not a replay file: %s", path)
+	}
+	if r.Version > CurrentVersion {
+		return nil, fmt.Errorf("unsupported replay version %d", r.Version)
+	}
+	return &r, nil
+}
+
+// Prune 清理回放目录：只保留最新的 keep 个回放，更早的回放会被删除
+// 文件名按时间排序（见 FileName），protected 中的路径（如排行榜引用的回放）始终保留
+func Prune(dir string, keep int, protected map[string]bool) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read replay directory: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) <= keep {
+		return nil
+	}
+	sort.Strings(names)
+
+	for _, name := range names[:len(names)-keep] {
+		path := filepath.Join(dir, name)
+		if protected[path] {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove replay: %w", err)
+		}
+	}
+	return nil
+}
+
+// FileName 生成回放文件名，如 20260101-120000.123-speedrun.json（精确到毫秒，同一秒结束的对局不会互相覆盖）
+func FileName(r *Replay) string {
+	return fmt.Sprintf("%s-%s.json", r.RecordedAt.Format("20060102-150405.000"), r.Mode)
+}
@@ -0,0 +1,324 @@
+package replay
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/word-killer/word-killer/pkg/config"
+	"github.com/word-killer/word-killer/pkg/game"
+	"github.com/word-killer/word-killer/pkg/stats"
+)
+
+var words = []string{
+	"apple", "brave", "cloud", "dance", "eagle", "flame", "grape", "house",
+	"input", "joker", "knife", "lemon", "mango", "night", "ocean", "piano",
+	"queen", "river", "stone", "tiger", "unity", "voice", "whale", "zebra",
+}
+
+// writeDict 写入测试词库，返回文件路径
+func writeDict(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(words, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func setup(dict string, start func(g *game.Game) error) func(g *game.Game) error {
+	return func(g *game.Game) error {
+		if err := g.LoadWordDictionaries(dict, "", "", 1, 0, 0); err != nil {
+			return err
+		}
+		return start(g)
+	}
+}
+
+func TestReplayReproducesSpeedRun(t *testing.T) {
+	dict := writeDict(t)
+	start := setup(dict, func(g *game.Game) error { return g.StartSpeedRunMode(5) })
+
+	clock := game.NewManualClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	g := game.NewWithClock(clock, 99)
+	// 第二局使用派生种子，回放只依赖本局种子
+	if err := start(g); err != nil {
+		t.Fatal(err)
+	}
+	g.Abort()
+	if err := start(g); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; g.Status == game.StatusRunning; i++ {
+		target := g.GetActiveWords()[0]
+		g.AddChar('x')
+		clock.Advance(150 * time.Millisecond)
+		g.Backspace()
+		for _, ch := range target {
+			clock.Advance(120 * time.Millisecond)
+			g.AddChar(ch)
+		}
+		if i == 2 {
+			g.Pause()
+			clock.Advance(time.Minute)
+			g.Resume()
+		}
+		g.TryEliminate()
+	}
+
+	path := filepath.Join(t.TempDir(), "replays", "run.json")
+	if err := FromGame(g, *config.DefaultConfig()).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewPlayer(r, time.Now(), start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.AdvanceTo(r.Duration() / 2)
+	if p.Game.Status != game.StatusRunning {
+		t.Fatal("replay finished too early")
+	}
+	p.RunToEnd()
+
+	if !p.Done() || p.Game.Status != game.StatusFinished {
+		t.Fatalf("replay did not finish: done=%v status=%v", p.Done(), p.Game.Status)
+	}
+	if !p.Matches() {
+		t.Errorf("replay result %+v differs from recorded %+v", p.Game.Stats.Snapshot(), r.Result)
+	}
+	if len(p.Game.Events()) != len(r.Events) {
+		t.Errorf("replay emitted %d events, recorded %d", len(p.Game.Events()), len(r.Events))
+	}
+}
+
+func TestReplayReproducesUnderwaterSpawns(t *testing.T) {
+	dict := writeDict(t)
+	start := setup(dict, func(g *game.Game) error { return g.StartUnderwaterCountdown(5) })
+
+	clock := game.NewManualClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	g := game.NewWithClock(clock, 7)
+	if err := start(g); err != nil {
+		t.Fatal(err)
+	}
+	for g.Status == game.StatusRunning {
+		clock.Advance(100 * time.Millisecond)
+		g.Tick()
+		if fishes := g.UnderwaterState.Fishes; len(fishes) > 0 && !fishes[0].Completed {
+			for _, ch := range fishes[0].Word {
+				g.AddChar(ch)
+			}
+			g.TryEliminate()
+		}
+	}
+
+	r := FromGame(g, *config.DefaultConfig())
+	p, err := NewPlayer(r, time.Now(), start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.RunToEnd()
+
+	if !p.Matches() {
+		t.Errorf("replay result %+v differs from recorded %+v", p.Game.Stats.Snapshot(), r.Result)
+	}
+	got, want := spawned(p.Game.Events()), spawned(r.Events)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("spawned fish differ:\n%v\n%v", got, want)
+	}
+}
+
+func TestReplayRestoresPracticeProfile(t *testing.T) {
+	dict := writeDict(t)
+	start := setup(dict, func(g *game.Game) error { return g.StartPracticeMode(15) })
+
+	clock := game.NewManualClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	g := game.NewWithClock(clock, 5)
+	g.PracticeProfile = stats.KeyStats{Keys: map[string]stats.KeyStat{
+		"q": {Attempts: 10, Errors: 5},
+		"z": {Attempts: 10, Errors: 4},
+		"e": {Attempts: 10},
+		"a": {Attempts: 10},
+	}}
+	if err := start(g); err != nil {
+		t.Fatal(err)
+	}
+	for g.Status == game.StatusRunning {
+		target := g.GetActiveWords()[0]
+		for i, ch := range target {
+			clock.Advance(time.Duration(100+20*i) * time.Millisecond)
+			g.AddChar(ch)
+		}
+		g.TryEliminate()
+	}
+
+	path := filepath.Join(t.TempDir(), "practice.json")
+	if err := FromGame(g, *config.DefaultConfig()).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.PracticeProfile == nil {
+		t.Fatal("practice profile not saved")
+	}
+
+	// 回放的游戏没有历史记录，选词只能依赖回放文件中的统计
+	p, err := NewPlayer(r, time.Now(), func(g *game.Game) error {
+		g.PracticeProfile = *r.PracticeProfile
+		return start(g)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.RunToEnd()
+
+	if !p.Matches() {
+		t.Errorf("replay result %+v differs from recorded %+v", p.Game.Stats.Snapshot(), r.Result)
+	}
+	got, want := spawned(p.Game.Events()), spawned(r.Events)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("practice words differ:\n%v\n%v", got, want)
+	}
+}
+
+func TestReplayRestoresReviewWords(t *testing.T) {
+	clock := game.NewManualClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	g := game.NewWithClock(clock, 9)
+	g.ReviewWords = []string{"rhythm", "queue", "fjord"}
+	if err := g.StartReviewMode(0); err != nil {
+		t.Fatal(err)
+	}
+	for g.Status == game.StatusRunning {
+		for _, ch := range g.GetActiveWords()[0] {
+			clock.Advance(150 * time.Millisecond)
+			g.AddChar(ch)
+		}
+		g.TryEliminate()
+	}
+
+	r := FromGame(g, *config.DefaultConfig())
+	if len(r.ReviewWords) != 3 {
+		t.Fatalf("review words not saved: %v", r.ReviewWords)
+	}
+	p, err := NewPlayer(r, time.Now(), func(g *game.Game) error {
+		g.ReviewWords = r.ReviewWords
+		return g.StartReviewMode(0)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.RunToEnd()
+	if !p.Matches() {
+		t.Errorf("replay result %+v differs from recorded %+v", p.Game.Stats.Snapshot(), r.Result)
+	}
+}
+
+func TestFileNameIsUniqueWithinASecond(t *testing.T) {
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	a := &Replay{Mode: "speedrun", RecordedAt: at.Add(100 * time.Millisecond)}
+	b := &Replay{Mode: "speedrun", RecordedAt: at.Add(900 * time.Millisecond)}
+	if FileName(a) == FileName(b) {
+		t.Errorf("runs in the same second share file name %s", FileName(a))
+	}
+	if got := FileName(a); got != "20260101-120000.100-speedrun.json" {
+		t.Errorf("FileName = %q", got)
+	}
+}
+
+func TestLoadRejectsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.json")
+	os.WriteFile(path, []byte(`{"version":1,"boards":{}}`), 0o644)
+	if _, err := Load(path); err == nil {
+		t.Error("expected error for non-replay file")
+	}
+}
+
+func TestPruneKeepsNewestAndProtected(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var paths []string
+	for i := 0; i < 5; i++ {
+		r := &Replay{Mode: "speedrun", RecordedAt: at.Add(time.Duration(i) * time.Minute)}
+		path := filepath.Join(dir, FileName(r))
+		if err := r.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	if err := Prune(dir, 2, map[string]bool{paths[0]: true}); err != nil {
+		t.Fatal(err)
+	}
+	for i, path := range paths {
+		_, err := os.Stat(path)
+		kept := err == nil
+		if want := i == 0 || i >= 3; kept != want {
+			t.Errorf("replay %d kept = %v, want %v", i, kept, want)
+		}
+	}
+
+	if err := Prune(filepath.Join(dir, "missing"), 2, nil); err != nil {
+		t.Errorf("missing directory should not be an error: %v", err)
+	}
+}
+
+func spawned(events []game.Event) []string {
+	var out []string
+	for _, e := range events {
+		if e.Type == game.EventWordSpawned {
+			out = append(out, e.Text)
+		}
+	}
+	return out
+}
+
+func TestGhostFollowsRecordedRun(t *testing.T) {
+	dict := writeDict(t)
+	start := setup(dict, func(g *game.Game) error { return g.StartSpeedRunMode(3) })
+
+	clock := game.NewManualClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	g := game.NewWithClock(clock, 5)
+	if err := start(g); err != nil {
+		t.Fatal(err)
+	}
+	for g.Status == game.StatusRunning {
+		clock.Advance(time.Second)
+		for _, ch := range g.GetActiveWords()[0] {
+			g.AddChar(ch)
+		}
+		g.TryEliminate()
+	}
+	ghost := NewGhost(FromGame(g, *config.DefaultConfig()))
+
+	if n := ghost.WordsAt(1500 * time.Millisecond); n != 1 {
+		t.Errorf("WordsAt(1.5s) = %d, want 1", n)
+	}
+	if len(ghost.CompletedAt(2*time.Second)) != 2 {
+		t.Errorf("CompletedAt(2s) = %v, want 2 words", ghost.CompletedAt(2*time.Second))
+	}
+	if ghost.FinishTime() != 3*time.Second {
+		t.Errorf("FinishTime = %v, want 3s", ghost.FinishTime())
+	}
+
+	// 新的对局使用幽灵的种子，得到相同的单词
+	rival := game.NewWithClock(clock, 1234)
+	rival.UseSeed(ghost.Seed())
+	if err := start(rival); err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, w := range rival.Words {
+		texts = append(texts, w.Text)
+	}
+	if !ghost.SameWords(texts) {
+		t.Errorf("rival words %v differ from ghost's", texts)
+	}
+}
@@ -0,0 +1,216 @@
+// Package review 经常打错、卡壳的单词的间隔复习队列（SM-2）
+package review
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// 复习文件版本
+const CurrentVersion = 1
+
+const (
+	// Hesitation 单词中间停顿达到这个时长视为卡壳
+	Hesitation = time.Second
+
+	// enrollBelow 评分低于它的单词加入复习队列
+	enrollBelow = 4
+
+	// passQuality SM-2 中评分达到它算作记住了（间隔继续增长）
+	passQuality = 3
+
+	// 难度系数的初始值和下限（SM-2 的 EF）
+	initialEase = 2.5
+	minEase     = 1.3
+)
+
+// Quality 按一个单词的输入过程评分（SM-2 的 0-5 分）
+// 每次退格扣 1 分，中间卡壳扣 2 分：一次就打对且没有停顿为 5 分
+func Quality(corrections int, pause time.Duration) int {
+	q := 5 - corrections
+	if pause >= Hesitation {
+		q -= 2
+	}
+	return max(q, 0)
+}
+
+// Card 一个复习单词的 SM-2 排程
+type Card struct {
+	Word     string    `json:"word"`
+	Ease     float64   `json:"ease"`     // 难度系数，越小间隔增长越慢
+	Interval int       `json:"interval"` // 当前复习间隔（天）
+	Reps     int       `json:"reps"`     // 连续记住的次数（评分低于 3 时归零）
+	Lapses   int       `json:"lapses"`   // 加入队列后又打错的次数
+	Due      time.Time `json:"due"`      // 下次复习时间
+	Reviewed time.Time `json:"reviewed"` // 最近一次评分时间
+}
+
+// review 按 SM-2 更新排程：记住时间隔依次为 1 天、6 天、之后乘以难度系数；没记住时从 1 天重新开始
+func (c *Card) review(quality int, now time.Time) {
+	if quality < passQuality {
+		c.Reps = 0
+		c.Interval = 1
+	} else {
+		switch c.Reps {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.Ease))
+		}
+		c.Reps++
+	}
+
+	miss := float64(5 - quality)
+	c.Ease = max(minEase, c.Ease+0.1-miss*(0.08+miss*0.02))
+	c.Due = now.AddDate(0, 0, c.Interval)
+	c.Reviewed = now
+}
+
+// deck 复习文件的内容
+type deck struct {
+	Version int              `json:"version"`
+	Cards   map[string]*Card `json:"cards"`
+}
+
+// Store 复习队列存储
+type Store struct {
+	path string
+	data deck
+}
+
+// Load 从文件加载复习队列（文件不存在时返回空队列）
+func Load(path string) (*Store, error) {
+	s := &Store{
+		path: path,
+		data: deck{Version: CurrentVersion, Cards: make(map[string]*Card)},
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open review file: %w", err)
+	}
+	defer file.Close()
+
+	var data deck
+	if err := json.NewDecoder(file).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse review file: %w", err)
+	}
+	if data.Version > CurrentVersion {
+		return nil, fmt.Errorf("review file version %d is newer than supported version %d", data.Version, CurrentVersion)
+	}
+	if data.Cards == nil {
+		data.Cards = make(map[string]*Card)
+	}
+	data.Version = CurrentVersion
+	s.data = data
+
+	return s, nil
+}
+
+// Save 保存复习队列到文件
+func (s *Store) Save() error {
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create review directory: %w", err)
+		}
+	}
+
+	file, err := os.Create(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to create review file: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(&s.data); err != nil {
+		return fmt.Errorf("failed to write review file: %w", err)
+	}
+
+	return nil
+}
+
+// Len 队列中的单词数
+func (s *Store) Len() int {
+	return len(s.data.Cards)
+}
+
+// Card 获取单词的排程
+func (s *Store) Card(word string) (Card, bool) {
+	c, ok := s.data.Cards[word]
+	if !ok {
+		return Card{}, false
+	}
+	return *c, true
+}
+
+// Record 记录一次单词输入的评分，返回队列是否改变
+// 不在队列中的单词评分低于 4 时加入队列；已在队列中的单词按 SM-2 更新，
+// 但还没到期的单词打得好不提前延长间隔（打错仍然重新开始）
+func (s *Store) Record(word string, quality int, now time.Time) bool {
+	c, ok := s.data.Cards[word]
+	if !ok {
+		if quality >= enrollBelow {
+			return false
+		}
+		c = &Card{Word: word, Ease: initialEase}
+		s.data.Cards[word] = c
+	} else {
+		if quality >= passQuality && now.Before(c.Due) {
+			return false
+		}
+		if quality < passQuality {
+			c.Lapses++
+		}
+	}
+	c.review(quality, now)
+	return true
+}
+
+// Due 已到期的单词，最早到期的在前
+func (s *Store) Due(now time.Time) []string {
+	cards := s.sorted()
+	var words []string
+	for _, c := range cards {
+		if c.Due.After(now) {
+			break
+		}
+		words = append(words, c.Word)
+	}
+	return words
+}
+
+// NextDue 最早的复习时间（队列为空时 ok 为 false）
+func (s *Store) NextDue() (time.Time, bool) {
+	cards := s.sorted()
+	if len(cards) == 0 {
+		return time.Time{}, false
+	}
+	return cards[0].Due, true
+}
+
+// sorted 按到期时间排序的全部单词（同时到期的按字母顺序）
+func (s *Store) sorted() []*Card {
+	cards := make([]*Card, 0, len(s.data.Cards))
+	for _, c := range s.data.Cards {
+		cards = append(cards, c)
+	}
+	sort.Slice(cards, func(i, j int) bool {
+		if !cards[i].Due.Equal(cards[j].Due) {
+			return cards[i].Due.Before(cards[j].Due)
+		}
+		return cards[i].Word < cards[j].Word
+	})
+	return cards
+}
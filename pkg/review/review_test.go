@@ -0,0 +1,85 @@
+package review
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQuality(t *testing.T) {
+	tests := []struct {
+		corrections int
+		pause       time.Duration
+		want        int
+	}{
+		{0, 200 * time.Millisecond, 5},
+		{1, 0, 4},
+		{0, 1500 * time.Millisecond, 3},
+		{2, 0, 3},
+		{3, 2 * time.Second, 0},
+	}
+	for _, tt := range tests {
+		if got := Quality(tt.corrections, tt.pause); got != tt.want {
+			t.Errorf("Quality(%d, %v) = %d, want %d", tt.corrections, tt.pause, got, tt.want)
+		}
+	}
+}
+
+func TestRecordSchedulesSM2(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "review.json")
+	store, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	// 打得好的单词不加入队列
+	if store.Record("clean", 5, now) || store.Len() != 0 {
+		t.Fatal("a clean word should not be enrolled")
+	}
+	if !store.Record("rhythm", 2, now) || !store.Record("queue", 3, now) {
+		t.Fatal("fumbled words should be enrolled")
+	}
+	if due := store.Due(now); len(due) != 0 {
+		t.Errorf("new cards should not be due until tomorrow: %v", due)
+	}
+
+	// 第二天到期；提前打好不延长间隔
+	if store.Record("queue", 5, now.Add(time.Hour)) {
+		t.Error("an early success should not reschedule")
+	}
+	day1 := now.AddDate(0, 0, 1)
+	if due := store.Due(day1); len(due) != 2 || due[0] != "queue" || due[1] != "rhythm" {
+		t.Fatalf("Due = %v, want [queue rhythm]", due)
+	}
+
+	// 记住：1 天 → 6 天 → 6 × 难度系数
+	store.Record("queue", 5, day1)
+	day7 := day1.AddDate(0, 0, 6)
+	if c, _ := store.Card("queue"); c.Interval != 6 || !c.Due.Equal(day7) || c.Reps != 2 {
+		t.Fatalf("second review: %+v", c)
+	}
+	store.Record("queue", 4, day7)
+	if c, _ := store.Card("queue"); c.Interval != 15 || math.Abs(c.Ease-2.46) > 1e-9 {
+		t.Errorf("third review: %+v", c)
+	}
+
+	// 又打错：从 1 天重新开始，难度系数降低（2.5 → 2.18 → 1.38）
+	store.Record("rhythm", 0, day1)
+	c, _ := store.Card("rhythm")
+	if c.Reps != 0 || c.Interval != 1 || c.Lapses != 1 || math.Abs(c.Ease-1.38) > 1e-9 {
+		t.Errorf("lapse: %+v", c)
+	}
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if next, ok := loaded.NextDue(); !ok || !next.Equal(day1.AddDate(0, 0, 1)) {
+		t.Errorf("NextDue after reload = %v, %v", next, ok)
+	}
+}
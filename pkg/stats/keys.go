@@ -0,0 +1,214 @@
+package stats
+
+import (
+	"sort"
+	"time"
+	"unicode"
+)
+
+const (
+	// errorCost 计算弱项时一次按错折合的耗时（发现、退格、重打）
+	errorCost = time.Second
+
+	// 进入弱项排行所需的最少次数，避免偶然一两次失误排在前面
+	minKeyAttempts    = 5
+	minBigramAttempts = 3
+)
+
+// KeyStat 一个按键（或二连键）的统计，按"应该按的键"计数
+type KeyStat struct {
+	Attempts  int   `json:"attempts"`   // 应该按这个键的次数
+	Errors    int   `json:"errors"`     // 其中按错的次数
+	Timed     int   `json:"timed"`      // 计入击键间隔的次数（单词中第一个键不计）
+	LatencyMS int64 `json:"latency_ms"` // 累计击键间隔（毫秒）
+}
+
+// ErrorRate 出错率（0-1）
+func (k KeyStat) ErrorRate() float64 {
+	if k.Attempts == 0 {
+		return 0
+	}
+	return float64(k.Errors) / float64(k.Attempts)
+}
+
+// AvgLatency 平均击键间隔（没有计时数据时为 0）
+func (k KeyStat) AvgLatency() time.Duration {
+	if k.Timed == 0 {
+		return 0
+	}
+	return time.Duration(k.LatencyMS/int64(k.Timed)) * time.Millisecond
+}
+
+// Weakness 弱项程度：平均击键间隔加上按出错率折算的耗时（越大越弱）
+func (k KeyStat) Weakness() time.Duration {
+	return k.AvgLatency() + time.Duration(k.ErrorRate()*float64(errorCost))
+}
+
+// add 记录一次击键，timed 为 false 时不计入击键间隔
+func (k *KeyStat) add(hit bool, latency time.Duration, timed bool) {
+	k.Attempts++
+	if !hit {
+		k.Errors++
+	}
+	if timed {
+		k.Timed++
+		k.LatencyMS += latency.Milliseconds()
+	}
+}
+
+// merge 累加另一份统计
+func (k *KeyStat) merge(o KeyStat) {
+	k.Attempts += o.Attempts
+	k.Errors += o.Errors
+	k.Timed += o.Timed
+	k.LatencyMS += o.LatencyMS
+}
+
+// KeyStats 按键统计：每个键、每个二连键的出错和击键间隔，以及常见的错键
+// 键名统一为小写（同一个物理键）
+type KeyStats struct {
+	Keys          map[string]KeyStat `json:"keys,omitempty"`
+	Bigrams       map[string]KeyStat `json:"bigrams,omitempty"`       // 前一个键 + 当前键，如 "th"
+	Substitutions map[string]int     `json:"substitutions,omitempty"` // 应该按的键 + 实际按的键，如 "er" 表示把 e 按成了 r
+}
+
+// Empty 是否还没有任何数据
+func (k KeyStats) Empty() bool {
+	return len(k.Keys) == 0
+}
+
+// Merge 累加另一份统计（用于汇总多局）
+func (k *KeyStats) Merge(o KeyStats) {
+	for key, s := range o.Keys {
+		update(&k.Keys, key, func(k *KeyStat) { k.merge(s) })
+	}
+	for key, s := range o.Bigrams {
+		update(&k.Bigrams, key, func(k *KeyStat) { k.merge(s) })
+	}
+	for key, n := range o.Substitutions {
+		if k.Substitutions == nil {
+			k.Substitutions = make(map[string]int)
+		}
+		k.Substitutions[key] += n
+	}
+}
+
+// Clone 返回一份独立的副本
+func (k KeyStats) Clone() KeyStats {
+	var c KeyStats
+	c.Merge(k)
+	return c
+}
+
+// update 修改 m 中的一项（map 中保存的是值），m 为 nil 时创建
+func update(m *map[string]KeyStat, key string, fn func(*KeyStat)) {
+	if *m == nil {
+		*m = make(map[string]KeyStat)
+	}
+	s := (*m)[key]
+	fn(&s)
+	(*m)[key] = s
+}
+
+// WeakSpot 弱项排行中的一项
+type WeakSpot struct {
+	Keys string // 按键或二连键
+	KeyStat
+}
+
+// WeakestKeys 最弱的 n 个按键（按平均击键间隔加出错折算的耗时，从慢到快）
+func (k KeyStats) WeakestKeys(n int) []WeakSpot {
+	return weakest(k.Keys, n, minKeyAttempts)
+}
+
+// WeakestBigrams 最弱的 n 个二连键
+func (k KeyStats) WeakestBigrams(n int) []WeakSpot {
+	return weakest(k.Bigrams, n, minBigramAttempts)
+}
+
+// weakest 按弱项程度排序，次数不足 minAttempts 的不参与排行
+func weakest(m map[string]KeyStat, n, minAttempts int) []WeakSpot {
+	spots := make([]WeakSpot, 0, len(m))
+	for key, s := range m {
+		if s.Attempts >= minAttempts {
+			spots = append(spots, WeakSpot{Keys: key, KeyStat: s})
+		}
+	}
+	sort.Slice(spots, func(i, j int) bool {
+		wi, wj := spots[i].Weakness(), spots[j].Weakness()
+		if wi != wj {
+			return wi > wj
+		}
+		return spots[i].Keys < spots[j].Keys
+	})
+	if len(spots) > n {
+		spots = spots[:n]
+	}
+	return spots
+}
+
+// Substitution 一种错键：应该按 Expected 却按了 Typed
+type Substitution struct {
+	Expected rune
+	Typed    rune
+	Count    int
+}
+
+// TopSubstitutions 出现次数最多的 n 种错键
+func (k KeyStats) TopSubstitutions(n int) []Substitution {
+	subs := make([]Substitution, 0, len(k.Substitutions))
+	for key, count := range k.Substitutions {
+		r := []rune(key)
+		if len(r) != 2 {
+			continue
+		}
+		subs = append(subs, Substitution{Expected: r[0], Typed: r[1], Count: count})
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].Count != subs[j].Count {
+			return subs[i].Count > subs[j].Count
+		}
+		if subs[i].Expected != subs[j].Expected {
+			return subs[i].Expected < subs[j].Expected
+		}
+		return subs[i].Typed < subs[j].Typed
+	})
+	if len(subs) > n {
+		subs = subs[:n]
+	}
+	return subs
+}
+
+// AddKey 记录一次字符输入：expected 是目标在这个位置的字符，typed 是实际按的键
+// expected 为 0 表示不知道目标（输入已经偏离所有目标），此时不计入按键统计
+// 击键间隔只统计同一段输入中连续按对之后的按键，单词的第一个键包含反应时间，不计时
+func (s *Statistics) AddKey(expected, typed rune) {
+	if expected == 0 {
+		s.lastKey = 0
+		return
+	}
+
+	now := s.GetElapsedSeconds()
+	timed := s.lastKey != 0
+	latency := time.Duration((now - s.lastKeyAt) * float64(time.Second))
+	hit := typed == expected
+
+	key := string(unicode.ToLower(expected))
+	update(&s.Keys.Keys, key, func(k *KeyStat) { k.add(hit, latency, timed) })
+	if timed {
+		update(&s.Keys.Bigrams, string(s.lastKey)+key, func(k *KeyStat) { k.add(hit, latency, true) })
+	}
+	if wrong := unicode.ToLower(typed); !hit && string(wrong) != key {
+		if s.Keys.Substitutions == nil {
+			s.Keys.Substitutions = make(map[string]int)
+		}
+		s.Keys.Substitutions[key+string(wrong)]++
+	}
+
+	if hit {
+		s.lastKey = unicode.ToLower(expected)
+		s.lastKeyAt = now
+	} else {
+		s.lastKey = 0
+	}
+}
@@ -1,9 +1,13 @@
 package stats
 
 import (
+	"math"
 	"time"
 )
 
+// charsPerWord 计算 WPM 时一个"单词"的字符数（打字测速的通用标准）
+const charsPerWord = 5
+
 // Statistics 游戏统计数据
 type Statistics struct {
 	// 计数器
@@ -13,12 +17,25 @@ type Statistics struct {
 	WordsCompleted  int // 完成单词数
 	TotalLetters    int // 总字母数
 
+	// 打字测速计数（退格不算输入的字符）
+	TypedChars      int // 输入的字符数（含确认单词的回车）
+	IncorrectChars  int // 输入时与目标不一致的字符数
+	CorrectedErrors int // 被退格删掉的错误字符数
+
+	pending   []bool // 当前输入中每个字符是否正确，退格时据此判断是否修正了错误
+	perSecond []int  // 每秒输入的字符数（按有效耗时分桶），用于计算稳定度
+
+	// 按键统计（见 AddKey）
+	Keys      KeyStats
+	lastKey   rune    // 上一个按对的键，0 表示下一个键不计时
+	lastKeyAt float64 // 上一个按对的键的有效耗时（秒）
+
 	// 时间跟踪
-	StartTime           time.Time     // 开始时间
-	EndTime             time.Time     // 结束时间
-	PauseStartTime      time.Time     // 暂停开始时间
-	TotalPausedDuration time.Duration // 累计暂停时长
-	isPaused            bool          // 是否正在暂停
+	StartTime time.Time // 开始时间
+	EndTime   time.Time // 结束时间
+
+	nowFunc     func() time.Time     // 时间来源，为 nil 时使用系统时间
+	elapsedFunc func() time.Duration // 有效耗时来源（游戏的 PlayClock，不含暂停），为 nil 时按开始时间计算
 }
 
 // New 创建统计对象
@@ -26,35 +43,28 @@ func New() *Statistics {
 	return &Statistics{}
 }
 
-// Start 开始计时
-func (s *Statistics) Start() {
-	s.StartTime = time.Now()
+// NewWithClock 创建使用指定时间来源的统计对象
+// elapsed 提供有效耗时，游戏中为 PlayClock，暂停由它统一计量；为 nil 时有效耗时为开始至今（或至结束）的时间
+func NewWithClock(now func() time.Time, elapsed func() time.Duration) *Statistics {
+	return &Statistics{nowFunc: now, elapsedFunc: elapsed}
 }
 
-// Pause 暂停计时
-func (s *Statistics) Pause() {
-	if !s.isPaused {
-		s.PauseStartTime = time.Now()
-		s.isPaused = true
+// now 获取当前时间
+func (s *Statistics) now() time.Time {
+	if s.nowFunc != nil {
+		return s.nowFunc()
 	}
+	return time.Now()
 }
 
-// Resume 恢复计时
-func (s *Statistics) Resume() {
-	if s.isPaused {
-		pauseDuration := time.Since(s.PauseStartTime)
-		s.TotalPausedDuration += pauseDuration
-		s.isPaused = false
-	}
+// Start 开始计时
+func (s *Statistics) Start() {
+	s.StartTime = s.now()
 }
 
 // Finish 结束计时
 func (s *Statistics) Finish() {
-	// 如果还在暂停中，先恢复
-	if s.isPaused {
-		s.Resume()
-	}
-	s.EndTime = time.Now()
+	s.EndTime = s.now()
 }
 
 // AddKeystroke 增加总敲击数
@@ -78,23 +88,61 @@ func (s *Statistics) AddCompletedWord(wordLength int) {
 	s.TotalLetters += wordLength
 }
 
+// AddTypedChar 记录输入的一个字符，correct 表示输入后仍与目标一致
+func (s *Statistics) AddTypedChar(correct bool) {
+	s.countTyped(correct)
+	s.pending = append(s.pending, correct)
+}
+
+// AddCorrection 记录一次退格；删掉的是错误字符时计为已修正的错误
+func (s *Statistics) AddCorrection() {
+	if n := len(s.pending); n > 0 {
+		if !s.pending[n-1] {
+			s.CorrectedErrors++
+		}
+		s.pending = s.pending[:n-1]
+	}
+	s.lastKey = 0
+}
+
+// AddSubmit 记录确认输入的回车（或判定键），correct 表示输入被接受；当前输入随之清空
+func (s *Statistics) AddSubmit(correct bool) {
+	s.countTyped(correct)
+	s.ClearInput()
+}
+
+// ClearInput 当前输入被清空（之后的退格不再修正之前的错误）
+func (s *Statistics) ClearInput() {
+	s.pending = s.pending[:0]
+	s.lastKey = 0
+}
+
+// countTyped 计数并按当前秒采样
+func (s *Statistics) countTyped(correct bool) {
+	s.TypedChars++
+	if !correct {
+		s.IncorrectChars++
+	}
+	sec := int(s.GetElapsedSeconds())
+	for len(s.perSecond) <= sec {
+		s.perSecond = append(s.perSecond, 0)
+	}
+	s.perSecond[sec]++
+}
+
 // GetElapsedSeconds 获取有效耗时（秒）
 func (s *Statistics) GetElapsedSeconds() float64 {
 	var elapsed time.Duration
 
-	if s.EndTime.IsZero() {
+	switch {
+	case s.elapsedFunc != nil:
+		elapsed = s.elapsedFunc()
+	case s.EndTime.IsZero():
 		// 游戏还在进行中
-		elapsed = time.Since(s.StartTime)
-		// 如果正在暂停，需要减去当前暂停时长
-		if s.isPaused {
-			currentPauseDuration := time.Since(s.PauseStartTime)
-			elapsed -= (s.TotalPausedDuration + currentPauseDuration)
-		} else {
-			elapsed -= s.TotalPausedDuration
-		}
-	} else {
+		elapsed = s.now().Sub(s.StartTime)
+	default:
 		// 游戏已结束
-		elapsed = s.EndTime.Sub(s.StartTime) - s.TotalPausedDuration
+		elapsed = s.EndTime.Sub(s.StartTime)
 	}
 
 	if elapsed < 0 {
@@ -130,6 +178,57 @@ func (s *Statistics) GetAccuracyPercent() float64 {
 	return float64(s.CorrectChars) / float64(s.TotalKeystrokes) * 100.0
 }
 
+// GetUncorrectedErrors 获取没有被修正的错误字符数
+func (s *Statistics) GetUncorrectedErrors() int {
+	return max(s.IncorrectChars-s.CorrectedErrors, 0)
+}
+
+// GetRawWPM 获取原始速度：每分钟输入的字符数 / 5，不扣除错误
+func (s *Statistics) GetRawWPM() float64 {
+	elapsed := s.GetElapsedSeconds()
+	if elapsed < 0.1 {
+		return 0.0
+	}
+	return float64(s.TypedChars) / charsPerWord / (elapsed / 60)
+}
+
+// GetNetWPM 获取净速度：原始速度减去每分钟未修正的错误数
+func (s *Statistics) GetNetWPM() float64 {
+	elapsed := s.GetElapsedSeconds()
+	if elapsed < 0.1 {
+		return 0.0
+	}
+	return max(s.GetRawWPM()-float64(s.GetUncorrectedErrors())/(elapsed/60), 0)
+}
+
+// GetConsistency 获取速度稳定度（0-100）：100 × (1 − 每秒输入字符数的变异系数)
+// 只统计已经完整经过的秒，不足两秒时为 0
+func (s *Statistics) GetConsistency() float64 {
+	n := int(s.GetElapsedSeconds())
+	if n < 2 {
+		return 0.0
+	}
+	var sum float64
+	for i := 0; i < n && i < len(s.perSecond); i++ {
+		sum += float64(s.perSecond[i])
+	}
+	mean := sum / float64(n)
+	if mean == 0 {
+		return 0.0
+	}
+	var variance float64
+	for i := 0; i < n; i++ {
+		count := 0
+		if i < len(s.perSecond) {
+			count = s.perSecond[i]
+		}
+		d := float64(count) - mean
+		variance += d * d
+	}
+	cv := math.Sqrt(variance/float64(n)) / mean
+	return max(100*(1-cv), 0)
+}
+
 // Reset 重置所有统计数据
 func (s *Statistics) Reset() {
 	s.TotalKeystrokes = 0
@@ -137,9 +236,55 @@ func (s *Statistics) Reset() {
 	s.CorrectChars = 0
 	s.WordsCompleted = 0
 	s.TotalLetters = 0
+	s.TypedChars = 0
+	s.IncorrectChars = 0
+	s.CorrectedErrors = 0
+	s.pending = nil
+	s.perSecond = nil
+	s.Keys = KeyStats{}
+	s.lastKey = 0
+	s.lastKeyAt = 0
 	s.StartTime = time.Time{}
 	s.EndTime = time.Time{}
-	s.PauseStartTime = time.Time{}
-	s.TotalPausedDuration = 0
-	s.isPaused = false
+}
+
+// Snapshot 统计数据快照（用于持久化和展示）
+type Snapshot struct {
+	TotalKeystrokes  int     `json:"total_keystrokes"`
+	ValidKeystrokes  int     `json:"valid_keystrokes"`
+	CorrectChars     int     `json:"correct_chars"`
+	WordsCompleted   int     `json:"words_completed"`
+	TotalLetters     int     `json:"total_letters"`
+	ElapsedSeconds   float64 `json:"elapsed_seconds"`
+	LettersPerSecond float64 `json:"letters_per_second"`
+	WordsPerSecond   float64 `json:"words_per_second"`
+	AccuracyPercent  float64 `json:"accuracy_percent"`
+
+	// 打字测速指标（旧记录中没有这些字段，读出为 0）
+	NetWPM            float64 `json:"net_wpm"`
+	RawWPM            float64 `json:"raw_wpm"`
+	CorrectedErrors   int     `json:"corrected_errors"`
+	UncorrectedErrors int     `json:"uncorrected_errors"`
+	Consistency       float64 `json:"consistency"`
+}
+
+// Snapshot 获取当前统计数据的快照
+func (s *Statistics) Snapshot() Snapshot {
+	return Snapshot{
+		TotalKeystrokes:  s.TotalKeystrokes,
+		ValidKeystrokes:  s.ValidKeystrokes,
+		CorrectChars:     s.CorrectChars,
+		WordsCompleted:   s.WordsCompleted,
+		TotalLetters:     s.TotalLetters,
+		ElapsedSeconds:   s.GetElapsedSeconds(),
+		LettersPerSecond: s.GetLettersPerSecond(),
+		WordsPerSecond:   s.GetWordsPerSecond(),
+		AccuracyPercent:  s.GetAccuracyPercent(),
+
+		NetWPM:            s.GetNetWPM(),
+		RawWPM:            s.GetRawWPM(),
+		CorrectedErrors:   s.CorrectedErrors,
+		UncorrectedErrors: s.GetUncorrectedErrors(),
+		Consistency:       s.GetConsistency(),
+	}
 }
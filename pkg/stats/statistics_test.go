@@ -0,0 +1,135 @@
+package stats
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestStats() (*Statistics, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := NewWithClock(func() time.Time { return now }, nil)
+	s.Start()
+	return s, &now
+}
+
+func TestTypingMetrics(t *testing.T) {
+	s, now := newTestStats()
+
+	// 一分钟内输入 50 个字符：2 个错误字符，其中 1 个被退格修正
+	for i := 0; i < 48; i++ {
+		s.AddTypedChar(true)
+	}
+	s.AddTypedChar(false)
+	s.AddCorrection()
+	s.AddTypedChar(true)
+	s.AddTypedChar(false)
+	s.ClearInput()
+	s.AddCorrection() // 清空后的退格不修正之前的错误
+	*now = now.Add(time.Minute)
+
+	if s.TypedChars != 51 || s.CorrectedErrors != 1 || s.GetUncorrectedErrors() != 1 {
+		t.Fatalf("typed %d, corrected %d, uncorrected %d; want 51, 1, 1",
+			s.TypedChars, s.CorrectedErrors, s.GetUncorrectedErrors())
+	}
+	if raw := s.GetRawWPM(); math.Abs(raw-10.2) > 1e-9 {
+		t.Errorf("raw WPM = %v, want 10.2", raw)
+	}
+	if net := s.GetNetWPM(); math.Abs(net-9.2) > 1e-9 {
+		t.Errorf("net WPM = %v, want 9.2", net)
+	}
+}
+
+func TestConsistency(t *testing.T) {
+	s, now := newTestStats()
+	if c := s.GetConsistency(); c != 0 {
+		t.Errorf("consistency before two seconds = %v, want 0", c)
+	}
+
+	// 每秒 5 个字符，速度完全均匀
+	for sec := 0; sec < 4; sec++ {
+		for i := 0; i < 5; i++ {
+			s.AddSubmit(true)
+		}
+		*now = now.Add(time.Second)
+	}
+	if c := s.GetConsistency(); math.Abs(c-100) > 1e-9 {
+		t.Errorf("steady typing: consistency = %v, want 100", c)
+	}
+
+	// 停顿的秒数计为 0，拉低稳定度
+	*now = now.Add(4 * time.Second)
+	if c := s.GetConsistency(); math.Abs(c) > 1e-9 {
+		t.Errorf("typing half the time: consistency = %v, want 0", c)
+	}
+
+	// 有效耗时来自外部时钟（游戏中为 PlayClock）时，暂停的时间不计入采样
+	var played time.Duration
+	s = NewWithClock(func() time.Time { return *now }, func() time.Duration { return played })
+	s.Start()
+	s.AddTypedChar(true)
+	*now = now.Add(time.Hour) // 暂停
+	played += time.Second
+	*now = now.Add(time.Second)
+	s.AddTypedChar(true)
+	played += time.Second
+	*now = now.Add(time.Second)
+	if c := s.GetConsistency(); math.Abs(c-100) > 1e-9 {
+		t.Errorf("after a pause: consistency = %v, want 100", c)
+	}
+}
+
+func TestKeyStats(t *testing.T) {
+	s, now := newTestStats()
+
+	// "the"：t 是第一个键不计时，h 和 e 各隔 200ms
+	s.AddKey('t', 't')
+	*now = now.Add(200 * time.Millisecond)
+	s.AddKey('h', 'h')
+	*now = now.Add(200 * time.Millisecond)
+	s.AddKey('e', 'r') // 把 e 按成 r
+	s.AddCorrection()
+	*now = now.Add(time.Second)
+	s.AddKey('e', 'E') // 大小写不同也算按错，但不是错键
+	s.AddKey('x', 'x') // 上一个键按错，不计时
+	s.ClearInput()
+	s.AddKey(0, 'q') // 偏离所有目标的输入不计入
+
+	keys := s.Keys
+	if got := keys.Keys["h"]; got.Attempts != 1 || got.Timed != 1 || got.AvgLatency() != 200*time.Millisecond {
+		t.Errorf("h = %+v, want one timed attempt of 200ms", got)
+	}
+	if got := keys.Keys["e"]; got.Attempts != 2 || got.Errors != 2 || got.Timed != 1 {
+		t.Errorf("e = %+v, want 2 attempts, 2 errors, 1 timed", got)
+	}
+	if got := keys.Keys["x"]; got.Timed != 0 {
+		t.Errorf("x after an error should not be timed: %+v", got)
+	}
+	if _, ok := keys.Keys["q"]; ok {
+		t.Error("off-target input was counted")
+	}
+	if got := keys.Bigrams["he"]; got.Attempts != 1 || got.Errors != 1 {
+		t.Errorf("bigram he = %+v, want one error", got)
+	}
+	if want := map[string]int{"er": 1}; !reflect.DeepEqual(keys.Substitutions, want) {
+		t.Errorf("substitutions = %v, want %v", keys.Substitutions, want)
+	}
+
+	// 汇总多局后排出弱项
+	var total KeyStats
+	total.Merge(keys)
+	total.Merge(KeyStats{Keys: map[string]KeyStat{
+		"e": {Attempts: 8, Timed: 8, LatencyMS: 800},
+		"a": {Attempts: 10, Timed: 10, LatencyMS: 3000},
+		"s": {Attempts: 10, Timed: 10, LatencyMS: 1000},
+	}})
+	// e：平均 111ms + 20% 出错折合 200ms；a：300ms 没有出错；s：100ms
+	weak := total.WeakestKeys(3)
+	if len(weak) != 3 || weak[0].Keys != "e" || weak[1].Keys != "a" || weak[2].Keys != "s" {
+		t.Errorf("WeakestKeys = %+v, want e, a, s", weak)
+	}
+	if subs := total.TopSubstitutions(5); len(subs) != 1 || subs[0] != (Substitution{Expected: 'e', Typed: 'r', Count: 1}) {
+		t.Errorf("TopSubstitutions = %+v", subs)
+	}
+}
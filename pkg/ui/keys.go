@@ -0,0 +1,217 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/word-killer/word-killer/pkg/stats"
+)
+
+const (
+	// keyAnalysisRows 每个弱项列表显示的行数
+	keyAnalysisRows = 5
+
+	// keyColumnWidth 弱项列表每列的宽度
+	keyColumnWidth = 22
+)
+
+// keyboardRows QWERTY 键盘的三排字母键及每排的缩进
+var keyboardRows = []struct {
+	keys   string
+	indent int
+}{
+	{"qwertyuiop", 0},
+	{"asdfghjkl", 2},
+	{"zxcvbnm", 4},
+}
+
+// heatLevels 热力图的颜色分档：出错率低于 below 时使用 color
+var heatLevels = []struct {
+	below float64
+	color string
+	label string
+}{
+	{0.02, "28", "<2%"},
+	{0.05, "142", "<5%"},
+	{0.10, "208", "<10%"},
+	{2, "160", "≥10%"},
+}
+
+var keyNoDataStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("245")).
+	Background(lipgloss.Color("237"))
+
+// KeyReport 按键分析界面的数据
+type KeyReport struct {
+	Keys    stats.KeyStats
+	AllTime bool // 汇总历史记录中的全部对局，否则只有刚结束的一局
+	Games   int  // AllTime 时汇总的局数
+}
+
+// RenderKeyAnalysis renders the keyboard heatmap and the weakest keys, bigrams and typos
+func RenderKeyAnalysis(vp Viewport, report KeyReport) string {
+	l := newLayout(vp)
+	var s strings.Builder
+
+	// TOP: Header
+	scope := "This game"
+	if report.AllTime {
+		scope = fmt.Sprintf("All time (%d games)", report.Games)
+	}
+	s.WriteString(l.center(headerStyle.Render("Key Analysis  │  " + scope)))
+	s.WriteString("\n")
+
+	// MIDDLE: Heatmap and lists
+	s.WriteString(renderKeyAnalysisContent(l, report.Keys))
+	s.WriteString("\n")
+
+	// BOTTOM: Hints
+	s.WriteString(l.inputBox().Render("[Tab] This game / All time  │  [ESC] Back"))
+	s.WriteString("\n")
+
+	return s.String()
+}
+
+// renderKeyAnalysisContent renders the heatmap box
+func renderKeyAnalysisContent(l layout, keys stats.KeyStats) string {
+	var lines []string
+
+	lines = append(lines, "")
+	if keys.Empty() {
+		lines = append(lines, l.centerInner(hintStyle.Render("No key data yet - finish a game first")))
+	} else {
+		for _, row := range renderKeyboard(keys) {
+			lines = append(lines, l.centerInner(row))
+		}
+		lines = append(lines, l.centerInner(renderHeatLegend()))
+		lines = append(lines, "")
+		lines = append(lines, renderKeyColumns(l, keys)...)
+	}
+
+	// Fill to fixed height (12 lines)
+	for len(lines) < 12 {
+		lines = append(lines, "")
+	}
+
+	// header 2 + box frame 6 + hints 4
+	return l.box().Render(strings.Join(squeeze(lines, l.height-12), "\n"))
+}
+
+// renderKeyboard renders the keyboard rows colored by error rate
+// 各排右侧补齐到相同宽度，整体居中时保持键盘的错位形状
+func renderKeyboard(keys stats.KeyStats) []string {
+	rows := make([]string, len(keyboardRows))
+	width := 0
+	for i, row := range keyboardRows {
+		cells := make([]string, 0, len(row.keys))
+		for _, r := range row.keys {
+			label := " " + strings.ToUpper(string(r)) + " "
+			k, ok := keys.Keys[string(r)]
+			if !ok || k.Attempts == 0 {
+				cells = append(cells, keyNoDataStyle.Render(label))
+				continue
+			}
+			cells = append(cells, heatStyle(heatColor(k.ErrorRate())).Render(label))
+		}
+		rows[i] = strings.Repeat(" ", row.indent) + strings.Join(cells, " ")
+		width = max(width, lipgloss.Width(rows[i]))
+	}
+	for i, row := range rows {
+		rows[i] = row + strings.Repeat(" ", width-lipgloss.Width(row))
+	}
+	return rows
+}
+
+// heatColor 出错率对应的颜色
+func heatColor(rate float64) string {
+	for _, h := range heatLevels {
+		if rate < h.below {
+			return h.color
+		}
+	}
+	return heatLevels[len(heatLevels)-1].color
+}
+
+// heatStyle 热力图按键样式
+func heatStyle(color string) lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("231")).
+		Background(lipgloss.Color(color)).
+		Bold(true)
+}
+
+// renderHeatLegend renders the color legend under the keyboard
+func renderHeatLegend() string {
+	parts := make([]string, 0, len(heatLevels)+1)
+	for _, h := range heatLevels {
+		parts = append(parts, heatStyle(h.color).Render("  ")+" "+hintStyle.Render(h.label))
+	}
+	parts = append(parts, keyNoDataStyle.Render("  ")+" "+hintStyle.Render("no data"))
+	return hintStyle.Render("errors: ") + strings.Join(parts, "  ")
+}
+
+// renderKeyColumns renders the weakest keys, weakest bigrams and common typos side by side;
+// columns that don't fit on narrow terminals are left out from the right
+func renderKeyColumns(l layout, keys stats.KeyStats) []string {
+	columns := [][]string{
+		weakSpotLines("Weakest keys", keys.WeakestKeys(keyAnalysisRows)),
+		weakSpotLines("Weakest bigrams", keys.WeakestBigrams(keyAnalysisRows)),
+		typoLines(keys.TopSubstitutions(keyAnalysisRows)),
+	}
+	columns = columns[:max(min((l.text()-2)/keyColumnWidth, len(columns)), 1)]
+
+	lines := make([]string, keyAnalysisRows+1)
+	for i := range lines {
+		var row strings.Builder
+		row.WriteString("  ")
+		for _, col := range columns {
+			cell := ""
+			if i < len(col) {
+				cell = col[i]
+			}
+			row.WriteString(lipgloss.NewStyle().Width(keyColumnWidth).Render(cell))
+		}
+		lines[i] = row.String()
+	}
+	return lines
+}
+
+// weakSpotLines formats a weak key list: title, then "key  error%  latency"
+func weakSpotLines(title string, spots []stats.WeakSpot) []string {
+	lines := []string{titleStyle.Render(title)}
+	if len(spots) == 0 {
+		return append(lines, hintStyle.Render("not enough data"))
+	}
+	for _, s := range spots {
+		latency := "    -"
+		if s.Timed > 0 {
+			latency = fmt.Sprintf("%3dms", s.AvgLatency().Milliseconds())
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s",
+			statValueStyle.Render(fmt.Sprintf("%-3s", keyName(s.Keys))),
+			statsStyle.Render(fmt.Sprintf("%4.0f%%", s.ErrorRate()*100)),
+			hintStyle.Render(latency)))
+	}
+	return lines
+}
+
+// typoLines formats the most common wrong-key substitutions
+func typoLines(subs []stats.Substitution) []string {
+	lines := []string{titleStyle.Render("Common typos")}
+	if len(subs) == 0 {
+		return append(lines, hintStyle.Render("none"))
+	}
+	for _, s := range subs {
+		lines = append(lines, fmt.Sprintf("%s → %s %s",
+			statValueStyle.Render(keyName(string(s.Expected))),
+			statValueStyle.Render(keyName(string(s.Typed))),
+			hintStyle.Render(fmt.Sprintf("×%d", s.Count))))
+	}
+	return lines
+}
+
+// keyName 按键的显示名称（空格显示为 ␣）
+func keyName(keys string) string {
+	return strings.ReplaceAll(keys, " ", "␣")
+}
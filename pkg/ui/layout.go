@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Viewport 终端可用区域的大小（列数 × 行数），来自 tea.WindowSizeMsg
+// 零值表示尚未收到窗口大小，按 DefaultViewport 布局
+type Viewport struct {
+	Width  int
+	Height int
+}
+
+const (
+	// MinWidth / MinHeight 低于此大小时显示 "terminal too small"
+	MinWidth  = 60
+	MinHeight = 24
+
+	// maxContentWidth 超宽终端上内容区的最大宽度
+	maxContentWidth = 160
+)
+
+// DefaultViewport 未知终端大小时使用的视口（与旧的固定布局一致）
+var DefaultViewport = Viewport{Width: contentWidth + 2, Height: 32}
+
+// orDefault 零值视口替换为 DefaultViewport
+func (v Viewport) orDefault() Viewport {
+	if v.Width <= 0 || v.Height <= 0 {
+		return DefaultViewport
+	}
+	return v
+}
+
+// TooSmall 终端是否小于最小尺寸
+func (v Viewport) TooSmall() bool {
+	v = v.orDefault()
+	return v.Width < MinWidth || v.Height < MinHeight
+}
+
+// Shrink 返回去掉顶部 lines 行之后的视口（如回放横幅）
+func (v Viewport) Shrink(lines int) Viewport {
+	v = v.orDefault()
+	v.Height -= lines
+	return v
+}
+
+// layout 根据视口计算的布局尺寸
+type layout struct {
+	width  int // 内容框宽度（含内边距、不含边框），最小为 MinWidth-2
+	height int // 可用行数
+}
+
+func newLayout(v Viewport) layout {
+	v = v.orDefault()
+	// 两侧各留 1 列给边框
+	width := max(min(v.Width-2, maxContentWidth), MinWidth-2)
+	return layout{width: width, height: v.Height}
+}
+
+// inner 内容框内去掉边距后的可用宽度
+func (l layout) inner() int {
+	return l.width - 8
+}
+
+// box 中部内容框样式
+func (l layout) box() lipgloss.Style {
+	return wordBoxStyle.Width(l.width)
+}
+
+// inputBox 底部输入框/提示框样式
+func (l layout) inputBox() lipgloss.Style {
+	return inputBoxStyle.Width(l.width)
+}
+
+// center 在内容宽度内居中；内容超宽时原样返回，不折行
+func (l layout) center(s string) string {
+	return lipgloss.PlaceHorizontal(l.width, lipgloss.Center, s)
+}
+
+// centerInner 在内容框内居中（菜单选项等）
+func (l layout) centerInner(s string) string {
+	return lipgloss.PlaceHorizontal(l.inner(), lipgloss.Center, s)
+}
+
+// rows 其余部分占用 overhead 行时，可变区域可以使用的行数（至少 minRows）
+func (l layout) rows(overhead, minRows int) int {
+	return max(l.height-overhead, minRows)
+}
+
+// indent 按 80 列排版的固定内容在更宽的内容框中居中所需的缩进
+func (l layout) indent() string {
+	return strings.Repeat(" ", max((l.width-contentWidth)/2, 0))
+}
+
+// shortfall 内容框比 80 列终端上的内容框（78 列）窄多少列，按 80 列排版的固定内容要左移这么多
+func (l layout) shortfall() int {
+	return max(contentWidth-2-l.width, 0)
+}
+
+// text 内容框内去掉内边距后可以显示文字的宽度
+func (l layout) text() int {
+	return l.width - 4
+}
+
+// hints 底部提示框：提示过宽时按顺序去掉可选项，窄终端上不折行
+func (l layout) hints(segments []string, optional ...int) string {
+	return l.inputBox().Render(fitSegments(l.text(), lipgloss.NewStyle(), segments, optional))
+}
+
+// hintLine 屏幕最下方的一行提示：过宽时按顺序去掉可选项
+func (l layout) hintLine(segments []string, optional ...int) string {
+	return hintStyle.Render("  " + fitSegments(l.width, hintStyle, segments, optional))
+}
+
+// fitSegments 用 "  │  " 连接各段；渲染后超过 width 时按顺序去掉可选段
+func fitSegments(width int, style lipgloss.Style, segments []string, optional []int) string {
+	drop := make(map[int]bool)
+	for {
+		var kept []string
+		for i, s := range segments {
+			if !drop[i] {
+				kept = append(kept, s)
+			}
+		}
+		line := strings.Join(kept, "  │  ")
+		if len(optional) == 0 || lipgloss.Width(style.Render(line)) <= width {
+			return line
+		}
+		drop[optional[0]] = true
+		optional = optional[1:]
+	}
+}
+
+// squeeze 行数超过 maxLines 时从下往上去掉空行，直到放得下（不删除非空行）
+func squeeze(lines []string, maxLines int) []string {
+	for i := len(lines) - 1; i >= 0 && len(lines) > maxLines; i-- {
+		if strings.TrimSpace(lines[i]) == "" {
+			lines = append(lines[:i], lines[i+1:]...)
+		}
+	}
+	return lines
+}
+
+// RenderTooSmall renders the "terminal too small" screen
+func RenderTooSmall(v Viewport) string {
+	v = v.orDefault()
+	msg := lipgloss.JoinVertical(lipgloss.Center,
+		titleStyle.Render("Terminal too small"),
+		"",
+		statsStyle.Render(fmt.Sprintf("Current: %d × %d", v.Width, v.Height)),
+		hintStyle.Render(fmt.Sprintf("Needed:  %d × %d", MinWidth, MinHeight)),
+		"",
+		menuNormalStyle.Render("Resize the window to continue  │  [Ctrl+C] Quit"),
+	)
+	return lipgloss.Place(v.Width, v.Height, lipgloss.Center, lipgloss.Center, msg)
+}
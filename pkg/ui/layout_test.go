@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/word-killer/word-killer/pkg/stats"
+)
+
+func TestViewportTooSmall(t *testing.T) {
+	tests := []struct {
+		v    Viewport
+		want bool
+	}{
+		{Viewport{}, false}, // 尚未收到窗口大小
+		{Viewport{Width: 60, Height: 24}, false},
+		{Viewport{Width: 59, Height: 40}, true},
+		{Viewport{Width: 200, Height: 23}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.v.TooSmall(); got != tt.want {
+			t.Errorf("%+v.TooSmall() = %v, want %v", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestNewLayoutClampsWidth(t *testing.T) {
+	tests := []struct {
+		width, want int
+	}{
+		{0, contentWidth},
+		{60, MinWidth - 2},
+		{80, 78},
+		{120, 118},
+		{400, maxContentWidth},
+	}
+	for _, tt := range tests {
+		if got := newLayout(Viewport{Width: tt.width, Height: 30}).width; got != tt.want {
+			t.Errorf("width %d: layout width = %d, want %d", tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestSqueezeDropsBlankLinesOnly(t *testing.T) {
+	lines := squeeze([]string{"a", "", "b", " ", "c", ""}, 4)
+	if got := strings.Join(lines, "|"); got != "a||b|c" {
+		t.Errorf("squeeze = %q", got)
+	}
+	lines = squeeze([]string{"a", "b", "c"}, 1)
+	if len(lines) != 3 {
+		t.Errorf("squeeze removed non-blank lines: %q", lines)
+	}
+}
+
+func TestScreensFitMinimumViewport(t *testing.T) {
+	vp := Viewport{Width: MinWidth, Height: MinHeight}
+	screens := map[string]string{
+		"about":       RenderAbout(vp),
+		"welcome":     RenderWelcome(vp, &WelcomeAnimationState{}, 0),
+		"mode select": RenderModeSelection(vp, 0, 0, "", nil),
+		"mode notice": RenderModeSelection(vp, 8, 0, "No words due for review - next one Jan 2 15:04", nil),
+		"mode packs":  RenderModeSelection(vp, 9, 0, "", &PackPicker{Name: "Top 1000 English", Words: 1000, Language: "en"}),
+	}
+
+	// 计分模式的状态栏和结算界面
+	words := []WordInfo{{Text: "alpha"}, {Text: "bravo", Completed: true}}
+	stats := GameStats{
+		WordsCompleted: 42, ElapsedSeconds: 123.4, LettersPerSecond: 12.3, AccuracyPercent: 100,
+		Score: &ScoreInfo{Score: 12345, Streak: 25, MaxStreak: 25, Multiplier: 1.5, Penalties: 3},
+	}
+	screens["classic"] = RenderGame(vp, words, nil, "alp", stats, 58)
+	screens["countdown"] = RenderCountdownGame(vp, words, nil, "alp", stats, 42, 60)
+	screens["speedrun"] = RenderSpeedRunGame(vp, words, nil, "alp", stats, 123.4, 99.999, GhostInfo{})
+	screens["results"] = RenderResults(vp, stats, false, 0, 0, RecordInfo{})
+	screens["practice"] = RenderPracticeGame(vp, words, nil, "alp", stats, 100, "q z x · qu ck ng")
+	roman := make([]WordInfo, 60)
+	for i := range roman {
+		roman[i] = WordInfo{Text: "itadakimasu", Target: "いただきます", Syllables: []string{"i", "ta", "da", "ki", "ma", "su"}}
+	}
+	screens["romanization"] = RenderRomanizationGame(vp, roman, []int{0}, "itad", stats, 60)
+	screens["key analysis"] = RenderKeyAnalysis(vp, KeyReport{Keys: fullKeyStats(), AllTime: true, Games: 1234})
+
+	// 其余模式和界面
+	plain := stats
+	plain.Score = nil
+	screens["classic unscored"] = RenderGame(vp, words, nil, "alp", plain, 58)
+	screens["countdown unscored"] = RenderCountdownGame(vp, words, nil, "alp", plain, 42, 60)
+	screens["pause"] = RenderPauseMenu(vp, 0, stats, 58, 0)
+	screens["sentence"] = RenderSentenceGame(vp, "The quick brown fox jumps over the lazy dog near the riverbank.", "The quick", plain)
+	screens["rhythm master"] = RenderRhythmMasterGame(vp, words, nil, "alp", plain, 1.5, 3, 12, 4)
+	dance := RhythmDanceStats{Song: "Demo Groove · 100 BPM · 2/4", RemainingTime: 42, TotalScore: 12345,
+		PerfectCount: 10, NiceCount: 5, OKCount: 3, MissCount: 1, CompletedWords: 19, CurrentCombo: 8, MaxCombo: 12}
+	screens["rhythm dance"] = RenderRhythmDanceGame(vp, "", []string{"one", "two", "three", "four", "five"}, 2, "th",
+		RhythmBarInfo{PointerPosition: 0.4, GoldenRatio: 0.618, PerfectZone: 0.02, NiceZone: 0.05, OKZone: 0.1},
+		dance, JudgmentEffectInfo{})
+	screens["rhythm dance results"] = RenderRhythmDanceResults(vp, dance, 0, 0, RecordInfo{Rank: 3, Value: 12345, Unit: "pts"})
+	rows := make([]SettingRow, 40)
+	for i := range rows {
+		rows[i] = SettingRow{Label: "Rhythm difficulty step (s)", Value: "50%", Slider: 0.5}
+	}
+	screens["settings"] = RenderSettings(vp, SettingsView{Rows: rows, Valid: true, SavePath: "config.json"}, 0)
+	for name, s := range screens {
+		if h := lipgloss.Height(strings.TrimRight(s, "\n")); h > vp.Height {
+			t.Errorf("%s: %d lines, viewport has %d", name, h, vp.Height)
+		}
+		if w := lipgloss.Width(s); w > vp.Width {
+			t.Errorf("%s: %d columns, viewport has %d", name, w, vp.Width)
+		}
+	}
+}
+
+// fullKeyStats 每个字母都有数据、每个列表都排满的按键统计
+func fullKeyStats() stats.KeyStats {
+	keys := stats.KeyStats{Keys: map[string]stats.KeyStat{}, Bigrams: map[string]stats.KeyStat{}, Substitutions: map[string]int{}}
+	for i, r := range "abcdefghijklmnopqrstuvwxyz" {
+		keys.Keys[string(r)] = stats.KeyStat{Attempts: 100, Errors: i, Timed: 90, LatencyMS: int64(90 * (100 + 10*i))}
+		keys.Bigrams[string(r)+"e"] = stats.KeyStat{Attempts: 10, Errors: i % 4, Timed: 10, LatencyMS: int64(1000 + 100*i)}
+		keys.Substitutions[string(r)+"x"] = 100 + i
+	}
+	return keys
+}
+
+func TestWordsPadByDisplayWidth(t *testing.T) {
+	for _, word := range []string{"apple", "привет", "λόγος", "garçon", "日本語"} {
+		if w := lipgloss.Width(padToWidth(word, highlightStyle.Render(word), 12)); w != 12 {
+			t.Errorf("padToWidth(%q) is %d columns wide, want 12", word, w)
+		}
+	}
+
+	matched, rest := matchedPrefix("привет", "при")
+	if matched != "при" || rest != "вет" {
+		t.Errorf("matchedPrefix = %q, %q; want %q, %q", matched, rest, "при", "вет")
+	}
+	if matched, rest := matchedPrefix("мир", "мирный"); matched != "мир" || rest != "" {
+		t.Errorf("input longer than word: matchedPrefix = %q, %q", matched, rest)
+	}
+
+	// 词库可以是任何文字：单词列仍然对齐，不超出屏幕
+	vp := Viewport{Width: MinWidth, Height: MinHeight}
+	words := []WordInfo{{Text: "привет"}, {Text: "日本語"}, {Text: "straße"}, {Text: "λόγος"}}
+	if w := lipgloss.Width(RenderGame(vp, words, nil, "при", GameStats{}, 58)); w > vp.Width {
+		t.Errorf("classic screen with non-Latin words: %d columns, viewport has %d", w, vp.Width)
+	}
+}
+
+func TestRomanizedWordHighlightsSyllables(t *testing.T) {
+	w := WordInfo{Text: "nihao", Target: "你好", Syllables: []string{"ni", "hao"}}
+	tests := []struct {
+		input           string
+		target, reading string
+	}{
+		{"n", romanTargetStyle.Render("你好"),
+			highlightStyle.Render("n") + currentSyllableStyle.Render("i") + " " + wordStyle.Render("hao")},
+		{"ni", highlightStyle.Render("你") + romanTargetStyle.Render("好"),
+			highlightStyle.Render("ni") + " " + highlightStyle.Render("") + currentSyllableStyle.Render("hao")},
+		{"nihao", highlightStyle.Render("你好"),
+			highlightStyle.Render("ni") + " " + highlightStyle.Render("hao")},
+	}
+	for _, tt := range tests {
+		target, reading := renderRomanizedWord(w, true, tt.input)
+		if target != tt.target || reading != tt.reading {
+			t.Errorf("input %q: got %q / %q, want %q / %q", tt.input, target, reading, tt.target, tt.reading)
+		}
+	}
+}
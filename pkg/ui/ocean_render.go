@@ -7,27 +7,47 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 	"github.com/word-killer/word-killer/pkg/game"
 )
 
 const (
-	oceanContentWidth = 72 // 内容区域宽度（不含边框）
-	oceanHeight       = 20 // 海洋场景高度（增加到20行）
+	// 游戏逻辑使用的海洋坐标系（气泡、装饰的列号，小鱼的行号都基于它）
+	// 渲染时按比例拉伸到实际网格大小
+	oceanLogicalWidth  = 72
+	oceanLogicalHeight = 20
+
+	minOceanHeight = 10 // 海洋场景最少行数
+	oceanOverhead  = 10 // 状态栏 2 + 海洋框 6 + 输入提示 2
 )
 
+// oceanCol 把逻辑列号换算到宽度为 width 的网格
+func oceanCol(x, width int) int {
+	return x * width / oceanLogicalWidth
+}
+
+// oceanRow 把逻辑行号换算到高度为 height 的网格（顶部两行波浪保持不变）
+func oceanRow(y, height int) int {
+	if y < 2 {
+		return y
+	}
+	return 2 + (y-2)*(height-2)/(oceanLogicalHeight-2)
+}
+
 // RenderUnderwaterGame 渲染海底世界游戏界面
-func RenderUnderwaterGame(g *game.Game) string {
+func RenderUnderwaterGame(vp Viewport, g *game.Game) string {
 	if g.UnderwaterState == nil {
 		return "海底世界初始化中..."
 	}
 
+	l := newLayout(vp)
 	var sections []string
 
 	// 1. 顶部状态栏（倒计时、统计）
-	sections = append(sections, renderUnderwaterStatus(g))
+	sections = append(sections, renderUnderwaterStatus(l, g))
 
-	// 2. 海洋场景（主要游戏区域）
-	sections = append(sections, renderOceanScene(g.UnderwaterState, g.InputBuffer))
+	// 2. 海洋场景（主要游戏区域），随终端大小拉伸
+	sections = append(sections, renderOceanScene(l, l.rows(oceanOverhead, minOceanHeight), g.UnderwaterState, g.InputBuffer))
 
 	// 3. 输入提示
 	sections = append(sections, renderUnderwaterInput(g.InputBuffer))
@@ -36,7 +56,7 @@ func RenderUnderwaterGame(g *game.Game) string {
 }
 
 // renderUnderwaterStatus 渲染状态栏
-func renderUnderwaterStatus(g *game.Game) string {
+func renderUnderwaterStatus(l layout, g *game.Game) string {
 	remaining := g.GetRemainingTime()
 	minutes := remaining / 60
 	seconds := remaining % 60
@@ -71,7 +91,7 @@ func renderUnderwaterStatus(g *game.Game) string {
 	// Calculate spacing
 	leftWidth := lipgloss.Width(statusLeft)
 	rightWidth := lipgloss.Width(statusRight)
-	spacing := contentWidth - leftWidth - rightWidth - 4 // Subtract margins
+	spacing := l.width - leftWidth - rightWidth - 4 // Subtract margins
 
 	if spacing < 1 {
 		spacing = 1
@@ -83,11 +103,11 @@ func renderUnderwaterStatus(g *game.Game) string {
 }
 
 // renderOceanScene 渲染海洋场景（核心渲染函数）
-func renderOceanScene(state *game.UnderwaterState, input string) string {
-	// 创建字符网格 (10行 × 72列)
-	grid := make([][]rune, oceanHeight)
+func renderOceanScene(l layout, height int, state *game.UnderwaterState, input string) string {
+	// 创建字符网格（框内宽度 × height 行）
+	grid := make([][]rune, height)
 	for i := range grid {
-		grid[i] = make([]rune, oceanContentWidth)
+		grid[i] = make([]rune, l.inner())
 		for j := range grid[i] {
 			grid[i][j] = ' '
 		}
@@ -101,7 +121,7 @@ func renderOceanScene(state *game.UnderwaterState, input string) string {
 	// Convert to colored strings
 	lines := renderGridWithColors(grid, state, input)
 
-	return wordBoxStyle.Render(strings.Join(lines, "\n"))
+	return l.box().Render(strings.Join(lines, "\n"))
 }
 
 // renderBackgroundElements 渲染背景元素（珊瑚、海草、贝壳、海星）
@@ -109,40 +129,45 @@ func renderBackgroundElements(grid [][]rune, state *game.UnderwaterState) {
 	gridHeight := len(grid)
 	gridWidth := len(grid[0])
 	bottomRow := gridHeight - 1
+	col := func(x int) int { return oceanCol(x, gridWidth) }
 
 	// === 珊瑚礁（多行组合）===
 	// 珊瑚礁1 - 左侧 (位置 10-14)
 	if bottomRow >= 0 && bottomRow-2 >= 0 {
-		grid[bottomRow][12] = '※'   // 底部中心
-		grid[bottomRow][11] = '◊'   // 左侧
-		grid[bottomRow][13] = '◇'   // 右侧
-		grid[bottomRow-1][12] = 'Ψ' // 上层
-		grid[bottomRow-1][11] = '°' // 气泡
+		x := col(12)
+		grid[bottomRow][x] = '※'     // 底部中心
+		grid[bottomRow][x-1] = '◊'   // 左侧
+		grid[bottomRow][x+1] = '◇'   // 右侧
+		grid[bottomRow-1][x] = 'Ψ'   // 上层
+		grid[bottomRow-1][x-1] = '°' // 气泡
 	}
 
 	// 珊瑚礁2 - 中央 (位置 35-40)
 	if bottomRow >= 0 && bottomRow-3 >= 0 {
-		grid[bottomRow][37] = '※'   // 底部中心
-		grid[bottomRow][36] = '◈'   // 左
-		grid[bottomRow][38] = '⟡'   // 右
-		grid[bottomRow-1][37] = '✿' // 中层
-		grid[bottomRow-1][36] = 'ω' // 左中
-		grid[bottomRow-1][38] = 'Ψ' // 右中
-		grid[bottomRow-2][37] = '°' // 顶部气泡
+		x := col(37)
+		grid[bottomRow][x] = '※'     // 底部中心
+		grid[bottomRow][x-1] = '◈'   // 左
+		grid[bottomRow][x+1] = '⟡'   // 右
+		grid[bottomRow-1][x] = '✿'   // 中层
+		grid[bottomRow-1][x-1] = 'ω' // 左中
+		grid[bottomRow-1][x+1] = 'Ψ' // 右中
+		grid[bottomRow-2][x] = '°'   // 顶部气泡
 	}
 
 	// 珊瑚礁3 - 右侧 (位置 58-62)
 	if bottomRow >= 0 && bottomRow-2 >= 0 {
-		grid[bottomRow][60] = '◆'   // 底部
-		grid[bottomRow][59] = '◊'   // 左
-		grid[bottomRow][61] = '◇'   // 右
-		grid[bottomRow-1][60] = '※' // 上层
-		grid[bottomRow-1][61] = '°' // 气泡
+		x := col(60)
+		grid[bottomRow][x] = '◆'     // 底部
+		grid[bottomRow][x-1] = '◊'   // 左
+		grid[bottomRow][x+1] = '◇'   // 右
+		grid[bottomRow-1][x] = '※'   // 上层
+		grid[bottomRow-1][x+1] = '°' // 气泡
 	}
 
 	// === 海草（摇曳的感觉）===
 	seaGrassPositions := []int{8, 18, 28, 48, 68}
 	for i, x := range seaGrassPositions {
+		x = col(x)
 		if x >= gridWidth {
 			continue
 		}
@@ -175,15 +200,15 @@ func renderBackgroundElements(grid [][]rune, state *game.UnderwaterState) {
 		{65, '◇'},
 	}
 	for _, shell := range shellDecorations {
-		if shell.x < gridWidth && bottomRow >= 0 {
-			grid[bottomRow][shell.x] = shell.char
+		if x := col(shell.x); x < gridWidth && bottomRow >= 0 {
+			grid[bottomRow][x] = shell.char
 		}
 	}
 
 	// 海星
 	starPositions := []int{15, 33, 55}
 	for _, x := range starPositions {
-		if x < gridWidth && bottomRow >= 0 {
+		if x = col(x); x < gridWidth && bottomRow >= 0 {
 			grid[bottomRow][x] = '✦'
 		}
 	}
@@ -227,8 +252,8 @@ func renderBubbles(grid [][]rune, state *game.UnderwaterState) {
 			continue
 		}
 
-		x := bubble.X
-		y := int(bubble.Y)
+		x := oceanCol(bubble.X, len(grid[0]))
+		y := int(bubble.Y * float64(len(grid)) / oceanLogicalHeight)
 
 		if x >= 0 && x < len(grid[0]) && y >= 0 && y < len(grid) {
 			// 根据气泡流编号选择不同大小的气泡
@@ -254,15 +279,15 @@ func renderFishes(grid [][]rune, state *game.UnderwaterState, input string) {
 				continue
 			}
 
-			// Check if on the same row
-			if fish1.Y == fish2.Y {
+			// Check if on the same row (after scaling to the grid height)
+			if oceanRow(fish1.Y, len(grid)) == oceanRow(fish2.Y, len(grid)) {
 				// Calculate X ranges for both fish
 				// Both directions now use 4 extra chars: left=◀□word-◁, right=▷-word□▶
 				x1Start := int(fish1.X * float64(len(grid[0])))
-				x1End := x1Start + len(fish1.Word) + 4
+				x1End := x1Start + textWidth(fish1.Word) + 4
 
 				x2Start := int(fish2.X * float64(len(grid[0])))
-				x2End := x2Start + len(fish2.Word) + 4
+				x2End := x2Start + textWidth(fish2.Word) + 4
 
 				// Check if ranges overlap
 				if x1Start < x2End && x2Start < x1End {
@@ -289,7 +314,7 @@ func renderFishes(grid [][]rune, state *game.UnderwaterState, input string) {
 
 		// 计算屏幕位置
 		xPos := int(fish.X * float64(len(grid[0])))
-		yPos := fish.Y
+		yPos := oceanRow(fish.Y, len(grid))
 
 		// Render fish based on direction
 		if yPos >= 0 && yPos < len(grid) {
@@ -297,25 +322,33 @@ func renderFishes(grid [][]rune, state *game.UnderwaterState, input string) {
 				// Right: ▷-word□▶
 				renderFishPart(grid, yPos, xPos, "▷-")
 				renderFishPart(grid, yPos, xPos+2, fish.Word)
-				renderFishPart(grid, yPos, xPos+2+len(fish.Word), "□▶")
+				renderFishPart(grid, yPos, xPos+2+textWidth(fish.Word), "□▶")
 			} else {
 				// Left: ◀□word-◁
 				renderFishPart(grid, yPos, xPos, "◀□")
 				renderFishPart(grid, yPos, xPos+2, fish.Word)
-				renderFishPart(grid, yPos, xPos+2+len(fish.Word), "-◁")
+				renderFishPart(grid, yPos, xPos+2+textWidth(fish.Word), "-◁")
 			}
 		}
 	}
 }
 
 // renderFishPart renders a part of the fish (head/body/tail)
+// 宽字符占两格，第二格写入 0，渲染时跳过
 func renderFishPart(grid [][]rune, y, xStart int, part string) {
-	runes := []rune(part)
-	for i, ch := range runes {
-		x := xStart + i
-		if x >= 0 && x < len(grid[0]) && y >= 0 && y < len(grid) {
-			grid[y][x] = ch
+	x := xStart
+	for _, ch := range part {
+		w := runewidth.RuneWidth(ch)
+		for i := 0; i < w; i++ {
+			if x+i >= 0 && x+i < len(grid[0]) && y >= 0 && y < len(grid) {
+				if i == 0 {
+					grid[y][x+i] = ch
+				} else {
+					grid[y][x+i] = 0
+				}
+			}
 		}
+		x += w
 	}
 }
 
@@ -345,6 +378,10 @@ func renderGridWithColors(grid [][]rune, state *game.UnderwaterState, input stri
 		sb.WriteString("  ") // 左边距
 
 		for x, ch := range row {
+			if ch == 0 {
+				// 宽字符的第二格
+				continue
+			}
 			if ch == ' ' {
 				sb.WriteRune(ch)
 				continue
@@ -360,8 +397,8 @@ func renderGridWithColors(grid [][]rune, state *game.UnderwaterState, input stri
 			// Check if within any fish range
 			for idx, fish := range state.Fishes {
 				fishX := int(fish.X * float64(len(grid[0])))
-				fishY := fish.Y
-				fishWidth := len(fish.Word) + 4 // Updated for new fish design
+				fishY := oceanRow(fish.Y, len(grid))
+				fishWidth := textWidth(fish.Word) + 4 // Updated for new fish design
 
 				if y == fishY && x >= fishX && x < fishX+fishWidth {
 					fishIdx = idx
@@ -376,7 +413,7 @@ func renderGridWithColors(grid [][]rune, state *game.UnderwaterState, input stri
 						// Left: ◀□word-◁
 						wordStart = fishX + 2
 					}
-					wordEnd := wordStart + len(fish.Word)
+					wordEnd := wordStart + textWidth(fish.Word)
 					if x >= wordStart && x < wordEnd {
 						inFishWord = true
 					} else {
@@ -403,7 +440,7 @@ func renderGridWithColors(grid [][]rune, state *game.UnderwaterState, input stri
 					// Check if this character should be highlighted (matches input)
 					if !targetFish.Completed && len(input) > 0 &&
 					   strings.HasPrefix(targetFish.Word, input) &&
-					   charPos < len(input) {
+					   charPos < textWidth(input) {
 						// Matched character - use highlight style
 						styled = highlightStyle.Render(string(ch))
 					} else {
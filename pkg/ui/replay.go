@@ -0,0 +1,24 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RenderReplayBanner 渲染回放进度条（显示在游戏画面顶部）
+func RenderReplayBanner(vp Viewport, mode string, elapsed, total time.Duration) string {
+	if elapsed > total {
+		elapsed = total
+	}
+
+	bannerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("201")).
+		Bold(true)
+
+	text := fmt.Sprintf("▶ REPLAY  %s  │  %5.1fs / %.1fs  │  [ESC] Stop",
+		mode, elapsed.Seconds(), total.Seconds())
+
+	return newLayout(vp).center(bannerStyle.Render(text))
+}
@@ -7,10 +7,12 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/word-killer/word-killer/pkg/game"
 )
 
 // RhythmDanceStats 节奏舞蹈模式统计信息
 type RhythmDanceStats struct {
+	Song            string // 曲谱信息（标题 · BPM · 段落），没有曲谱时为空
 	RemainingTime   int
 	TotalScore      int
 	PerfectCount    int
@@ -20,24 +22,33 @@ type RhythmDanceStats struct {
 	CompletedWords  int
 	CurrentCombo    int
 	MaxCombo        int
-	JudgmentHistory []string // 判定历史记录
+	JudgmentHistory []game.Judgment // 判定历史记录
+	NetWPM          float64         // 结算界面的打字速度
+	RawWPM          float64
+	Consistency     float64
 }
 
 // RhythmBarInfo 节奏条信息
 type RhythmBarInfo struct {
 	PointerPosition float64
 	GoldenRatio     float64
+
+	// 判定区域在黄金点单侧的宽度（节奏条总长为 1），由判定窗口和当前速度换算
+	PerfectZone float64
+	NiceZone    float64
+	OKZone      float64
 }
 
 // JudgmentEffectInfo 判定特效信息
 type JudgmentEffectInfo struct {
-	LastJudgment         string
+	LastJudgment         game.Judgment
 	LastJudgmentTime     time.Time
 	LastJudgmentPosition float64 // 上次判定的指针位置（用于显示箭头）
 }
 
 // RenderRhythmDanceGame 渲染节奏舞蹈模式主界面
 func RenderRhythmDanceGame(
+	vp Viewport,
 	danceFrame string,
 	wordQueue []string,   // 单词队列（固定长度5）
 	currentWordIndex int, // 当前单词索引（固定为2）
@@ -46,14 +57,15 @@ func RenderRhythmDanceGame(
 	stats RhythmDanceStats,
 	effectInfo JudgmentEffectInfo,
 ) string {
+	l := newLayout(vp)
 	var s strings.Builder
 
 	// === 顶部：统计信息 ===
-	s.WriteString(renderRhythmStats(stats))
+	s.WriteString(renderRhythmStats(l, stats))
 	s.WriteString("\n")
 
 	// === 中部：主游戏区域（舞蹈小人 + 单词队列 + 节奏条）===
-	mainArea := renderRhythmMainArea(danceFrame, wordQueue, currentWordIndex, userInput, rhythmBar, effectInfo, stats)
+	mainArea := renderRhythmMainArea(l, danceFrame, wordQueue, currentWordIndex, userInput, rhythmBar, effectInfo, stats)
 	s.WriteString(mainArea)
 	s.WriteString("\n")
 
@@ -65,7 +77,7 @@ func RenderRhythmDanceGame(
 }
 
 // renderRhythmStats 渲染统计信息栏
-func renderRhythmStats(stats RhythmDanceStats) string {
+func renderRhythmStats(l layout, stats RhythmDanceStats) string {
 	// 时间显示（小于10秒时红色警告）
 	var timeStyle lipgloss.Style
 	if stats.RemainingTime < 10 {
@@ -83,17 +95,20 @@ func renderRhythmStats(stats RhythmDanceStats) string {
 	scoreDisplay := fmt.Sprintf("Score: %d", stats.TotalScore)
 	comboDisplay := fmt.Sprintf("Combo: %d", stats.CurrentCombo)
 
-	statusLine := fmt.Sprintf("%s  │  %s  │  %s",
-		timeStyle.Render(timeDisplay),
-		scoreDisplay,
-		comboDisplay)
+	segments := []string{timeStyle.Render(timeDisplay), scoreDisplay, comboDisplay}
+	if stats.Song != "" {
+		segments = append(segments, "♪ "+stats.Song)
+	}
+	// 窄终端上省略曲谱信息
+	statusLine := joinStatus(l, segments, 3)
 
 	statusStyled := headerStyle.Render(statusLine)
-	return lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(statusStyled)
+	return l.center(statusStyled)
 }
 
 // renderRhythmMainArea 渲染主游戏区域（新布局：上下布局，单词队列在中间）
 func renderRhythmMainArea(
+	l layout,
 	danceFrame string,
 	wordQueue []string,
 	currentWordIndex int,
@@ -108,28 +123,29 @@ func renderRhythmMainArea(
 	lines = append(lines, "")
 
 	// == 上部：判定历史记录（两行）==
-	historyLines := renderJudgmentHistory(stats)
+	historyLines := renderJudgmentHistory(l, stats)
 	lines = append(lines, historyLines...)
 	lines = append(lines, "") // 空行
 
 	// == 中上部：舞蹈小人 ==
-	danceLines := renderDanceCharacter(danceFrame, effectInfo.LastJudgment)
+	danceLines := renderDanceCharacter(l, danceFrame, effectInfo.LastJudgment)
 	lines = append(lines, danceLines...)
 	lines = append(lines, "") // 空行
 
 	// == 中部：左右分栏（单词队列 ｜ 节奏条）==
-	splitLines := renderWordQueueAndBar(wordQueue, currentWordIndex, userInput, rhythmBar, effectInfo)
+	splitLines := renderWordQueueAndBar(l, wordQueue, currentWordIndex, userInput, rhythmBar, effectInfo)
 	lines = append(lines, splitLines...)
 
 	// 添加底部空行增加高度，使其与结算界面一致（约18-20行总高度）
 	lines = append(lines, "", "")
 
-	return wordBoxStyle.Render(strings.Join(lines, "\n"))
+	// 终端较矮时去掉空行：统计栏 2 + 框 6 + 提示 1
+	return l.box().Render(strings.Join(squeeze(lines, l.height-9), "\n"))
 }
 
 // renderJudgmentHistory 渲染判定历史记录（两行）
 // 使用彩色方块显示，按等级统计后显示
-func renderJudgmentHistory(stats RhythmDanceStats) []string {
+func renderJudgmentHistory(l layout, stats RhythmDanceStats) []string {
 	var lines []string
 
 	// 第一行：颜色说明
@@ -144,14 +160,14 @@ func renderJudgmentHistory(stats RhythmDanceStats) []string {
 		missStyle.Render("■ Miss")
 
 	lines = append(lines, lipgloss.NewStyle().
-		Width(contentWidth-8).
+		Width(l.inner()).
 		Align(lipgloss.Center).
 		Render(legend))
 
 	// 第二行：统计各等级数量后显示
 	if len(stats.JudgmentHistory) == 0 {
 		lines = append(lines, lipgloss.NewStyle().
-			Width(contentWidth-8).
+			Width(l.inner()).
 			Align(lipgloss.Center).
 			Foreground(lipgloss.Color("240")).
 			Render("(empty)"))
@@ -166,13 +182,13 @@ func renderJudgmentHistory(stats RhythmDanceStats) []string {
 
 	for _, judgment := range stats.JudgmentHistory {
 		switch judgment {
-		case "Perfect":
+		case game.JudgmentPerfect:
 			perfectCount++
-		case "Nice":
+		case game.JudgmentNice:
 			niceCount++
-		case "OK":
+		case game.JudgmentOK:
 			okCount++
-		case "Miss":
+		case game.JudgmentMiss:
 			missCount++
 		}
 	}
@@ -195,7 +211,7 @@ func renderJudgmentHistory(stats RhythmDanceStats) []string {
 
 	historyBar := strings.Join(barParts, "")
 	lines = append(lines, lipgloss.NewStyle().
-		Width(contentWidth-8).
+		Width(l.inner()).
 		Align(lipgloss.Center).
 		Render(historyBar))
 
@@ -203,7 +219,7 @@ func renderJudgmentHistory(stats RhythmDanceStats) []string {
 }
 
 // renderDanceCharacter 渲染舞蹈小人（带颜色效果）
-func renderDanceCharacter(danceFrame string, lastJudgment string) []string {
+func renderDanceCharacter(l layout, danceFrame string, lastJudgment game.Judgment) []string {
 	var lines []string
 
 	// 将舞蹈帧按行分割
@@ -212,20 +228,20 @@ func renderDanceCharacter(danceFrame string, lastJudgment string) []string {
 	// 根据判定类型选择颜色（与判定颜色统一）
 	var characterStyle lipgloss.Style
 	switch lastJudgment {
-	case "Perfect":
+	case game.JudgmentPerfect:
 		// Perfect: 金色
 		characterStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("226")).
 			Bold(true)
-	case "Nice":
+	case game.JudgmentNice:
 		// Nice: 蓝色
 		characterStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("33"))
-	case "OK":
+	case game.JudgmentOK:
 		// OK: 绿色
 		characterStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("46"))
-	case "Miss":
+	case game.JudgmentMiss:
 		// Miss: 红色
 		characterStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("196"))
@@ -237,10 +253,7 @@ func renderDanceCharacter(danceFrame string, lastJudgment string) []string {
 
 	// 居中显示
 	for _, line := range frameLines {
-		centeredLine := lipgloss.NewStyle().
-			Width(contentWidth - 8).
-			Align(lipgloss.Center).
-			Render(characterStyle.Render(line))
+		centeredLine := l.centerInner(characterStyle.Render(line))
 		lines = append(lines, centeredLine)
 	}
 
@@ -254,6 +267,7 @@ func renderDanceCharacter(danceFrame string, lastJudgment string) []string {
 
 // renderWordQueueAndBar 渲染左右分栏：单词队列（左半屏，靠右对齐）｜ 节奏条（右半屏，仅显示在中间行）
 func renderWordQueueAndBar(
+	l layout,
 	wordQueue []string,
 	currentWordIndex int,
 	userInput string,
@@ -268,16 +282,18 @@ func renderWordQueueAndBar(
 		return []string{"Error: WordQueue length must be 5"}
 	}
 
-	// 左半屏宽度和右半屏宽度
-	const leftWidth = contentWidth/2 - 4
-	const rightWidth = contentWidth/2 - 4
+	// 左半屏宽度和右半屏宽度（去掉框的左右边距和中间 5 列分隔）
+	// 窄终端上单词队列保持完整宽度，节奏条变短
+	leftWidth := max((l.text()-5)/2, wordQueueWidth)
+	rightWidth := min(leftWidth, l.text()-5-leftWidth)
 
 	// 颜色配置（从上到下：深灰 → 浅灰 → 白色 → 浅灰 → 深灰）
 	colors := []string{"#444444", "#888888", "#FFFFFF", "#888888", "#444444"}
 
 	// 渲染节奏条内容（包含 Perfect 特效、节奏条、箭头、其他判定特效），共5行
 	// 结构：[0-1: Perfect特效2行, 2: 节奏条主体1行, 3: 箭头1行, 4: 其他判定1行]
-	rhythmBarLines := renderRhythmBarWithEffects(rhythmBar, effectInfo)
+	// 节奏条随右半屏拉伸
+	rhythmBarLines := renderRhythmBarWithEffects(rhythmBar, effectInfo, rightWidth-1)
 
 	// 首先渲染节奏条的前2行（Perfect特效），左侧空白
 	for j := 0; j < 2; j++ {
@@ -357,12 +373,10 @@ func renderWordQueueAndBar(
 
 // renderRhythmBarWithEffects 渲染完整的节奏条内容（包含判定特效、节奏条、箭头）
 // 返回固定5行内容：[上方特效2行, 节奏条1行, 箭头1行, 下方特效1行]
-func renderRhythmBarWithEffects(rhythmBar RhythmBarInfo, effectInfo JudgmentEffectInfo) []string {
+// barWidth 为显示宽度；判定区域按 RhythmBarInfo 中的宽度换算，与实际判定一致
+func renderRhythmBarWithEffects(rhythmBar RhythmBarInfo, effectInfo JudgmentEffectInfo, barWidth int) []string {
 	var lines []string
 
-	// 节奏条宽度
-	const barWidth = 35
-
 	// 计算指针和黄金点的位置
 	pointerPos := int(rhythmBar.PointerPosition * float64(barWidth))
 	goldenPos := int(rhythmBar.GoldenRatio * float64(barWidth))
@@ -378,7 +392,7 @@ func renderRhythmBarWithEffects(rhythmBar RhythmBarInfo, effectInfo JudgmentEffe
 	var topEffect []string  // 上方特效（2行）
 	var bottomEffect string // 下方特效（1行）
 
-	if effectInfo.LastJudgment != "" {
+	if effectInfo.LastJudgment != game.JudgmentNone {
 		elapsed := time.Since(effectInfo.LastJudgmentTime)
 		if elapsed < 1*time.Second {
 			topEffect, bottomEffect = renderJudgmentEffects(effectInfo.LastJudgment, elapsed)
@@ -397,24 +411,24 @@ func renderRhythmBarWithEffects(rhythmBar RhythmBarInfo, effectInfo JudgmentEffe
 	// 渲染节奏条主体（1行）
 	var barChars []string
 	for i := 0; i < barWidth; i++ {
-		// 计算距离黄金点的字符数（绝对距离）
-		charDistance := int(math.Abs(float64(i - goldenPos)))
+		// 计算该列中心与黄金点的距离（节奏条总长为 1）
+		distance := math.Abs((float64(i)+0.5)/float64(barWidth) - rhythmBar.GoldenRatio)
 
-		// 根据字符距离选择颜色，与判定颜色一致
-		// 模式: --4433223344--
-		//       Miss OK Nice Perfect Nice OK Miss
+		// 根据距离选择颜色，与判定颜色一致
+		// 模式: Miss | OK | Nice | Perfect | Nice | OK | Miss
+		// 黄金点所在的列总是显示为 Perfect，区域再窄也看得到
 		var cellStyle lipgloss.Style
-		if charDistance == 0 {
-			// Perfect 区域（黄金点本身）：金色
+		if i == goldenPos || distance <= rhythmBar.PerfectZone {
+			// Perfect 区域：金色
 			cellStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("226")) // 金色
-		} else if charDistance <= 2 {
-			// Nice 区域（1-2个字符距离）：蓝色
+		} else if distance <= rhythmBar.NiceZone {
+			// Nice 区域：蓝色
 			cellStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("33")) // 蓝色
-		} else if charDistance <= 4 {
-			// OK 区域（3-4个字符距离）：绿色
+		} else if distance <= rhythmBar.OKZone {
+			// OK 区域：绿色
 			cellStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("46")) // 绿色
 		} else {
-			// Miss 区域（>4个字符距离）：红色
+			// Miss 区域：红色
 			cellStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")) // 红色
 		}
 
@@ -449,7 +463,7 @@ func renderRhythmBarWithEffects(rhythmBar RhythmBarInfo, effectInfo JudgmentEffe
 
 // renderJudgmentArrow 渲染指向上次判定位置的箭头，随时间渐渐消失
 func renderJudgmentArrow(effectInfo JudgmentEffectInfo, barWidth int) string {
-	if effectInfo.LastJudgment == "" {
+	if effectInfo.LastJudgment == game.JudgmentNone {
 		return "" // 没有判定记录，不显示箭头
 	}
 
@@ -476,13 +490,13 @@ func renderJudgmentArrow(effectInfo JudgmentEffectInfo, barWidth int) string {
 			// 根据判定类型选择箭头颜色
 			var arrowColor lipgloss.Color
 			switch effectInfo.LastJudgment {
-			case "Perfect":
+			case game.JudgmentPerfect:
 				arrowColor = lipgloss.Color("226") // 金色
-			case "Nice":
+			case game.JudgmentNice:
 				arrowColor = lipgloss.Color("33") // 蓝色
-			case "OK":
+			case game.JudgmentOK:
 				arrowColor = lipgloss.Color("46") // 绿色
-			case "Miss":
+			case game.JudgmentMiss:
 				arrowColor = lipgloss.Color("196") // 红色
 			default:
 				arrowColor = lipgloss.Color("255") // 白色
@@ -507,6 +521,9 @@ func renderJudgmentArrow(effectInfo JudgmentEffectInfo, barWidth int) string {
 	return strings.Join(arrowChars, "")
 }
 
+// wordQueueWidth 单词队列每行的宽度："Input:[" + 20 列单词区 + "]"
+const wordQueueWidth = 7 + 20 + 1
+
 // renderCurrentWordWithInput 渲染当前单词行，显示 "Input:[    word]" 固定宽度20字符，带颜色编码
 func renderCurrentWordWithInput(targetWord string, userInput string) string {
 	const wordFieldWidth = 20 // 单词显示区域固定宽度
@@ -518,12 +535,13 @@ func renderCurrentWordWithInput(targetWord string, userInput string) string {
 	// 渲染目标单词的每个字符，根据用户输入进行颜色编码
 	var wordChars []string
 
-	for i, ch := range targetWord {
+	typed := []rune(userInput) // 按字符而不是字节对齐
+	for i, ch := range []rune(targetWord) {
 		charStr := string(ch)
 
-		if i < len(userInput) {
+		if i < len(typed) {
 			// 有对应的输入字符
-			if userInput[i] == byte(ch) {
+			if typed[i] == ch {
 				// 正确：绿色
 				wordChars = append(wordChars, lipgloss.NewStyle().
 					Foreground(lipgloss.Color("46")).
@@ -548,7 +566,7 @@ func renderCurrentWordWithInput(targetWord string, userInput string) string {
 	renderedWord := strings.Join(wordChars, "")
 
 	// 计算需要补齐的空格数（单词左侧填充空格，使总宽度为 wordFieldWidth）
-	paddingCount := wordFieldWidth - len(targetWord)
+	paddingCount := wordFieldWidth - textWidth(targetWord)
 	if paddingCount < 0 {
 		paddingCount = 0 // 如果单词超长，不补齐
 	}
@@ -575,7 +593,7 @@ func renderWordAligned(word string, color string) string {
 	prefix := strings.Repeat(" ", prefixWidth)
 
 	// 计算单词左侧填充（右对齐到 wordFieldWidth）
-	paddingCount := wordFieldWidth - len(word)
+	paddingCount := wordFieldWidth - textWidth(word)
 	if paddingCount < 0 {
 		paddingCount = 0
 	}
@@ -593,17 +611,17 @@ func renderWordAligned(word string, color string) string {
 
 // renderJudgmentEffects 渲染判定特效（上下对称设计）
 // 返回：上方特效（2行）和下方特效（1行）
-func renderJudgmentEffects(judgment string, elapsed time.Duration) ([]string, string) {
+func renderJudgmentEffects(judgment game.Judgment, elapsed time.Duration) ([]string, string) {
 	ms := elapsed.Milliseconds()
 
 	switch judgment {
-	case "Perfect":
+	case game.JudgmentPerfect:
 		return renderPerfectEffect(ms)
-	case "Nice":
+	case game.JudgmentNice:
 		return renderNiceEffect(ms)
-	case "OK":
+	case game.JudgmentOK:
 		return renderOKEffect(ms)
-	case "Miss":
+	case game.JudgmentMiss:
 		return renderMissEffect(ms)
 	default:
 		return []string{"", ""}, ""
@@ -722,46 +740,60 @@ func renderMissEffect(ms int64) ([]string, string) {
 }
 
 // renderJudgmentCounts 渲染判定计数
-func renderJudgmentCounts(lastJudgment string, lastJudgmentTime time.Time) string {
+func renderJudgmentCounts(lastJudgment game.Judgment, lastJudgmentTime time.Time) string {
 	// 这个函数将在完整版中显示 Perfect/Nice/OK/Miss 的计数
 	// 现在先返回空，稍后实现
 	return ""
 }
 
 // RenderRhythmDanceResults 渲染节奏舞蹈模式结果页面
-func RenderRhythmDanceResults(stats RhythmDanceStats, selectedOption int, animFrame int) string {
+func RenderRhythmDanceResults(vp Viewport, stats RhythmDanceStats, selectedOption int, animFrame int, record RecordInfo) string {
+	l := newLayout(vp)
 	var s strings.Builder
 
 	// === TOP: Header ===
-	header := fmt.Sprintf("Total Score: %d  │  Max Combo: %d", stats.TotalScore, stats.MaxCombo)
+	header := fmt.Sprintf("Total Score: %d  │  Max Combo: %d  │  Net WPM: %.1f", stats.TotalScore, stats.MaxCombo, stats.NetWPM)
 	headerStyled := headerStyle.Render(header)
-	s.WriteString(lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(headerStyled))
+	s.WriteString(l.center(headerStyled))
 	s.WriteString("\n")
 
 	// === MIDDLE: Statistics ===
-	statsArea := renderRhythmDanceResultsArea(stats, selectedOption, animFrame)
+	statsArea := renderRhythmDanceResultsArea(l, stats, selectedOption, animFrame, record)
 	s.WriteString(statsArea)
 	s.WriteString("\n")
 
 	// === BOTTOM: Hints ===
-	hints := inputBoxStyle.Render("[↑↓] Select  │  [Enter] Confirm  │  [ESC] Exit")
+	hints := l.hints([]string{"[↑↓] Select", "[Enter] Confirm", "[H] Key Heatmap", "[ESC] Exit"}, 1)
 	s.WriteString(hints)
 	s.WriteString("\n")
 
 	return s.String()
 }
 
+// rhythmDanceResultsFullHeight 完整结果页所需的屏幕高度；更矮时省略空行和顶部已显示的数据
+const rhythmDanceResultsFullHeight = 36
+
 // renderRhythmDanceResultsArea 渲染结果统计区域
-func renderRhythmDanceResultsArea(stats RhythmDanceStats, selectedOption int, animFrame int) string {
+func renderRhythmDanceResultsArea(l layout, stats RhythmDanceStats, selectedOption int, animFrame int, record RecordInfo) string {
 	var content strings.Builder
+	compact := l.height < rhythmDanceResultsFullHeight
+	pad := l.indent()
+	shift := l.shortfall()
+	col := 50 - shift
+	separator := separatorStyle.Render(strings.Repeat("━", 69-shift))
 
 	// Title
-	content.WriteString(fmt.Sprintf("%60s\n", titleStyle.Render("RHYTHM DANCE RESULTS")))
-	content.WriteString("    " + separatorStyle.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━") + "\n")
+	content.WriteString(pad + fmt.Sprintf("%*s\n", 60-shift, titleStyle.Render("RHYTHM DANCE RESULTS")))
+	if line := renderRecordLine(l, record, animFrame); line != "" {
+		content.WriteString(line + "\n")
+	}
+	content.WriteString(pad + "    " + separator + "\n")
 
 	// Statistics
-	content.WriteString(fmt.Sprintf("%51s\n", titleStyle.Render("Performance:")))
-	content.WriteString("\n")
+	if !compact {
+		content.WriteString(pad + fmt.Sprintf("%*s\n", 51-shift, titleStyle.Render("Performance:")))
+		content.WriteString("\n")
+	}
 
 	// 判定计数
 	totalJudgments := stats.PerfectCount + stats.NiceCount + stats.OKCount + stats.MissCount
@@ -770,65 +802,56 @@ func renderRhythmDanceResultsArea(stats RhythmDanceStats, selectedOption int, an
 		accuracy = float64(stats.PerfectCount+stats.NiceCount+stats.OKCount) / float64(totalJudgments) * 100
 	}
 
-	content.WriteString(fmt.Sprintf("%50s %s\n",
+	content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
 		statItemStyle.Render("Perfect:"),
 		lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Bold(true).Render(fmt.Sprintf("%7d", stats.PerfectCount))))
-	content.WriteString(fmt.Sprintf("%50s %s\n",
+	content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
 		statItemStyle.Render("Nice:"),
 		lipgloss.NewStyle().Foreground(lipgloss.Color("33")).Bold(true).Render(fmt.Sprintf("%7d", stats.NiceCount))))
-	content.WriteString(fmt.Sprintf("%50s %s\n",
+	content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
 		statItemStyle.Render("OK:"),
 		statValueStyle.Render(fmt.Sprintf("%7d", stats.OKCount))))
-	content.WriteString(fmt.Sprintf("%50s %s\n",
+	content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
 		statItemStyle.Render("Miss:"),
 		lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("%7d", stats.MissCount))))
 
-	content.WriteString("\n")
-	content.WriteString(fmt.Sprintf("%50s %s\n",
-		statItemStyle.Render("Completed Words:"),
-		statValueStyle.Render(fmt.Sprintf("%7d", stats.CompletedWords))))
-	content.WriteString(fmt.Sprintf("%50s %s\n",
-		statItemStyle.Render("Total Score:"),
-		statValueStyle.Render(fmt.Sprintf("%7d", stats.TotalScore))))
-	content.WriteString(fmt.Sprintf("%50s %s\n",
-		statItemStyle.Render("Max Combo:"),
-		statValueStyle.Render(fmt.Sprintf("%7d", stats.MaxCombo))))
-	content.WriteString(fmt.Sprintf("%50s %s\n",
+	if !compact {
+		// 总分和最大连击已显示在顶部，空间不够时省略
+		content.WriteString("\n")
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Completed Words:"),
+			statValueStyle.Render(fmt.Sprintf("%7d", stats.CompletedWords))))
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Total Score:"),
+			statValueStyle.Render(fmt.Sprintf("%7d", stats.TotalScore))))
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Max Combo:"),
+			statValueStyle.Render(fmt.Sprintf("%7d", stats.MaxCombo))))
+	}
+	content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
 		statItemStyle.Render("Accuracy:"),
 		statValueStyle.Render(fmt.Sprintf("%6.2f%%", accuracy))))
+	if !compact {
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Net WPM:"),
+			statValueStyle.Render(fmt.Sprintf("%7.1f", stats.NetWPM))))
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Raw WPM:"),
+			statValueStyle.Render(fmt.Sprintf("%7.1f", stats.RawWPM))))
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Consistency:"),
+			statValueStyle.Render(fmt.Sprintf("%6.1f%%", stats.Consistency))))
+	}
 
 	// Menu
-	content.WriteString("\n")
-	content.WriteString("    " + separatorStyle.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━") + "\n")
-	content.WriteString("\n")
-
-	options := []string{"Restart", "Select Mode", "Main Menu"}
-	selectedStyle := lipgloss.NewStyle().
-		Foreground(getRandomMenuColor(animFrame)).
-		Bold(true)
-
-	for i, opt := range options {
-		var optionDisplay string
-		if i == selectedOption {
-			optionDisplay = "> " + opt + " <"
-		} else {
-			optionDisplay = "  " + opt + "  "
-		}
-
-		var styledText string
-		if i == selectedOption {
-			styledText = selectedStyle.Render(optionDisplay)
-		} else {
-			styledText = menuNormalStyle.Render(optionDisplay)
-		}
-
-		alignedText := lipgloss.NewStyle().
-			Width(contentWidth - 8).
-			Align(lipgloss.Center).
-			Render(styledText)
-
-		content.WriteString("  " + alignedText + "\n")
+	if !compact {
+		content.WriteString("\n")
+		content.WriteString(pad + "    " + separator + "\n")
+		content.WriteString("\n")
 	}
 
-	return wordBoxStyle.Render(content.String())
+	options := []string{"Restart", "Watch Replay", "Select Mode", "Main Menu"}
+	content.WriteString(renderResultsMenu(l, options, selectedOption, animFrame))
+
+	return l.box().Render(content.String())
 }
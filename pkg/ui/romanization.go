@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	// romanTargetStyle 罗马字模式中待输入的中日文
+	romanTargetStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("255")).
+				Bold(true)
+
+	// currentSyllableStyle 正在输入的音节中还没输入的部分
+	currentSyllableStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("252")).
+				Underline(true)
+)
+
+// RenderRomanizationGame 渲染罗马字模式游戏界面：每个词的中日文显示在读音上方
+func RenderRomanizationGame(vp Viewport, words []WordInfo, highlightedIndices []int, input string, stats GameStats,
+	remainingWords int) string {
+	l := newLayout(vp)
+	var s strings.Builder
+
+	// === 顶部：状态栏（与经典模式相同）===
+	s.WriteString(renderStatusBar(l, stats, remainingWords))
+	s.WriteString("\n")
+
+	// === 中部：单词区域，每个词占两行 ===
+	wordArea := renderWordArea(l, max(l.rows(15, minWordRows)/2, 2), words, highlightedIndices, input)
+	s.WriteString(wordArea)
+	s.WriteString("\n")
+
+	// === 底部：输入区域 ===
+	s.WriteString(renderInputArea(l, input))
+	s.WriteString("\n")
+
+	s.WriteString(l.hintLine([]string{"[ESC] Pause", "Type the reading without tones (ü = v)"}, 1))
+	s.WriteString("\n")
+
+	return s.String()
+}
+
+// renderRomanizedWord 渲染罗马字模式的一个词，返回中日文和读音两行
+// 读音按音节高亮输入进度：已输完的音节为绿色，正在输入的音节加下划线；
+// 中日文的字数与音节数相同时，已输完的音节对应的字也变为绿色
+func renderRomanizedWord(w WordInfo, highlighted bool, input string) (string, string) {
+	reading := strings.Join(w.Syllables, " ")
+	if w.Completed {
+		target, done := w, w
+		target.Text, done.Text = w.Target, reading
+		return renderCompletedWordAnimation(target), renderCompletedWordAnimation(done)
+	}
+	if !highlighted || input == "" {
+		return romanTargetStyle.Render(w.Target), wordStyle.Render(reading)
+	}
+
+	// 输入是 Text 的前缀（只含 a-z），逐个音节消耗
+	rest := input
+	typed := 0
+	parts := make([]string, len(w.Syllables))
+	for i, syl := range w.Syllables {
+		switch {
+		case len(rest) >= len(syl):
+			parts[i] = highlightStyle.Render(syl)
+			rest = rest[len(syl):]
+			typed++
+		case i == typed:
+			parts[i] = highlightStyle.Render(syl[:len(rest)]) + currentSyllableStyle.Render(syl[len(rest):])
+			rest = ""
+		default:
+			parts[i] = wordStyle.Render(syl)
+		}
+	}
+
+	chars := []rune(w.Target)
+	var target string
+	switch {
+	case typed == len(w.Syllables):
+		target = highlightStyle.Render(w.Target)
+	case len(chars) == len(w.Syllables):
+		target = highlightStyle.Render(string(chars[:typed])) + romanTargetStyle.Render(string(chars[typed:]))
+	default:
+		target = romanTargetStyle.Render(w.Target)
+	}
+
+	return target, strings.Join(parts, " ")
+}
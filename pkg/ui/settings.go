@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// settingsOverhead 设置列表以外占用的行数：标题 2 + 框 6 + 滚动提示 2 + 按钮 1 + 状态 1 + 提示 4
+const settingsOverhead = 16
+
+// settingsSliderWidth 配比滑块宽度
+const settingsSliderWidth = 20
+
+var (
+	settingsErrorStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("196"))
+
+	settingsDisabledStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("240"))
+)
+
+// SettingRow 设置界面中的一行
+type SettingRow struct {
+	Label  string
+	Value  string  // 显示值
+	Slider float64 // 配比滑块的填充比例（0-1），负数表示不显示滑块
+	Path   bool    // 文件路径或文本项（回车编辑）
+	Error  string  // 校验错误，空表示合法
+}
+
+// SettingsView 设置界面的显示数据
+type SettingsView struct {
+	Rows     []SettingRow
+	Selected int    // 选中的行；len(Rows) 为 Save，len(Rows)+1 为 Cancel
+	Editing  bool   // 正在编辑选中的路径
+	Input    string // 路径输入缓冲
+	Valid    bool   // 所有设置都合法（可以保存）
+	Status   string // 提示信息
+	SavePath string // 配置文件保存位置
+}
+
+// RenderSettings renders the settings screen
+func RenderSettings(vp Viewport, view SettingsView, animFrame int) string {
+	l := newLayout(vp)
+	var s strings.Builder
+
+	// TOP: Header
+	header := headerStyle.Render("Settings")
+	s.WriteString(l.center(header))
+	s.WriteString("\n")
+
+	// MIDDLE: Settings list
+	s.WriteString(renderSettingsList(l, view, animFrame))
+	s.WriteString("\n")
+
+	// BOTTOM: Hints
+	hints := l.hints([]string{"[↑↓] Select", "[←→] Adjust", "[Enter] Edit/Confirm", "[ESC] Discard"}, 1)
+	if view.Editing {
+		hints = l.hints([]string{"[Enter] Apply", "[ESC] Cancel edit"})
+	}
+	s.WriteString(hints)
+	s.WriteString("\n")
+
+	return s.String()
+}
+
+// renderSettingsList renders the scrolling list of settings and the buttons
+func renderSettingsList(l layout, view SettingsView, animFrame int) string {
+	var lines []string
+	selectedStyle := lipgloss.NewStyle().Foreground(getRandomMenuColor(animFrame)).Bold(true)
+
+	// 滚动窗口：保持选中行可见
+	visible := l.rows(settingsOverhead, 3)
+	start := 0
+	if view.Selected >= visible {
+		start = min(view.Selected, len(view.Rows)-1) - visible + 1
+	}
+	end := min(start+visible, len(view.Rows))
+
+	if start > 0 {
+		lines = append(lines, hintStyle.Render("  ↑ more"))
+	} else {
+		lines = append(lines, "")
+	}
+
+	for i := start; i < end; i++ {
+		row := view.Rows[i]
+		selected := i == view.Selected
+
+		cursor := "  "
+		label := menuNormalStyle.Render(fmt.Sprintf("%-28s", row.Label))
+		if selected {
+			cursor = selectedStyle.Render("> ")
+			label = selectedStyle.Render(fmt.Sprintf("%-28s", row.Label))
+		}
+
+		value := statValueStyle.Render(row.Value)
+		switch {
+		case selected && view.Editing:
+			value = inputStyle.Render(view.Input + "_")
+		case row.Slider >= 0:
+			value = renderSettingsSlider(row.Slider) + " " + value
+		case selected && !row.Path:
+			value = hintStyle.Render("◀ ") + value + hintStyle.Render(" ▶")
+		}
+
+		line := cursor + label + value
+		if row.Error != "" {
+			line += "  " + settingsErrorStyle.Render("✗ "+row.Error)
+		}
+		lines = append(lines, line)
+	}
+
+	if end < len(view.Rows) {
+		lines = append(lines, hintStyle.Render("  ↓ more"))
+	} else {
+		lines = append(lines, "")
+	}
+
+	// Buttons
+	save := "  Save  "
+	cancel := "  Cancel  "
+	if view.Selected == len(view.Rows) {
+		save = "> Save <"
+	}
+	if view.Selected == len(view.Rows)+1 {
+		cancel = "> Cancel <"
+	}
+	switch {
+	case view.Selected == len(view.Rows) && view.Valid:
+		save = selectedStyle.Render(save)
+	case view.Valid:
+		save = menuNormalStyle.Render(save)
+	default:
+		save = settingsDisabledStyle.Render(save)
+	}
+	if view.Selected == len(view.Rows)+1 {
+		cancel = selectedStyle.Render(cancel)
+	} else {
+		cancel = menuNormalStyle.Render(cancel)
+	}
+	lines = append(lines, l.centerInner(save+"    "+cancel))
+
+	// Status
+	switch {
+	case view.Status != "":
+		lines = append(lines, settingsErrorStyle.Render("  "+view.Status))
+	case view.SavePath != "":
+		lines = append(lines, statsStyle.Render("  Saves to "+view.SavePath))
+	}
+
+	return l.box().Render(strings.Join(lines, "\n"))
+}
+
+// renderSettingsSlider renders a ratio slider
+func renderSettingsSlider(fill float64) string {
+	filled := int(fill*settingsSliderWidth + 0.5)
+	filled = max(0, min(filled, settingsSliderWidth))
+	return statValueStyle.Render(strings.Repeat("█", filled)) +
+		settingsDisabledStyle.Render(strings.Repeat("░", settingsSliderWidth-filled))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -48,6 +49,11 @@ var (
 		Foreground(lipgloss.Color("226")). // Bright yellow
 		Bold(true)
 
+	// Ghost style: words the ghost racer has already eliminated
+	ghostWordStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("135")).
+		Italic(true)
+
 	// Fading styles (for gradual transition)
 	fadingStyle1 = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("46")). // Bright green
@@ -101,21 +107,50 @@ var (
 
 // Layout constants
 const (
-	// Fixed width for all content areas to ensure alignment
+	// Default width for content areas (the layout each screen was designed for);
+	// the actual width follows the terminal, see layout.go
 	contentWidth = 80
+
+	// Word grid: each word gets a fixed-width column; rows follow the terminal height
+	wordColumnWidth = 18
+	minWordRows     = 3
 )
 
 // GameStats game statistics
 type GameStats struct {
-	TotalKeystrokes  int
-	ValidKeystrokes  int
-	CorrectChars     int
-	WordsCompleted   int
-	TotalLetters     int
-	ElapsedSeconds   float64
-	LettersPerSecond float64
-	WordsPerSecond   float64
-	AccuracyPercent  float64
+	TotalKeystrokes   int
+	ValidKeystrokes   int
+	CorrectChars      int
+	WordsCompleted    int
+	TotalLetters      int
+	ElapsedSeconds    float64
+	LettersPerSecond  float64
+	WordsPerSecond    float64
+	AccuracyPercent   float64
+	NetWPM            float64    // words per minute (5 characters) minus uncorrected errors
+	RawWPM            float64    // words per minute (5 characters) including errors
+	CorrectedErrors   int        // wrong characters removed with backspace
+	UncorrectedErrors int        // wrong characters that were never fixed
+	Consistency       float64    // 0-100, from the per-second speed variation
+	Score             *ScoreInfo // 单词模式的得分，不计分的模式为 nil
+}
+
+// ScoreInfo word mode score for the status bar and results screen
+type ScoreInfo struct {
+	Score      int
+	Streak     int     // current run of clean eliminations
+	MaxStreak  int     // longest run of clean eliminations
+	Multiplier float64 // multiplier for the current streak
+	Penalties  int     // wrong Enter presses
+}
+
+// RecordInfo personal best information for the results screen
+type RecordInfo struct {
+	Rank        int     // 1-based rank on the leaderboard, 0 if not ranked
+	IsNewRecord bool    // true when the result beats the previous best
+	Value       float64 // the value used for ranking
+	Unit        string  // unit of Value, e.g. "s", "words", "pts"
+	Label       string  // leaderboard label
 }
 
 // WordInfo contains word display information
@@ -123,12 +158,23 @@ type WordInfo struct {
 	Text        string
 	Completed   bool
 	CompletedAt time.Time
+	Ghosted     bool     // 幽灵对手已消除该单词（极速模式赛跑）
+	Target      string   // 罗马字模式：显示在读音上方的中日文
+	Syllables   []string // 罗马字模式：Text 按音节切分
+}
+
+// GhostInfo 幽灵对手的实时进度
+type GhostInfo struct {
+	Active     bool    // 是否有幽灵对手
+	Words      int     // 幽灵已消除的单词数
+	Finished   bool    // 幽灵是否已完成
+	FinishTime float64 // 幽灵完成用时（秒）
 }
 
 // WelcomeAnimationState tracks the welcome screen animation state
 type WelcomeAnimationState struct {
 	Frame              int
-	SelectedOption     int  // 0 for start, 1 for about
+	SelectedOption     int  // 0 for start, 1 for settings, 2 for about, 3 for quit
 	BulletActive       bool // whether a bullet is currently flying
 	BulletX            int  // bullet column position
 	BulletRow          int  // which line the bullet is on (relative to content box)
@@ -144,21 +190,22 @@ type TaglineInfo struct {
 }
 
 // RenderWelcome renders welcome screen with unified style
-func RenderWelcome(state *WelcomeAnimationState, animFrame int) string {
+func RenderWelcome(vp Viewport, state *WelcomeAnimationState, animFrame int) string {
+	l := newLayout(vp)
 	var s strings.Builder
 
 	// TOP: Header
 	header := headerStyle.Render("Word Killer")
-	s.WriteString(lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(header))
+	s.WriteString(l.center(header))
 	s.WriteString("\n")
 
 	// MIDDLE: Content (tagline + menu + bullet animation)
-	content := renderWelcomeContent(state.SelectedOption, animFrame, state)
+	content := renderWelcomeContent(l, state.SelectedOption, animFrame, state)
 	s.WriteString(content)
 	s.WriteString("\n")
 
 	// BOTTOM: Hints
-	hints := inputBoxStyle.Render("[↑↓] Select  │  [Enter] Confirm  │  [ESC] Quit")
+	hints := l.inputBox().Render("[↑↓] Select  │  [Enter] Confirm  │  [ESC] Quit")
 	s.WriteString(hints)
 	s.WriteString("\n")
 
@@ -166,22 +213,22 @@ func RenderWelcome(state *WelcomeAnimationState, animFrame int) string {
 }
 
 // renderWelcomeContent renders the welcome screen content area
-func renderWelcomeContent(selectedOption int, animFrame int, state *WelcomeAnimationState) string {
+func renderWelcomeContent(l layout, selectedOption int, animFrame int, state *WelcomeAnimationState) string {
 	const totalLines = 12 // Total lines in the content box
 	var lines []string
 
 	// Line 0: Version (top-left)
 	topline := lipgloss.NewStyle().Foreground(lipgloss.Color("117")).Render("V1.0.0")
-	versionLine := lipgloss.NewStyle().Width(contentWidth-8).
+	versionLine := lipgloss.NewStyle().Width(l.inner()).
 		Align(lipgloss.Left, lipgloss.Top).
 		Render(topline)
 	lines = append(lines, addBulletToLine(versionLine, 0, state))
 
 	// Line 1: Empty
-	lines = append(lines, addBulletToLine(strings.Repeat(" ", contentWidth-8), 1, state))
+	lines = append(lines, addBulletToLine(strings.Repeat(" ", l.inner()), 1, state))
 
-	// Lines 2-3: Menu options (Start, About)
-	options := []string{"Start", "About"}
+	// Lines 2-5: Menu options (Start, Settings, About, Quit)
+	options := []string{"Start", "Settings", "About", "Quit"}
 	selectedStyle := lipgloss.NewStyle().Foreground(getRandomMenuColor(animFrame)).Bold(true)
 
 	for i, opt := range options {
@@ -199,18 +246,15 @@ func renderWelcomeContent(selectedOption int, animFrame int, state *WelcomeAnima
 			styledText = menuNormalStyle.Render(optionDisplay)
 		}
 
-		alignedText := lipgloss.NewStyle().
-			Width(contentWidth - 8).
-			Align(lipgloss.Center).
-			Render(styledText)
+		alignedText := l.centerInner(styledText)
 
 		lineIndex := 2 + i
 		lines = append(lines, addBulletToLine("  "+alignedText, lineIndex, state))
 	}
 
-	// Lines 4-9: Empty (middle spacing)
-	for i := 4; i < 10; i++ {
-		lines = append(lines, addBulletToLine(strings.Repeat(" ", contentWidth-8), i, state))
+	// Lines 6-9: Empty (middle spacing)
+	for i := 2 + len(options); i < 10; i++ {
+		lines = append(lines, addBulletToLine(strings.Repeat(" ", l.inner()), i, state))
 	}
 
 	// Line 10: Tagline with explosion effect (bottom-right)
@@ -226,15 +270,15 @@ func renderWelcomeContent(selectedOption int, animFrame int, state *WelcomeAnima
 		taglineRendered = lipgloss.NewStyle().Foreground(lipgloss.Color("117")).Render(taglineText)
 	}
 
-	taglineLine := lipgloss.NewStyle().Width(contentWidth-8).
+	taglineLine := lipgloss.NewStyle().Width(l.inner()).
 		Align(lipgloss.Right, lipgloss.Bottom).
 		Render(taglineRendered)
 	lines = append(lines, addBulletToLine(taglineLine, 10, state))
 
 	// Line 11: Empty
-	lines = append(lines, addBulletToLine(strings.Repeat(" ", contentWidth-8), 11, state))
+	lines = append(lines, addBulletToLine(strings.Repeat(" ", l.inner()), 11, state))
 
-	return wordBoxStyle.Render(strings.Join(lines, "\n"))
+	return l.box().Render(strings.Join(lines, "\n"))
 }
 
 // addBulletToLine adds bullet to a line if the bullet is on that line
@@ -267,21 +311,23 @@ func stripAnsi(s string) string {
 }
 
 // RenderGame renders game screen with professional layout
-func RenderGame(words []WordInfo, highlightedIndices []int, input string, stats GameStats, remainingWords int) string {
+func RenderGame(vp Viewport, words []WordInfo, highlightedIndices []int, input string, stats GameStats, remainingWords int) string {
+	l := newLayout(vp)
 	var s strings.Builder
 
 	// === TOP: Status Bar ===
-	statusBar := renderStatusBar(stats, remainingWords)
+	statusBar := renderStatusBar(l, stats, remainingWords)
 	s.WriteString(statusBar)
 	s.WriteString("\n")
 
 	// === MIDDLE: Word List Area ===
-	wordArea := renderWordArea(words, highlightedIndices, input)
+	// status bar 2 + word box frame 8 + input box 4 + hints 1
+	wordArea := renderWordArea(l, l.rows(15, minWordRows), words, highlightedIndices, input)
 	s.WriteString(wordArea)
 	s.WriteString("\n")
 
 	// === BOTTOM: Input Area ===
-	inputArea := renderInputArea(input)
+	inputArea := renderInputArea(l, input)
 	s.WriteString(inputArea)
 	s.WriteString("\n")
 
@@ -293,44 +339,61 @@ func RenderGame(words []WordInfo, highlightedIndices []int, input string, stats
 }
 
 // renderStatusBar renders the top status bar with statistics
-func renderStatusBar(stats GameStats, remainingWords int) string {
+func renderStatusBar(l layout, stats GameStats, remainingWords int) string {
 	// Format each stat with fixed width for alignment
 	timeStr := fmt.Sprintf("Time: %6.1fs", stats.ElapsedSeconds)
 	progressStr := fmt.Sprintf("Progress: %2d/%-2d", stats.WordsCompleted, stats.WordsCompleted+remainingWords)
 	speedStr := fmt.Sprintf("Speed: %5.1f l/s", stats.LettersPerSecond)
 	accuracyStr := fmt.Sprintf("Accuracy: %5.1f%%", stats.AccuracyPercent)
 
-	// Build status line with fixed spacing
-	statusLine := fmt.Sprintf("%s  │  %s  │  %s  │  %s",
-		timeStr, progressStr, speedStr, accuracyStr)
+	// Build status line with fixed spacing; speed and accuracy are left out on narrow terminals
+	statusLine := joinStatus(l, []string{timeStr, progressStr, speedStr, accuracyStr}, 2, 3)
+	if stats.Score != nil {
+		// Speed and accuracy make room for the score on narrow terminals; the results screen shows them all
+		statusLine = joinStatus(l, []string{timeStr, progressStr, speedStr, accuracyStr, renderScore(stats.Score)}, 2, 3)
+	}
 
-	// Apply style and ensure fixed width
+	// Apply style and center in the content width
 	styled := headerStyle.Render(statusLine)
-	return lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(styled)
+	return l.center(styled)
 }
 
-// renderWordArea renders the middle word list area with fixed height
-func renderWordArea(words []WordInfo, highlightedIndices []int, input string) string {
+// joinStatus joins status bar segments; while the line is too wide for the
+// terminal the optional segments are left out, in the given order
+func joinStatus(l layout, segments []string, optional ...int) string {
+	return fitSegments(l.width, headerStyle, segments, optional)
+}
+
+// renderScore formats the word mode score, with the streak multiplier once it applies
+func renderScore(score *ScoreInfo) string {
+	s := fmt.Sprintf("Score: %d", score.Score)
+	if score.Multiplier > 1 {
+		s += fmt.Sprintf(" ×%g", score.Multiplier)
+	}
+	return s
+}
+
+// renderWordArea renders the middle word list area with a fixed height of maxRows rows
+func renderWordArea(l layout, maxRows int, words []WordInfo, highlightedIndices []int, input string) string {
 	if len(words) == 0 {
 		content := statsStyle.Render("All words completed!")
-		return wordBoxStyle.Render(content)
+		return l.box().Render(content)
 	}
 
 	var wordLines []string
 	wordLines = append(wordLines, titleStyle.Render("Words:"))
 	wordLines = append(wordLines, "")
 
-	// Fixed layout: 10 rows maximum
-	const maxRows = 10
-	const wordColumnWidth = 18 // Each word gets 18 chars (word + spacing)
-
 	// Calculate optimal columns based on content width
-	availableWidth := contentWidth - 8 // Reserve space for padding and borders
+	availableWidth := l.inner() // Reserve space for padding and borders
 	wordsPerRow := availableWidth / wordColumnWidth
 	if wordsPerRow < 1 {
 		wordsPerRow = 1
 	}
 
+	// 罗马字模式每行单词上方多一行中日文
+	romanized := len(words[0].Syllables) > 0
+
 	// Calculate max words to display
 	maxWordsToDisplay := maxRows * wordsPerRow
 
@@ -342,12 +405,13 @@ func renderWordArea(words []WordInfo, highlightedIndices []int, input string) st
 
 	rowCount := 0
 	for i := 0; i < displayCount; i += wordsPerRow {
-		var rowWords []string
+		var rowWords, rowTargets []string
 		for j := 0; j < wordsPerRow; j++ {
 			idx := i + j
 			if idx >= displayCount {
 				// Fill empty cells with spaces
 				rowWords = append(rowWords, strings.Repeat(" ", wordColumnWidth))
+				rowTargets = append(rowTargets, strings.Repeat(" ", wordColumnWidth))
 				continue
 			}
 
@@ -364,6 +428,13 @@ func renderWordArea(words []WordInfo, highlightedIndices []int, input string) st
 				}
 			}
 
+			if romanized {
+				target, reading := renderRomanizedWord(wordInfo, isHighlighted, input)
+				rowTargets = append(rowTargets, padToWidth(wordInfo.Target, target, wordColumnWidth))
+				rowWords = append(rowWords, padToWidth(strings.Join(wordInfo.Syllables, " "), reading, wordColumnWidth))
+				continue
+			}
+
 			var renderedWord string
 
 			// Render completed word with animation
@@ -371,11 +442,15 @@ func renderWordArea(words []WordInfo, highlightedIndices []int, input string) st
 				renderedWord = renderCompletedWordAnimation(wordInfo)
 			} else if isHighlighted && len(input) > 0 {
 				// Highlight matched part for active words
-				matchLen := len(input)
-				if matchLen > len(wordInfo.Text) {
-					matchLen = len(wordInfo.Text)
+				matched, rest := matchedPrefix(wordInfo.Text, input)
+				restStyle := wordStyle
+				if wordInfo.Ghosted {
+					restStyle = ghostWordStyle
 				}
-				renderedWord = highlightStyle.Render(wordInfo.Text[:matchLen]) + wordStyle.Render(wordInfo.Text[matchLen:])
+				renderedWord = highlightStyle.Render(matched) + restStyle.Render(rest)
+			} else if wordInfo.Ghosted {
+				// Ghost already took this word
+				renderedWord = ghostWordStyle.Render(wordInfo.Text)
 			} else {
 				// Normal active word
 				renderedWord = wordStyle.Render(wordInfo.Text)
@@ -385,6 +460,9 @@ func renderWordArea(words []WordInfo, highlightedIndices []int, input string) st
 			paddedWord := padToWidth(wordInfo.Text, renderedWord, wordColumnWidth)
 			rowWords = append(rowWords, paddedWord)
 		}
+		if romanized {
+			wordLines = append(wordLines, "  "+strings.Join(rowTargets, ""))
+		}
 		wordLines = append(wordLines, "  "+strings.Join(rowWords, ""))
 		rowCount++
 	}
@@ -393,16 +471,19 @@ func renderWordArea(words []WordInfo, highlightedIndices []int, input string) st
 	for rowCount < maxRows {
 		emptyRow := "  " + strings.Repeat(" ", wordsPerRow*wordColumnWidth)
 		wordLines = append(wordLines, emptyRow)
+		if romanized {
+			wordLines = append(wordLines, emptyRow)
+		}
 		rowCount++
 	}
 
 	content := strings.Join(wordLines, "\n")
-	return wordBoxStyle.Render(content)
+	return l.box().Render(content)
 }
 
-// padToWidth pads a styled string to a specific width based on the plain text length
+// padToWidth pads a styled string to a specific width based on the plain text display width
 func padToWidth(plainText, styledText string, width int) string {
-	plainLen := len(plainText)
+	plainLen := textWidth(plainText)
 	if plainLen >= width {
 		return styledText
 	}
@@ -433,9 +514,9 @@ func renderCompletedWordAnimation(wordInfo WordInfo) string {
 	const animationStart = 150 // when fade animation starts
 
 	var result strings.Builder
-	wordLen := len(wordInfo.Text)
+	wordLen := utf8.RuneCountInString(wordInfo.Text)
 
-	for i, ch := range wordInfo.Text {
+	for i, ch := range []rune(wordInfo.Text) {
 		letterStartTime := animationStart + int64(i*letterFadeTime)
 		letterElapsed := msElapsed - letterStartTime
 
@@ -474,7 +555,7 @@ func renderCompletedWordAnimation(wordInfo WordInfo) string {
 }
 
 // renderInputArea renders the bottom input area
-func renderInputArea(input string) string {
+func renderInputArea(l layout, input string) string {
 	// Input label and value
 	label := statItemStyle.Render("Input: ")
 	value := inputStyle.Render(input)
@@ -483,25 +564,26 @@ func renderInputArea(input string) string {
 	}
 
 	content := label + value
-	return inputBoxStyle.Render(content)
+	return l.inputBox().Render(content)
 }
 
 // RenderPauseMenu renders pause menu with stats and animation
-func RenderPauseMenu(selectedIndex int, stats GameStats, remainingWords int, animFrame int) string {
+func RenderPauseMenu(vp Viewport, selectedIndex int, stats GameStats, remainingWords int, animFrame int) string {
+	l := newLayout(vp)
 	var s strings.Builder
 
 	// === TOP: Status Bar (same as game screen) ===
-	statusBar := renderStatusBar(stats, remainingWords)
+	statusBar := renderStatusBar(l, stats, remainingWords)
 	s.WriteString(statusBar)
 	s.WriteString("\n")
 
 	// === MIDDLE: Pause Animation + Menu ===
-	pauseArea := renderPauseArea(selectedIndex, animFrame)
+	pauseArea := renderPauseArea(l, selectedIndex, animFrame)
 	s.WriteString(pauseArea)
 	s.WriteString("\n")
 
 	// === BOTTOM: Hints ===
-	hints := inputBoxStyle.Render("[↑↓] Select  │  [Enter] Confirm  │  [ESC] Quit Game")
+	hints := l.inputBox().Render("[↑↓] Select  │  [Enter] Confirm  │  [ESC] Quit Game")
 	s.WriteString(hints)
 	s.WriteString("\n")
 
@@ -509,7 +591,7 @@ func RenderPauseMenu(selectedIndex int, stats GameStats, remainingWords int, ani
 }
 
 // renderPauseArea renders the pause menu area with scrolling animation and fixed height
-func renderPauseArea(selectedIndex int, animFrame int) string {
+func renderPauseArea(l layout, selectedIndex int, animFrame int) string {
 	// Scrolling "GAME PAUSED" text
 	pauseText := "    GAME PAUSED    "
 	displayWidth := 20 // width inside the box
@@ -531,12 +613,13 @@ func renderPauseArea(selectedIndex int, animFrame int) string {
 	lines = append(lines, titleStyle.Render("Pause Menu:"))
 	lines = append(lines, "") // Empty line
 
-	// Scrolling text box (5 lines)
-	lines = append(lines, "                         "+titleStyle.Render("╔══════════════════════╗"))
-	lines = append(lines, "                         "+titleStyle.Render("║                      ║"))
-	lines = append(lines, "                         "+titleStyle.Render("║ ")+hintStyle.Render(visibleText)+titleStyle.Render(" ║"))
-	lines = append(lines, "                         "+titleStyle.Render("║                      ║"))
-	lines = append(lines, "                         "+titleStyle.Render("╚══════════════════════╝"))
+	// Scrolling text box (5 lines), centered like the menu below
+	boxIndent := strings.Repeat(" ", 2+(l.inner()-24)/2)
+	lines = append(lines, boxIndent+titleStyle.Render("╔══════════════════════╗"))
+	lines = append(lines, boxIndent+titleStyle.Render("║                      ║"))
+	lines = append(lines, boxIndent+titleStyle.Render("║ ")+hintStyle.Render(visibleText)+titleStyle.Render(" ║"))
+	lines = append(lines, boxIndent+titleStyle.Render("║                      ║"))
+	lines = append(lines, boxIndent+titleStyle.Render("╚══════════════════════╝"))
 
 	lines = append(lines, "") // Empty line after box
 
@@ -564,11 +647,8 @@ func renderPauseArea(selectedIndex int, animFrame int) string {
 			styledText = menuNormalStyle.Render(optionDisplay)
 		}
 
-		// Use lipgloss to center the text within the content width
-		alignedText := lipgloss.NewStyle().
-			Width(contentWidth - 8). // Reserve space for padding and borders
-			Align(lipgloss.Center).
-			Render(styledText)
+		// Center the text within the content width
+		alignedText := l.centerInner(styledText)
 
 		lines = append(lines, "  "+alignedText)
 	}
@@ -578,7 +658,7 @@ func renderPauseArea(selectedIndex int, animFrame int) string {
 		lines = append(lines, "")
 	}
 
-	return wordBoxStyle.Render(strings.Join(lines, "\n"))
+	return l.box().Render(strings.Join(lines, "\n"))
 }
 
 // min returns the minimum of two integers
@@ -609,7 +689,8 @@ func getRandomMenuColor(frame int) lipgloss.Color {
 }
 
 // RenderResults renders game results with consistent layout
-func RenderResults(stats GameStats, aborted bool, selectedOption int, animFrame int) string {
+func RenderResults(vp Viewport, stats GameStats, aborted bool, selectedOption int, animFrame int, record RecordInfo) string {
+	l := newLayout(vp)
 	var s strings.Builder
 
 	// === TOP: Header ===
@@ -620,79 +701,141 @@ func RenderResults(stats GameStats, aborted bool, selectedOption int, animFrame
 		header = "Time:   " + fmt.Sprintf("%6.1fs", stats.ElapsedSeconds) + "  │  Status: Completed! 🎉"
 	}
 	headerStyled := headerStyle.Render(header)
-	s.WriteString(lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(headerStyled))
+	s.WriteString(l.center(headerStyled))
 	s.WriteString("\n")
 
 	// === MIDDLE: Statistics Area ===
-	statsArea := renderResultsArea(stats, aborted, selectedOption, animFrame)
+	statsArea := renderResultsArea(l, stats, aborted, selectedOption, animFrame, record)
 	s.WriteString(statsArea)
 	s.WriteString("\n")
 
 	// === BOTTOM: Hints ===
-	hints := inputBoxStyle.Render("[↑↓] Select  │  [Enter] Confirm  │  [ESC] Exit")
+	hints := l.hints([]string{"[↑↓] Select", "[Enter] Confirm", "[H] Key Heatmap", "[ESC] Exit"}, 1)
 	s.WriteString(hints)
 	s.WriteString("\n")
 
 	return s.String()
 }
 
+// resultsFullHeight is the screen height needed for the full results page;
+// below it the secondary metrics and spacing are dropped
+const resultsFullHeight = 38
+
 // renderResultsArea renders the statistics area
-func renderResultsArea(stats GameStats, aborted bool, selectedOption int, animFrame int) string {
+func renderResultsArea(l layout, stats GameStats, aborted bool, selectedOption int, animFrame int, record RecordInfo) string {
 	var content strings.Builder
+	compact := l.height < resultsFullHeight
+	pad := l.indent() // keep the fixed-width columns centered on wide terminals
+	// and shift them left on narrow ones
+	shift := l.shortfall()
+	col := 50 - shift
+	separator := separatorStyle.Render(strings.Repeat("━", 69-shift))
 
 	// Title
 	if aborted {
-		content.WriteString(fmt.Sprintf("%58s\n", titleStyle.Render("GAME OVER")))
+		content.WriteString(pad + fmt.Sprintf("%*s\n", 58-shift, titleStyle.Render("GAME OVER")))
 	} else {
-		content.WriteString(fmt.Sprintf("%70s\n", titleStyle.Render("CONGRATULATIONS")))
+		content.WriteString(pad + fmt.Sprintf("%*s\n", 70-shift, titleStyle.Render("CONGRATULATIONS")))
+	}
+	if line := renderRecordLine(l, record, animFrame); line != "" {
+		content.WriteString(line + "\n")
+	}
+	content.WriteString(pad + "    " + separator + "\n")
+
+	if !compact {
+		// Statistics section
+		content.WriteString(pad + fmt.Sprintf("%*s\n", 51-shift, titleStyle.Render("Performance Metrics:")))
+
+		// Keystroke stats
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Total Keystrokes:"),
+			statValueStyle.Render(fmt.Sprintf("%7d", stats.TotalKeystrokes))))
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Valid Keystrokes:"),
+			statValueStyle.Render(fmt.Sprintf("%7d", stats.ValidKeystrokes))))
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Correct Chars:"),
+			statValueStyle.Render(fmt.Sprintf("%7d", stats.CorrectChars))))
 	}
-	content.WriteString("    " + separatorStyle.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━") + "\n")
-
-	// Statistics section
-	content.WriteString(fmt.Sprintf("%51s\n", titleStyle.Render("Performance Metrics:")))
-
-	// Keystroke stats
-	content.WriteString(fmt.Sprintf("%50s %s\n",
-		statItemStyle.Render("Total Keystrokes:"),
-		statValueStyle.Render(fmt.Sprintf("%7d", stats.TotalKeystrokes))))
-	content.WriteString(fmt.Sprintf("%50s %s\n",
-		statItemStyle.Render("Valid Keystrokes:"),
-		statValueStyle.Render(fmt.Sprintf("%7d", stats.ValidKeystrokes))))
-	content.WriteString(fmt.Sprintf("%50s %s\n",
-		statItemStyle.Render("Correct Chars:"),
-		statValueStyle.Render(fmt.Sprintf("%7d", stats.CorrectChars))))
 
 	// Word stats
-	content.WriteString(fmt.Sprintf("%50s %s\n",
+	content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
 		statItemStyle.Render("Completed Words:"),
 		statValueStyle.Render(fmt.Sprintf("%7d", stats.WordsCompleted))))
-	content.WriteString(fmt.Sprintf("%50s %s\n",
-		statItemStyle.Render("Total Letters:"),
-		statValueStyle.Render(fmt.Sprintf("%7d", stats.TotalLetters))))
+	if !compact {
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Total Letters:"),
+			statValueStyle.Render(fmt.Sprintf("%7d", stats.TotalLetters))))
+	}
 
 	// Speed and accuracy (highlighted)
-	content.WriteString(fmt.Sprintf("%50s %s\n",
-		statItemStyle.Render("Letters/second:"),
-		statValueStyle.Render(fmt.Sprintf("%7.2f", stats.LettersPerSecond))))
-	content.WriteString(fmt.Sprintf("%50s %s\n",
-		statItemStyle.Render("Words/second:"),
-		statValueStyle.Render(fmt.Sprintf("%7.2f", stats.WordsPerSecond))))
-	content.WriteString(fmt.Sprintf("%50s %s\n",
+	if !compact {
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Letters/second:"),
+			statValueStyle.Render(fmt.Sprintf("%7.2f", stats.LettersPerSecond))))
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Words/second:"),
+			statValueStyle.Render(fmt.Sprintf("%7.2f", stats.WordsPerSecond))))
+	}
+	content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+		statItemStyle.Render("Net WPM:"),
+		statValueStyle.Render(fmt.Sprintf("%7.1f", stats.NetWPM))))
+	if !compact {
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Raw WPM:"),
+			statValueStyle.Render(fmt.Sprintf("%7.1f", stats.RawWPM))))
+	}
+	content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
 		statItemStyle.Render("Accuracy:"),
 		statValueStyle.Render(fmt.Sprintf("%6.2f%%", stats.AccuracyPercent))))
+	if !compact {
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Consistency:"),
+			statValueStyle.Render(fmt.Sprintf("%6.1f%%", stats.Consistency))))
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Errors (fixed/left):"),
+			statValueStyle.Render(fmt.Sprintf("%3d/%-3d", stats.CorrectedErrors, stats.UncorrectedErrors))))
+	}
+
+	// Word mode score
+	if score := stats.Score; score != nil {
+		content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+			statItemStyle.Render("Score:"),
+			statValueStyle.Render(fmt.Sprintf("%7d", score.Score))))
+		if !compact {
+			content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+				statItemStyle.Render("Best Streak:"),
+				statValueStyle.Render(fmt.Sprintf("%7d", score.MaxStreak))))
+			content.WriteString(pad + fmt.Sprintf("%*s %s\n", col,
+				statItemStyle.Render("Wrong Enters:"),
+				statValueStyle.Render(fmt.Sprintf("%7d", score.Penalties))))
+		}
+	}
 
 	// Add separator before menu
-	content.WriteString("\n")
-	content.WriteString("    " + separatorStyle.Render("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━") + "\n")
+	if !compact {
+		content.WriteString("\n")
+	}
+	content.WriteString(pad + "    " + separator + "\n")
 
 	// Menu options - similar to pause menu
-	content.WriteString("\n")
-	options := []string{"Restart", "Select Mode", "Main Menu"}
+	if !compact {
+		content.WriteString("\n")
+	}
+	options := []string{"Restart", "Watch Replay", "Select Mode", "Main Menu"}
+	content.WriteString(renderResultsMenu(l, options, selectedOption, animFrame))
+
+	return l.box().Render(content.String())
+}
+
+// renderResultsMenu renders the centered results menu options
+func renderResultsMenu(l layout, options []string, selectedOption int, animFrame int) string {
 	// Use random color for selected option
 	selectedStyle := lipgloss.NewStyle().
 		Foreground(getRandomMenuColor(animFrame)).
 		Bold(true)
 
+	lines := make([]string, len(options))
 	for i, opt := range options {
 		// Build the option text with indicator
 		var optionDisplay string
@@ -710,34 +853,75 @@ func renderResultsArea(stats GameStats, aborted bool, selectedOption int, animFr
 			styledText = menuNormalStyle.Render(optionDisplay)
 		}
 
-		// Use lipgloss to center the text within the content width
-		alignedText := lipgloss.NewStyle().
-			Width(contentWidth - 8). // Reserve space for padding and borders
-			Align(lipgloss.Center).
-			Render(styledText)
+		// Center the text within the content width
+		lines[i] = "  " + l.centerInner(styledText)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderRecordLine renders the "new record" banner or the leaderboard rank
+func renderRecordLine(l layout, record RecordInfo, animFrame int) string {
+	if record.Rank == 0 {
+		return ""
+	}
+
+	var text string
+	var style lipgloss.Style
+	if record.IsNewRecord {
+		text = fmt.Sprintf("★ NEW RECORD! ★  %s", formatRecordValue(record))
+		style = lipgloss.NewStyle().Foreground(getRandomMenuColor(animFrame)).Bold(true)
+	} else {
+		text = fmt.Sprintf("Rank #%d  │  %s", record.Rank, formatRecordValue(record))
+		style = statValueStyle
+	}
+
+	return lipgloss.PlaceHorizontal(l.width-4, lipgloss.Center, style.Render(text))
+}
 
-		content.WriteString("  " + alignedText + "\n")
+// formatRecordValue formats the ranking value with its unit
+func formatRecordValue(record RecordInfo) string {
+	switch record.Unit {
+	case "s":
+		return fmt.Sprintf("%.3fs", record.Value)
+	case "l/s":
+		return fmt.Sprintf("%.2f l/s", record.Value)
+	default:
+		return fmt.Sprintf("%.0f %s", record.Value, record.Unit)
 	}
+}
 
-	return wordBoxStyle.Render(content.String())
+// PackPicker 模式选择界面的词库包选择器
+type PackPicker struct {
+	Name     string // 当前词库包的名称
+	Words    int    // 单词数（0 表示不显示）
+	Language string
 }
 
 // RenderModeSelection renders the mode selection screen with unified style
-func RenderModeSelection(selectedMode int, animFrame int) string {
+// notice 非空时在提示栏中代替按键说明显示（如复习模式没有到期的单词）
+// pack 为 nil 时不显示词库包选择器（词库包目录中没有词库包）
+func RenderModeSelection(vp Viewport, selectedMode int, animFrame int, notice string, pack *PackPicker) string {
+	l := newLayout(vp)
 	var s strings.Builder
 
 	// TOP: Header
 	header := headerStyle.Render("Select Game Mode")
-	s.WriteString(lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(header))
+	s.WriteString(l.center(header))
 	s.WriteString("\n")
 
 	// MIDDLE: Content (mode options)
-	content := renderModeSelectionContent(selectedMode, animFrame)
+	content := renderModeSelectionContent(l, selectedMode, animFrame, pack)
 	s.WriteString(content)
 	s.WriteString("\n")
 
 	// BOTTOM: Hints
-	hints := inputBoxStyle.Render("[↑↓] Select  │  [Enter] Confirm  │  [ESC] Back")
+	hints := l.inputBox().Render("[↑↓] Select  │  [Enter] Confirm  │  [ESC] Back")
+	if pack != nil {
+		hints = l.hints([]string{"[↑↓] Select", "[←→] Pack", "[Enter] Confirm", "[ESC] Back"}, 2)
+	}
+	if notice != "" {
+		hints = l.inputBox().Render(statValueStyle.Render(notice))
+	}
 	s.WriteString(hints)
 	s.WriteString("\n")
 
@@ -745,12 +929,12 @@ func RenderModeSelection(selectedMode int, animFrame int) string {
 }
 
 // renderModeSelectionContent renders the mode selection content area
-func renderModeSelectionContent(selectedMode int, animFrame int) string {
+func renderModeSelectionContent(l layout, selectedMode int, animFrame int, pack *PackPicker) string {
 	var lines []string
 
 	lines = append(lines, "")
 
-	// Menu options - 现在有7个模式
+	// Menu options - 现在有10个模式
 	options := []string{
 		"Classic Mode",
 		"Sentence Mode",
@@ -759,6 +943,9 @@ func renderModeSelectionContent(selectedMode int, animFrame int) string {
 		"Rhythm Master",
 		"Underwater Countdown",
 		"Rhythm Dance",
+		"Adaptive Practice",
+		"Spaced Review",
+		"CJK Romanization",
 	}
 	selectedStyle := lipgloss.NewStyle().Foreground(getRandomMenuColor(animFrame)).Bold(true)
 
@@ -777,43 +964,63 @@ func renderModeSelectionContent(selectedMode int, animFrame int) string {
 			styledText = menuNormalStyle.Render(optionDisplay)
 		}
 
-		alignedText := lipgloss.NewStyle().
-			Width(contentWidth - 8).
-			Align(lipgloss.Center).
-			Render(styledText)
+		alignedText := l.centerInner(styledText)
 
 		lines = append(lines, "  "+alignedText)
 	}
 
+	// 词库包选择器
+	if pack != nil {
+		lines = append(lines, "", "  "+l.centerInner(renderPackPicker(pack)))
+	}
+
 	// Fill to fixed height (14 lines to accommodate 5 options)
 	for len(lines) < 14 {
 		lines = append(lines, "")
 	}
 
-	return wordBoxStyle.Render(strings.Join(lines, "\n"))
+	// header 2 + box frame 6 + hints 4
+	return l.box().Render(strings.Join(squeeze(lines, l.height-12), "\n"))
+}
+
+// renderPackPicker 渲染词库包选择器：◀ 名称 ▶ · 单词数 · 语言
+func renderPackPicker(pack *PackPicker) string {
+	s := statItemStyle.Render("Pack ") + statValueStyle.Render("◀ "+pack.Name+" ▶")
+	var details []string
+	if pack.Words > 0 {
+		details = append(details, fmt.Sprintf("%d words", pack.Words))
+	}
+	if pack.Language != "" {
+		details = append(details, pack.Language)
+	}
+	if len(details) > 0 {
+		s += hintStyle.Render(" · " + strings.Join(details, " · "))
+	}
+	return s
 }
 
 // RenderSentenceGame renders the sentence typing game screen
-func RenderSentenceGame(targetSentence string, userInput string, stats GameStats) string {
+func RenderSentenceGame(vp Viewport, targetSentence string, userInput string, stats GameStats) string {
+	l := newLayout(vp)
 	var s strings.Builder
 
 	// === TOP: Status Bar ===
 	timeStr := fmt.Sprintf("Time: %6.1fs", stats.ElapsedSeconds)
-	progressStr := fmt.Sprintf("Progress: %2d/%2d", len(userInput), len(targetSentence))
+	progressStr := fmt.Sprintf("Progress: %2d/%2d", utf8.RuneCountInString(userInput), utf8.RuneCountInString(targetSentence))
 	accuracyStr := fmt.Sprintf("Accuracy: %5.1f%%", stats.AccuracyPercent)
 
 	statusLine := fmt.Sprintf("%s  │  %s  │  %s", timeStr, progressStr, accuracyStr)
 	statusStyled := headerStyle.Render(statusLine)
-	s.WriteString(lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(statusStyled))
+	s.WriteString(l.center(statusStyled))
 	s.WriteString("\n\n")
 
 	// === MIDDLE: Sentence Display Area ===
-	sentenceArea := renderSentenceArea(targetSentence, userInput)
+	sentenceArea := renderSentenceArea(l, targetSentence, userInput)
 	s.WriteString(sentenceArea)
 	s.WriteString("\n")
 
 	// === BOTTOM: Stats and Hints ===
-	detailedStats := renderSentenceStats(stats, len(targetSentence))
+	detailedStats := renderSentenceStats(l, stats, utf8.RuneCountInString(targetSentence))
 	s.WriteString(detailedStats)
 	s.WriteString("\n")
 
@@ -824,7 +1031,7 @@ func RenderSentenceGame(targetSentence string, userInput string, stats GameStats
 }
 
 // renderSentenceArea renders the target sentence and user input with color coding
-func renderSentenceArea(targetSentence string, userInput string) string {
+func renderSentenceArea(l layout, targetSentence string, userInput string) string {
 	var content strings.Builder
 
 	content.WriteString(titleStyle.Render("Target:") + "\n")
@@ -833,10 +1040,11 @@ func renderSentenceArea(targetSentence string, userInput string) string {
 	content.WriteString(titleStyle.Render("Your Input:") + "\n")
 	content.WriteString("  ")
 
-	// Render each character with color coding
-	for i, targetChar := range targetSentence {
-		if i < len(userInput) {
-			userChar := rune(userInput[i])
+	// Render each character with color coding（按字符而不是字节对齐）
+	typed := []rune(userInput)
+	for i, targetChar := range []rune(targetSentence) {
+		if i < len(typed) {
+			userChar := typed[i]
 			if userChar == targetChar {
 				// Correct character - green
 				content.WriteString(highlightStyle.Render(string(userChar)))
@@ -855,11 +1063,11 @@ func renderSentenceArea(targetSentence string, userInput string) string {
 		}
 	}
 
-	return wordBoxStyle.Render(content.String())
+	return l.box().Render(content.String())
 }
 
 // renderSentenceStats renders detailed statistics for sentence mode
-func renderSentenceStats(stats GameStats, totalChars int) string {
+func renderSentenceStats(l layout, stats GameStats, totalChars int) string {
 	statsLine := fmt.Sprintf("Characters: %d/%d  │  Correct: %d  │  Speed: %.1f chars/s",
 		stats.TotalKeystrokes,
 		totalChars,
@@ -867,11 +1075,12 @@ func renderSentenceStats(stats GameStats, totalChars int) string {
 		stats.LettersPerSecond)
 
 	content := statsStyle.Render(statsLine)
-	return inputBoxStyle.Render(content)
+	return l.inputBox().Render(content)
 }
 
 // UpdateWelcomeAnimation updates the welcome screen animation state
-func UpdateWelcomeAnimation(state *WelcomeAnimationState) {
+func UpdateWelcomeAnimation(vp Viewport, state *WelcomeAnimationState) {
+	l := newLayout(vp)
 	state.Frame++
 
 	// Trigger bullet if not active and explosion not triggered
@@ -889,7 +1098,7 @@ func UpdateWelcomeAnimation(state *WelcomeAnimationState) {
 		// Calculate tagline starting position
 		// Tagline "Low-key but never simple" is 24 chars, right-aligned
 		taglineText := "Low-key but never simple"
-		taglineStartX := (contentWidth - 8) - len(taglineText)
+		taglineStartX := l.inner() - len(taglineText)
 
 		// Check if bullet hits the tagline at line 10
 		if state.BulletRow == 10 && state.BulletX >= taglineStartX {
@@ -901,7 +1110,7 @@ func UpdateWelcomeAnimation(state *WelcomeAnimationState) {
 		}
 
 		// If bullet goes past content width without hitting, reset
-		if state.BulletX >= (contentWidth - 8) {
+		if state.BulletX >= l.inner() {
 			state.BulletActive = false
 			state.ExplosionTriggered = false
 		}
@@ -976,8 +1185,9 @@ func renderTaglineExplosion(taglineInfo TaglineInfo) string {
 }
 
 // RenderCountdownGame 渲染倒计时模式游戏界面
-func RenderCountdownGame(words []WordInfo, highlightedIndices []int, input string, stats GameStats,
+func RenderCountdownGame(vp Viewport, words []WordInfo, highlightedIndices []int, input string, stats GameStats,
 	timeRemaining float64, totalDuration float64) string {
+	l := newLayout(vp)
 	var s strings.Builder
 
 	// === 顶部：倒计时器（大号显示）===
@@ -1003,29 +1213,67 @@ func RenderCountdownGame(words []WordInfo, highlightedIndices []int, input strin
 	speedStr := fmt.Sprintf("Speed: %.1f letters/s", stats.LettersPerSecond)
 
 	statusLine := fmt.Sprintf("%s  │  %s  │  %s", timerRendered, progressStr, speedStr)
+	if stats.Score != nil {
+		statusLine = joinStatus(l, []string{timerRendered, progressStr, speedStr, renderScore(stats.Score)}, 2)
+	}
 	statusStyled := headerStyle.Render(statusLine)
-	s.WriteString(lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(statusStyled))
+	s.WriteString(l.center(statusStyled))
+	s.WriteString("\n")
+
+	// === 中部：单词区域（复用现有渲染）===
+	wordArea := renderWordArea(l, l.rows(15, minWordRows), words, highlightedIndices, input)
+	s.WriteString(wordArea)
+	s.WriteString("\n")
+
+	// === 底部：输入区域 ===
+	inputArea := renderInputArea(l, input)
+	s.WriteString(inputArea)
+	s.WriteString("\n")
+
+	s.WriteString(l.hintLine([]string{"[ESC] Pause", "Eliminate as many words as possible before time runs out!"}, 1))
+	s.WriteString("\n")
+
+	return s.String()
+}
+
+// RenderPracticeGame 渲染自适应练习模式游戏界面，focus 为当前这组单词针对的弱项
+func RenderPracticeGame(vp Viewport, words []WordInfo, highlightedIndices []int, input string, stats GameStats,
+	targetWords int, focus string) string {
+	l := newLayout(vp)
+	var s strings.Builder
+
+	// === 顶部：进度和速度 ===
+	progressStr := fmt.Sprintf("Progress: %2d/%-2d", stats.WordsCompleted, targetWords)
+	speedStr := fmt.Sprintf("WPM: %5.1f", stats.NetWPM)
+	accuracyStr := fmt.Sprintf("Accuracy: %5.1f%%", stats.AccuracyPercent)
+	statusLine := joinStatus(l, []string{progressStr, speedStr, accuracyStr})
+	s.WriteString(l.center(headerStyle.Render(statusLine)))
 	s.WriteString("\n")
 
 	// === 中部：单词区域（复用现有渲染）===
-	wordArea := renderWordArea(words, highlightedIndices, input)
+	wordArea := renderWordArea(l, l.rows(15, minWordRows), words, highlightedIndices, input)
 	s.WriteString(wordArea)
 	s.WriteString("\n")
 
 	// === 底部：输入区域 ===
-	inputArea := renderInputArea(input)
+	inputArea := renderInputArea(l, input)
 	s.WriteString(inputArea)
 	s.WriteString("\n")
 
-	s.WriteString(hintStyle.Render("  [ESC] Pause  │  Eliminate as many words as possible before time runs out!"))
+	// 提示行显示本组单词针对的弱项
+	if focus == "" {
+		focus = "not enough key data yet"
+	}
+	s.WriteString(l.hintLine([]string{"[ESC] Pause", "Focus: " + focus}, 1))
 	s.WriteString("\n")
 
 	return s.String()
 }
 
 // RenderSpeedRunGame 渲染极速模式游戏界面
-func RenderSpeedRunGame(words []WordInfo, highlightedIndices []int, input string, stats GameStats,
-	currentTime float64, bestTime float64) string {
+func RenderSpeedRunGame(vp Viewport, words []WordInfo, highlightedIndices []int, input string, stats GameStats,
+	currentTime float64, bestTime float64, ghost GhostInfo) string {
+	l := newLayout(vp)
 	var s strings.Builder
 
 	// === 顶部：毫秒级计时器 ===
@@ -1061,17 +1309,28 @@ func RenderSpeedRunGame(words []WordInfo, highlightedIndices []int, input string
 	}
 
 	statusLine := fmt.Sprintf("%s  │  %s  │  %s", timerRendered, progressStr, bestDisplay)
+	if stats.Score != nil {
+		statusLine = joinStatus(l, []string{timerRendered, progressStr, bestDisplay, renderScore(stats.Score)}, 2)
+	}
 	statusStyled := headerStyle.Render(statusLine)
-	s.WriteString(lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(statusStyled))
+	s.WriteString(l.center(statusStyled))
 	s.WriteString("\n")
 
+	// === 幽灵赛跑进度 ===
+	overhead := 16 // 状态栏 2 + 单词框 8 + 输入框 4 + 速度 1 + 提示 1
+	if ghost.Active {
+		s.WriteString(renderGhostLine(l, ghost, stats.WordsCompleted, stats.WordsCompleted+remainingWords))
+		s.WriteString("\n")
+		overhead++
+	}
+
 	// === 中部：单词区域 ===
-	wordArea := renderWordArea(words, highlightedIndices, input)
+	wordArea := renderWordArea(l, l.rows(overhead, minWordRows), words, highlightedIndices, input)
 	s.WriteString(wordArea)
 	s.WriteString("\n")
 
 	// === 底部：输入 + 速度指标 ===
-	inputArea := renderInputArea(input)
+	inputArea := renderInputArea(l, input)
 	s.WriteString(inputArea)
 	s.WriteString("\n")
 
@@ -1081,15 +1340,41 @@ func RenderSpeedRunGame(words []WordInfo, highlightedIndices []int, input string
 	s.WriteString(speedStyle.Render("  " + speedIndicator))
 	s.WriteString("\n")
 
-	s.WriteString(hintStyle.Render("  [ESC] Pause  │  Complete all words as fast as possible!"))
+	s.WriteString(l.hintLine([]string{"[ESC] Pause", "Complete all words as fast as possible!"}, 1))
 	s.WriteString("\n")
 
 	return s.String()
 }
 
+// renderGhostLine 渲染与幽灵对手的对比：双方进度和领先/落后单词数
+func renderGhostLine(l layout, ghost GhostInfo, playerWords int, total int) string {
+	ghostProgress := fmt.Sprintf("👻 Ghost: %d/%d", ghost.Words, total)
+	if ghost.Finished {
+		ghostProgress = fmt.Sprintf("👻 Ghost finished in %.3fs", ghost.FinishTime)
+	}
+
+	var lead string
+	var leadStyle lipgloss.Style
+	switch diff := playerWords - ghost.Words; {
+	case diff > 0:
+		lead = fmt.Sprintf("▲ %d ahead", diff)
+		leadStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("46")).Bold(true)
+	case diff < 0:
+		lead = fmt.Sprintf("▼ %d behind", -diff)
+		leadStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+	default:
+		lead = "= tied"
+		leadStyle = statsStyle
+	}
+
+	line := ghostWordStyle.Render(ghostProgress) + "  │  " + leadStyle.Render(lead)
+	return l.center(line)
+}
+
 // RenderRhythmMasterGame 渲染节奏大师模式游戏界面
-func RenderRhythmMasterGame(words []WordInfo, highlightedIndices []int, input string, stats GameStats,
+func RenderRhythmMasterGame(vp Viewport, words []WordInfo, highlightedIndices []int, input string, stats GameStats,
 	wordTimeRemaining float64, wordTimeLimit float64, combo int, level int) string {
+	l := newLayout(vp)
 	var s strings.Builder
 
 	// === 顶部：连击和等级显示 ===
@@ -1103,47 +1388,45 @@ func RenderRhythmMasterGame(words []WordInfo, highlightedIndices []int, input st
 
 	statusLine := fmt.Sprintf("%s  │  %s  │  %s", comboStyle.Render(comboDisplay), levelDisplay, speedDisplay)
 	statusStyled := headerStyle.Render(statusLine)
-	s.WriteString(lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(statusStyled))
+	s.WriteString(l.center(statusStyled))
 	s.WriteString("\n")
 
 	// === 中部：带进度条的单词区域 ===
-	wordArea := renderRhythmWordArea(words, highlightedIndices, input, wordTimeRemaining, wordTimeLimit)
+	// 状态栏 2 + 单词框 8 + 进度条 1 + 时间限制 4 + 输入框 4 + 提示 1
+	wordArea := renderRhythmWordArea(l, l.rows(20, minWordRows), words, highlightedIndices, input, wordTimeRemaining, wordTimeLimit)
 	s.WriteString(wordArea)
 	s.WriteString("\n")
 
 	// === 时间限制显示 ===
 	timeLimitInfo := fmt.Sprintf("Time Limit per Word: %.1fs", wordTimeLimit)
 	timeLimitStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("117"))
-	s.WriteString(inputBoxStyle.Render(timeLimitStyle.Render(timeLimitInfo)))
+	s.WriteString(l.inputBox().Render(timeLimitStyle.Render(timeLimitInfo)))
 	s.WriteString("\n")
 
 	// === 底部：输入区域 ===
-	inputArea := renderInputArea(input)
+	inputArea := renderInputArea(l, input)
 	s.WriteString(inputArea)
 	s.WriteString("\n")
 
-	s.WriteString(hintStyle.Render("  [ESC] Pause  │  Complete each word within the time limit!"))
+	s.WriteString(l.hintLine([]string{"[ESC] Pause", "Complete each word within the time limit!"}, 1))
 	s.WriteString("\n")
 
 	return s.String()
 }
 
 // renderRhythmWordArea 渲染带进度条的单词区域（节奏大师专用）
-func renderRhythmWordArea(words []WordInfo, highlightedIndices []int, input string,
+func renderRhythmWordArea(l layout, maxRows int, words []WordInfo, highlightedIndices []int, input string,
 	wordTimeRemaining float64, wordTimeLimit float64) string {
 	if len(words) == 0 {
 		content := statsStyle.Render("All words completed!")
-		return wordBoxStyle.Render(content)
+		return l.box().Render(content)
 	}
 
 	var wordLines []string
 	wordLines = append(wordLines, titleStyle.Render("Words:"))
 	wordLines = append(wordLines, "")
 
-	const maxRows = 10
-	const wordColumnWidth = 18
-
-	availableWidth := contentWidth - 8
+	availableWidth := l.inner()
 	wordsPerRow := availableWidth / wordColumnWidth
 	if wordsPerRow < 1 {
 		wordsPerRow = 1
@@ -1193,12 +1476,9 @@ func renderRhythmWordArea(words []WordInfo, highlightedIndices []int, input stri
 			} else if idx == firstActiveIdx {
 				// 当前活动单词 - 显示进度条
 				if isHighlighted && len(input) > 0 {
-					matchLen := len(input)
-					if matchLen > len(wordInfo.Text) {
-						matchLen = len(wordInfo.Text)
-					}
-					renderedWord = highlightStyle.Render(wordInfo.Text[:matchLen]) +
-						wordStyle.Render(wordInfo.Text[matchLen:])
+					matched, rest := matchedPrefix(wordInfo.Text, input)
+					renderedWord = highlightStyle.Render(matched) +
+						wordStyle.Render(rest)
 				} else {
 					renderedWord = wordStyle.Render(wordInfo.Text)
 				}
@@ -1227,12 +1507,9 @@ func renderRhythmWordArea(words []WordInfo, highlightedIndices []int, input stri
 				renderedWord = renderedWord + "\n  " + bar
 			} else if isHighlighted && len(input) > 0 {
 				// 高亮匹配的单词
-				matchLen := len(input)
-				if matchLen > len(wordInfo.Text) {
-					matchLen = len(wordInfo.Text)
-				}
-				renderedWord = highlightStyle.Render(wordInfo.Text[:matchLen]) +
-					wordStyle.Render(wordInfo.Text[matchLen:])
+				matched, rest := matchedPrefix(wordInfo.Text, input)
+				renderedWord = highlightStyle.Render(matched) +
+					wordStyle.Render(rest)
 			} else {
 				// 普通未完成单词
 				renderedWord = wordStyle.Render(wordInfo.Text)
@@ -1253,25 +1530,26 @@ func renderRhythmWordArea(words []WordInfo, highlightedIndices []int, input stri
 	}
 
 	content := strings.Join(wordLines, "\n")
-	return wordBoxStyle.Render(content)
+	return l.box().Render(content)
 }
 
 // RenderAbout renders the about page with game information
-func RenderAbout() string {
+func RenderAbout(vp Viewport) string {
+	l := newLayout(vp)
 	var s strings.Builder
 
 	// TOP: Header
 	header := headerStyle.Render("About Word Killer")
-	s.WriteString(lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(header))
+	s.WriteString(l.center(header))
 	s.WriteString("\n")
 
 	// MIDDLE: Content
-	content := renderAboutContent()
+	content := renderAboutContent(l)
 	s.WriteString(content)
 	s.WriteString("\n")
 
 	// BOTTOM: Hints
-	hints := inputBoxStyle.Render("[ESC] Back to Main Menu")
+	hints := l.inputBox().Render("[ESC] Back to Main Menu")
 	s.WriteString(hints)
 	s.WriteString("\n")
 
@@ -1279,19 +1557,19 @@ func RenderAbout() string {
 }
 
 // renderAboutContent renders the about page content
-func renderAboutContent() string {
+func renderAboutContent(l layout) string {
 	var lines []string
 
 	lines = append(lines, "")
 	lines = append(lines, titleStyle.Render("  Game Modes:"))
 	lines = append(lines, "")
 	lines = append(lines, "    "+statsStyle.Render("• Classic Mode")+" - Type and eliminate falling words")
-	lines = append(lines, "    "+statsStyle.Render("• Sentence Mode")+" - Type complete sentences accurately")
+	lines = append(lines, "    "+statsStyle.Render("• Sentence Mode")+" - Type complete sentences")
 	lines = append(lines, "")
 	lines = append(lines, titleStyle.Render("  Features:"))
 	lines = append(lines, "")
-	lines = append(lines, "    "+statsStyle.Render("• Real-time statistics")+" - Track your speed and accuracy")
-	lines = append(lines, "    "+statsStyle.Render("• Multiple difficulty levels")+" - Short, medium, and long words")
+	lines = append(lines, "    "+statsStyle.Render("• Real-time statistics")+" - Track speed and accuracy")
+	lines = append(lines, "    "+statsStyle.Render("• Difficulty levels")+" - Short, medium and long words")
 	lines = append(lines, "")
 	lines = append(lines, titleStyle.Render("  License:"))
 	lines = append(lines, "")
@@ -1303,5 +1581,6 @@ func renderAboutContent() string {
 		lines = append(lines, "")
 	}
 
-	return wordBoxStyle.Render(strings.Join(lines, "\n"))
+	// header 2 + box frame 6 + hints 4
+	return l.box().Render(strings.Join(squeeze(lines, l.height-12), "\n"))
 }
@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// textWidth 文本在终端中占的列数（按字符而不是字节计算，中日韩等宽字符占两列）
+func textWidth(s string) int {
+	return runewidth.StringWidth(s)
+}
+
+// splitRunes 在第 n 个字符处把字符串分成两段（n 超过字符数时第二段为空）
+func splitRunes(s string, n int) (string, string) {
+	for i := range s {
+		if n <= 0 {
+			return s[:i], s[i:]
+		}
+		n--
+	}
+	return s, ""
+}
+
+// matchedPrefix 单词中与输入对应的前缀和剩余部分（按输入的字符数划分）
+func matchedPrefix(word, input string) (string, string) {
+	return splitRunes(word, utf8.RuneCountInString(input))
+}